@@ -0,0 +1,127 @@
+package v1
+
+import (
+	"context"
+	"microblog/domain/follow/domain"
+	"microblog/domain/shared/page"
+	"microblog/domain/shared/response"
+	"microblog/infrastructure/auth"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+)
+
+// FollowRouter is the router of the follow graph. The follows go through
+// Service, which checks and publishes them.
+type FollowRouter struct {
+	Repository domain.Repository
+	Service    *domain.Service
+}
+
+// ConnectionPage is a page of the users on one side of the follow graph
+// along with the cursors of its neighbours.
+type ConnectionPage struct {
+	Items []domain.Connection `json:"items"`
+	page.Info
+}
+
+// userID reads the id URL param of the request.
+func userID(r *http.Request) (uint, error) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		return 0, err
+	}
+
+	return uint(id), nil
+}
+
+// FollowHandler makes the authenticated user follow the user by id.
+func (fr *FollowRouter) FollowHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := userID(r)
+	if err != nil {
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	principal, ok := auth.FromContext(r.Context())
+	if !ok {
+		response.HTTPError(w, r, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	follow := domain.Follow{FollowerID: principal.UserID, FollowingID: id}
+	err = fr.Service.Follow(r.Context(), &follow)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, r, http.StatusNoContent, nil)
+}
+
+// UnfollowHandler makes the authenticated user stop following the user by id.
+func (fr *FollowRouter) UnfollowHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := userID(r)
+	if err != nil {
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	principal, ok := auth.FromContext(r.Context())
+	if !ok {
+		response.HTTPError(w, r, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	ctx := r.Context()
+	err = fr.Repository.Unfollow(ctx, principal.UserID, id)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, r, http.StatusNoContent, nil)
+}
+
+// GetFollowersHandler response a page of the users that follow the user by
+// id, most recent follow first.
+func (fr *FollowRouter) GetFollowersHandler(w http.ResponseWriter, r *http.Request) {
+	fr.list(w, r, fr.Repository.GetFollowers)
+}
+
+// GetFollowingHandler response a page of the users followed by the user by
+// id, most recent follow first.
+func (fr *FollowRouter) GetFollowingHandler(w http.ResponseWriter, r *http.Request) {
+	fr.list(w, r, fr.Repository.GetFollowing)
+}
+
+// list responds the page of connections returned by fetch for the user by
+// id.
+func (fr *FollowRouter) list(w http.ResponseWriter, r *http.Request,
+	fetch func(ctx context.Context, userID uint, p page.Request) ([]domain.Connection, page.Info, error)) {
+	id, err := userID(r)
+	if err != nil {
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	p, err := page.FromQuery(r.URL.Query())
+	if err != nil {
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	connections, info, err := fetch(ctx, id, p)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	if connections == nil {
+		connections = []domain.Connection{}
+	}
+
+	response.JSON(w, r, http.StatusOK, ConnectionPage{Items: connections, Info: info})
+}
@@ -0,0 +1,184 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"microblog/domain/follow/domain"
+	mockLocal "microblog/domain/follow/domain/mocks"
+	"microblog/domain/shared/errs"
+	"microblog/domain/shared/page"
+	userDomain "microblog/domain/user/domain"
+	mockUser "microblog/domain/user/domain/mocks"
+	"microblog/infrastructure/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newRequest returns a request for the user id URL param, authenticated as
+// principalID unless it is zero.
+func newRequest(method string, id string, principalID uint) *http.Request {
+	request := httptest.NewRequest(method, "/api/v1/users/{id}/follow", nil)
+
+	requestCtx := chi.NewRouteContext()
+	requestCtx.URLParams.Add("id", id)
+	ctx := context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx)
+
+	if principalID != 0 {
+		ctx = auth.NewContext(ctx, auth.Principal{UserID: principalID})
+	}
+
+	return request.WithContext(ctx)
+}
+
+func TestFollowRouter_FollowHandler(t *testing.T) {
+
+	t.Run("Error Param Follow Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testFollowHandler := &FollowRouter{Repository: mockRepository, Service: domain.NewService(mockRepository)}
+
+		testFollowHandler.FollowHandler(response, newRequest(http.MethodPut, "abc", 1))
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Error Unauthenticated Follow Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testFollowHandler := &FollowRouter{Repository: mockRepository, Service: domain.NewService(mockRepository)}
+
+		testFollowHandler.FollowHandler(response, newRequest(http.MethodPut, "2", 0))
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Error Self Follow Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testFollowHandler := &FollowRouter{Repository: mockRepository, Service: domain.NewService(mockRepository)}
+
+		testFollowHandler.FollowHandler(response, newRequest(http.MethodPut, "1", 1))
+		assert.Equal(tt, http.StatusUnprocessableEntity, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Error User Not Found Follow Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+		mockUsers := &mockUser.Repository{}
+
+		service := domain.NewService(mockRepository)
+		service.Users = mockUsers
+
+		testFollowHandler := &FollowRouter{Repository: mockRepository, Service: service}
+		mockUsers.On("GetOne", mock.Anything, uint(2)).Return(userDomain.User{}, errs.NotFound("user not found")).Once()
+
+		testFollowHandler.FollowHandler(response, newRequest(http.MethodPut, "2", 1))
+		assert.Equal(tt, http.StatusNotFound, response.Code)
+		mockRepository.AssertExpectations(tt)
+		mockUsers.AssertExpectations(tt)
+	})
+
+	t.Run("Follow Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+		mockUsers := &mockUser.Repository{}
+
+		service := domain.NewService(mockRepository)
+		service.Users = mockUsers
+
+		testFollowHandler := &FollowRouter{Repository: mockRepository, Service: service}
+		mockUsers.On("GetOne", mock.Anything, uint(2)).Return(userDomain.User{ID: 2}, nil).Once()
+		mockRepository.On("Follow", mock.Anything, &domain.Follow{FollowerID: 1, FollowingID: 2}).Return(true, nil).Once()
+
+		testFollowHandler.FollowHandler(response, newRequest(http.MethodPut, "2", 1))
+		assert.Equal(tt, http.StatusNoContent, response.Code)
+		mockRepository.AssertExpectations(tt)
+		mockUsers.AssertExpectations(tt)
+	})
+}
+
+func TestFollowRouter_UnfollowHandler(t *testing.T) {
+	response := httptest.NewRecorder()
+	mockRepository := &mockLocal.Repository{}
+
+	testFollowHandler := &FollowRouter{Repository: mockRepository}
+	mockRepository.On("Unfollow", mock.Anything, uint(1), uint(2)).Return(nil).Once()
+
+	testFollowHandler.UnfollowHandler(response, newRequest(http.MethodDelete, "2", 1))
+	assert.Equal(t, http.StatusNoContent, response.Code)
+	mockRepository.AssertExpectations(t)
+}
+
+func TestFollowRouter_GetFollowersHandler(t *testing.T) {
+
+	t.Run("Error SQL Get Followers Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testFollowHandler := &FollowRouter{Repository: mockRepository}
+		mockRepository.On("GetFollowers", mock.Anything, uint(2), mock.Anything).Return(nil, page.Info{}, errors.New("error sql")).Once()
+
+		testFollowHandler.GetFollowersHandler(response, newRequest(http.MethodGet, "2", 0))
+		assert.Equal(tt, http.StatusInternalServerError, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Error Invalid Cursor", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testFollowHandler := &FollowRouter{Repository: mockRepository}
+
+		request := newRequest(http.MethodGet, "2", 0)
+		request.URL.RawQuery = "cursor=nope"
+
+		testFollowHandler.GetFollowersHandler(response, request)
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Get Followers Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testFollowHandler := &FollowRouter{Repository: mockRepository}
+		info := page.Info{NextCursor: "next"}
+		mockRepository.On("GetFollowers", mock.Anything, uint(2), page.Request{Limit: 5, Order: page.Desc}).
+			Return([]domain.Connection{{UserID: 1}}, info, nil).Once()
+
+		request := newRequest(http.MethodGet, "2", 0)
+		request.URL.RawQuery = "limit=5"
+
+		testFollowHandler.GetFollowersHandler(response, request)
+		assert.Equal(tt, http.StatusOK, response.Code)
+
+		var got ConnectionPage
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&got))
+		assert.Len(tt, got.Items, 1)
+		assert.Equal(tt, info, got.Info)
+		mockRepository.AssertExpectations(tt)
+	})
+}
+
+func TestFollowRouter_GetFollowingHandler(t *testing.T) {
+	response := httptest.NewRecorder()
+	mockRepository := &mockLocal.Repository{}
+
+	testFollowHandler := &FollowRouter{Repository: mockRepository}
+	mockRepository.On("GetFollowing", mock.Anything, uint(2), mock.Anything).Return(nil, page.Info{}, nil).Once()
+
+	testFollowHandler.GetFollowingHandler(response, newRequest(http.MethodGet, "2", 0))
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"items":[]}`, response.Body.String())
+	mockRepository.AssertExpectations(t)
+}
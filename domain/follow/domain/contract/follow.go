@@ -0,0 +1,163 @@
+// Package contract holds the conformance suite every adapter of the follow
+// port must pass, whatever its storage.
+package contract
+
+import (
+	"context"
+	"errors"
+	"microblog/domain/follow/domain"
+	"microblog/domain/shared/errs"
+	"microblog/domain/shared/page"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Harness is a follow repository under test along with a way to create the
+// users on both sides of the relationships.
+type Harness struct {
+	Repository domain.Repository
+	NewUser    func(t *testing.T) uint
+}
+
+// follow stores the relationship or stops the test. It reports whether the
+// relationship is new.
+func follow(t *testing.T, repository domain.Repository, followerID, followingID uint) bool {
+	t.Helper()
+
+	created, err := repository.Follow(context.Background(), &domain.Follow{FollowerID: followerID, FollowingID: followingID})
+	if err != nil {
+		t.Fatalf("error following %d from %d: %v", followingID, followerID, err)
+	}
+
+	return created
+}
+
+// userIDs returns the user ids of the connections in order.
+func userIDs(connections []domain.Connection) []uint {
+	result := make([]uint, 0, len(connections))
+	for _, c := range connections {
+		result = append(result, c.UserID)
+	}
+
+	return result
+}
+
+// TestRepository runs the domain.Repository suite. newHarness must return
+// an empty repository each time it is called.
+func TestRepository(t *testing.T, newHarness func(t *testing.T) Harness) {
+	ctx := context.Background()
+
+	t.Run("Follow Sets Creation Time", func(tt *testing.T) {
+		h := newHarness(tt)
+
+		relationship := domain.Follow{FollowerID: h.NewUser(tt), FollowingID: h.NewUser(tt)}
+		created, err := h.Repository.Follow(ctx, &relationship)
+		assert.NoError(tt, err)
+		assert.True(tt, created)
+		assert.False(tt, relationship.CreatedAt.IsZero())
+	})
+
+	t.Run("Follow Twice Is A No-op", func(tt *testing.T) {
+		h := newHarness(tt)
+		followerID, followingID := h.NewUser(tt), h.NewUser(tt)
+
+		assert.True(tt, follow(tt, h.Repository, followerID, followingID))
+		assert.False(tt, follow(tt, h.Repository, followerID, followingID))
+
+		counts, err := h.Repository.Counts(ctx, followingID)
+		assert.NoError(tt, err)
+		assert.Equal(tt, domain.Counts{Followers: 1}, counts)
+	})
+
+	t.Run("Error Unknown User", func(tt *testing.T) {
+		h := newHarness(tt)
+
+		_, err := h.Repository.Follow(ctx, &domain.Follow{FollowerID: h.NewUser(tt), FollowingID: 9999})
+		assert.True(tt, errors.Is(err, errs.ErrNotFound), "%v", err)
+	})
+
+	t.Run("Followers And Following Newest First", func(tt *testing.T) {
+		h := newHarness(tt)
+		me, first, second := h.NewUser(tt), h.NewUser(tt), h.NewUser(tt)
+
+		follow(tt, h.Repository, me, first)
+		follow(tt, h.Repository, me, second)
+		follow(tt, h.Repository, first, me)
+		follow(tt, h.Repository, second, me)
+
+		following, info, err := h.Repository.GetFollowing(ctx, me, page.Request{Limit: 10, Order: page.Desc})
+		assert.NoError(tt, err)
+		assert.Equal(tt, page.Info{}, info)
+		assert.Equal(tt, []uint{second, first}, userIDs(following))
+		for _, c := range following {
+			assert.NotEmpty(tt, c.Username)
+			assert.False(tt, c.FollowedAt.IsZero())
+		}
+
+		followers, _, err := h.Repository.GetFollowers(ctx, me, page.Request{Limit: 10, Order: page.Desc})
+		assert.NoError(tt, err)
+		assert.Equal(tt, []uint{second, first}, userIDs(followers))
+
+		counts, err := h.Repository.Counts(ctx, me)
+		assert.NoError(tt, err)
+		assert.Equal(tt, domain.Counts{Followers: 2, Following: 2}, counts)
+	})
+
+	t.Run("Followers Paginated", func(tt *testing.T) {
+		h := newHarness(tt)
+		me := h.NewUser(tt)
+
+		var users []uint
+		for i := 0; i < 3; i++ {
+			userID := h.NewUser(tt)
+			follow(tt, h.Repository, userID, me)
+			users = append(users, userID)
+		}
+
+		first, info, err := h.Repository.GetFollowers(ctx, me, page.Request{Limit: 2, Order: page.Desc})
+		assert.NoError(tt, err)
+		assert.Equal(tt, []uint{users[2], users[1]}, userIDs(first))
+		assert.Empty(tt, info.PrevCursor)
+
+		next, err := page.DecodeCursor(info.NextCursor)
+		if !assert.NoError(tt, err) {
+			return
+		}
+
+		second, info, err := h.Repository.GetFollowers(ctx, me, page.Request{Limit: 2, Order: page.Desc, Cursor: &next})
+		assert.NoError(tt, err)
+		assert.Equal(tt, []uint{users[0]}, userIDs(second))
+		assert.Empty(tt, info.NextCursor)
+
+		prev, err := page.DecodeCursor(info.PrevCursor)
+		if !assert.NoError(tt, err) {
+			return
+		}
+
+		back, _, err := h.Repository.GetFollowers(ctx, me, page.Request{Limit: 2, Order: page.Desc, Cursor: &prev})
+		assert.NoError(tt, err)
+		assert.Equal(tt, []uint{users[2], users[1]}, userIDs(back))
+
+		ascending, _, err := h.Repository.GetFollowers(ctx, me, page.Request{Limit: 10, Order: page.Asc})
+		assert.NoError(tt, err)
+		assert.Equal(tt, users, userIDs(ascending))
+	})
+
+	t.Run("Unfollow", func(tt *testing.T) {
+		h := newHarness(tt)
+		followerID, followingID := h.NewUser(tt), h.NewUser(tt)
+		follow(tt, h.Repository, followerID, followingID)
+
+		assert.NoError(tt, h.Repository.Unfollow(ctx, followerID, followingID))
+		assert.NoError(tt, h.Repository.Unfollow(ctx, followerID, followingID))
+
+		following, _, err := h.Repository.GetFollowing(ctx, followerID, page.Request{Limit: 10, Order: page.Desc})
+		assert.NoError(tt, err)
+		assert.Empty(tt, following)
+
+		counts, err := h.Repository.Counts(ctx, followingID)
+		assert.NoError(tt, err)
+		assert.Equal(tt, domain.Counts{}, counts)
+	})
+}
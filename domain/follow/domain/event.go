@@ -0,0 +1,11 @@
+package domain
+
+// Followed is published once a user follows another one.
+type Followed struct {
+	Follow Follow
+}
+
+// Name identifies the event.
+func (Followed) Name() string {
+	return "follow.created"
+}
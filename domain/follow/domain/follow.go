@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"microblog/domain/shared/errs"
+	"microblog/domain/shared/page"
+	"time"
+)
+
+// ErrSelfFollow is returned when a user tries to follow itself.
+var ErrSelfFollow = errs.Validation("a user can not follow itself")
+
+// Follow is the relationship of a user following another one.
+type Follow struct {
+	FollowerID  uint      `json:"follower_id,omitempty"`
+	FollowingID uint      `json:"following_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at,omitempty"`
+}
+
+// Validate is the validation method for the relationship.
+func (f Follow) Validate() error {
+	if f.FollowerID == f.FollowingID {
+		return ErrSelfFollow
+	}
+
+	return nil
+}
+
+// Connection is a user on the other side of a follow relationship.
+type Connection struct {
+	UserID     uint      `json:"id"`
+	FirstName  string    `json:"first_name,omitempty"`
+	LastName   string    `json:"last_name,omitempty"`
+	Username   string    `json:"username,omitempty"`
+	Picture    string    `json:"picture,omitempty"`
+	FollowedAt time.Time `json:"followed_at"`
+}
+
+// Cursor returns the position of the connection in a list ordered by follow
+// time.
+func (c Connection) Cursor() page.Cursor {
+	return page.Cursor{CreatedAt: c.FollowedAt, ID: c.UserID}
+}
+
+// Counts are the follow graph counters of a user.
+type Counts struct {
+	Followers int `json:"followers_count"`
+	Following int `json:"following_count"`
+}
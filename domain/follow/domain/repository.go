@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"context"
+	"microblog/domain/shared/page"
+)
+
+// Repository handle the operations over the follow graph.
+type Repository interface {
+	// Follow adds the relationship and reports whether it is new, following
+	// twice is a no-op.
+	Follow(ctx context.Context, follow *Follow) (bool, error)
+	Unfollow(ctx context.Context, followerID, followingID uint) error
+	GetFollowers(ctx context.Context, userID uint, p page.Request) ([]Connection, page.Info, error)
+	GetFollowing(ctx context.Context, userID uint, p page.Request) ([]Connection, page.Info, error)
+	Counts(ctx context.Context, userID uint) (Counts, error)
+}
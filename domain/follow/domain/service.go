@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"context"
+	"microblog/domain/shared/event"
+	userDomain "microblog/domain/user/domain"
+)
+
+// Service applies the follow rules over a Repository. The followed users
+// are looked up in Users and the new follows published to Events when they
+// are set.
+type Service struct {
+	Repository Repository
+	Users      userDomain.Repository
+	Events     event.Publisher
+}
+
+// NewService returns a Service that stores the follow graph in the given
+// repository.
+func NewService(repository Repository) *Service {
+	return &Service{Repository: repository}
+}
+
+// Follow makes a user follow another one. Following twice is a no-op and
+// publishes nothing.
+func (s *Service) Follow(ctx context.Context, follow *Follow) error {
+	if err := follow.Validate(); err != nil {
+		return err
+	}
+
+	if s.Users != nil {
+		if _, err := s.Users.GetOne(ctx, follow.FollowingID); err != nil {
+			return err
+		}
+	}
+
+	created, err := s.Repository.Follow(ctx, follow)
+	if err != nil {
+		return err
+	}
+
+	if created && s.Events != nil {
+		s.Events.Publish(ctx, Followed{Follow: *follow})
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package domain_test
+
+import (
+	"context"
+	"errors"
+	"microblog/domain/follow/domain"
+	mockLocal "microblog/domain/follow/domain/mocks"
+	"microblog/domain/shared/errs"
+	"microblog/domain/shared/event"
+	userDomain "microblog/domain/user/domain"
+	mockUser "microblog/domain/user/domain/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestService_Follow(t *testing.T) {
+
+	t.Run("Error Self Follow", func(tt *testing.T) {
+		mockRepository := &mockLocal.Repository{}
+		service := domain.NewService(mockRepository)
+
+		err := service.Follow(context.Background(), &domain.Follow{FollowerID: 1, FollowingID: 1})
+		assert.True(tt, errors.Is(err, domain.ErrSelfFollow), "%v", err)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Error User Not Found", func(tt *testing.T) {
+		mockRepository := &mockLocal.Repository{}
+		mockUsers := &mockUser.Repository{}
+		service := domain.NewService(mockRepository)
+		service.Users = mockUsers
+
+		mockUsers.On("GetOne", mock.Anything, uint(2)).Return(userDomain.User{}, errs.NotFound("user not found")).Once()
+
+		err := service.Follow(context.Background(), &domain.Follow{FollowerID: 1, FollowingID: 2})
+		assert.True(tt, errors.Is(err, errs.ErrNotFound), "%v", err)
+		mockRepository.AssertExpectations(tt)
+		mockUsers.AssertExpectations(tt)
+	})
+
+	t.Run("Publishes New Follows Only", func(tt *testing.T) {
+		mockRepository := &mockLocal.Repository{}
+		service := domain.NewService(mockRepository)
+
+		var published []event.Event
+		bus := event.NewBus()
+		bus.Subscribe(func(ctx context.Context, e event.Event) error {
+			published = append(published, e)
+			return nil
+		})
+		service.Events = bus
+
+		follow := &domain.Follow{FollowerID: 1, FollowingID: 2}
+		mockRepository.On("Follow", mock.Anything, follow).Return(false, errors.New("error sql")).Once()
+		assert.Error(tt, service.Follow(context.Background(), follow))
+
+		mockRepository.On("Follow", mock.Anything, follow).Return(true, nil).Once()
+		assert.NoError(tt, service.Follow(context.Background(), follow))
+
+		mockRepository.On("Follow", mock.Anything, follow).Return(false, nil).Once()
+		assert.NoError(tt, service.Follow(context.Background(), follow))
+
+		if assert.Len(tt, published, 1) {
+			assert.Equal(tt, "follow.created", published[0].Name())
+			assert.Equal(tt, uint(2), published[0].(domain.Followed).Follow.FollowingID)
+		}
+		mockRepository.AssertExpectations(tt)
+	})
+}
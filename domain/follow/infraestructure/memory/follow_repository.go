@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"context"
+	"microblog/domain/follow/domain"
+	"microblog/domain/shared/page"
+	userDomain "microblog/domain/user/domain"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FollowRepository keeps the follow graph in memory. It reads the users on
+// both sides of a relationship from Users. It is safe for concurrent use.
+type FollowRepository struct {
+	Users userDomain.Repository
+
+	mu      sync.RWMutex
+	follows map[domain.Follow]time.Time
+}
+
+// NewFollowRepository returns an empty FollowRepository.
+func NewFollowRepository(users userDomain.Repository) *FollowRepository {
+	return &FollowRepository{Users: users, follows: make(map[domain.Follow]time.Time)}
+}
+
+// Follow adds a follow relationship and reports whether it is new.
+// Following twice is a no-op.
+func (fr *FollowRepository) Follow(ctx context.Context, follow *domain.Follow) (bool, error) {
+	now := time.Now().Truncate(time.Microsecond)
+
+	if err := follow.Validate(); err != nil {
+		return false, err
+	}
+
+	for _, id := range []uint{follow.FollowerID, follow.FollowingID} {
+		if _, err := fr.Users.GetOne(ctx, id); err != nil {
+			return false, err
+		}
+	}
+
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+
+	key := domain.Follow{FollowerID: follow.FollowerID, FollowingID: follow.FollowingID}
+	_, exists := fr.follows[key]
+	if !exists {
+		fr.follows[key] = now
+	}
+
+	follow.CreatedAt = now
+
+	return !exists, nil
+}
+
+// Unfollow removes a follow relationship.
+func (fr *FollowRepository) Unfollow(ctx context.Context, followerID, followingID uint) error {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+
+	delete(fr.follows, domain.Follow{FollowerID: followerID, FollowingID: followingID})
+
+	return nil
+}
+
+// GetFollowers returns a page of the users that follow the given user.
+func (fr *FollowRepository) GetFollowers(ctx context.Context, userID uint, p page.Request) ([]domain.Connection, page.Info, error) {
+	return fr.connections(ctx, p, func(f domain.Follow) (uint, bool) {
+		return f.FollowerID, f.FollowingID == userID
+	})
+}
+
+// GetFollowing returns a page of the users followed by the given user.
+func (fr *FollowRepository) GetFollowing(ctx context.Context, userID uint, p page.Request) ([]domain.Connection, page.Info, error) {
+	return fr.connections(ctx, p, func(f domain.Follow) (uint, bool) {
+		return f.FollowingID, f.FollowerID == userID
+	})
+}
+
+// Counts returns the follow graph counters of the given user.
+func (fr *FollowRepository) Counts(ctx context.Context, userID uint) (domain.Counts, error) {
+	fr.mu.RLock()
+	defer fr.mu.RUnlock()
+
+	var counts domain.Counts
+	for f := range fr.follows {
+		if f.FollowingID == userID {
+			counts.Followers++
+		}
+
+		if f.FollowerID == userID {
+			counts.Following++
+		}
+	}
+
+	return counts, nil
+}
+
+// connections returns a page of the users on the other side of the
+// relationships that match, skipping the ones that no longer exist.
+func (fr *FollowRepository) connections(ctx context.Context, p page.Request, match func(f domain.Follow) (uint, bool)) ([]domain.Connection, page.Info, error) {
+	fr.mu.RLock()
+	followedAt := make(map[uint]time.Time)
+	for f, createdAt := range fr.follows {
+		if id, ok := match(f); ok {
+			followedAt[id] = createdAt
+		}
+	}
+	fr.mu.RUnlock()
+
+	var connections []domain.Connection
+	for id, createdAt := range followedAt {
+		u, err := fr.Users.GetOne(ctx, id)
+		if err != nil {
+			continue
+		}
+
+		connections = append(connections, domain.Connection{
+			UserID:     u.ID,
+			FirstName:  u.FirstName,
+			LastName:   u.LastName,
+			Username:   u.Username,
+			Picture:    u.Picture,
+			FollowedAt: createdAt,
+		})
+	}
+
+	sort.Slice(connections, func(i, j int) bool {
+		return connections[i].Cursor().Before(connections[j].Cursor(), p.Order)
+	})
+
+	from, to, info := p.Slice(len(connections), func(i int) page.Cursor {
+		return connections[i].Cursor()
+	})
+
+	return connections[from:to], info, nil
+}
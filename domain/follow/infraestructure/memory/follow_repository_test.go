@@ -0,0 +1,30 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"microblog/domain/follow/domain/contract"
+	userContract "microblog/domain/user/domain/contract"
+	memoryUser "microblog/domain/user/infraestructure/memory"
+	"testing"
+)
+
+func TestFollowRepository_Contract(t *testing.T) {
+	contract.TestRepository(t, func(t *testing.T) contract.Harness {
+		users := memoryUser.NewUserRepository()
+		n := 0
+
+		return contract.Harness{
+			Repository: NewFollowRepository(users),
+			NewUser: func(t *testing.T) uint {
+				n++
+				u := userContract.NewUser(fmt.Sprintf("user.%d", n))
+				if err := users.Create(context.Background(), &u); err != nil {
+					t.Fatalf("error creating user: %v", err)
+				}
+
+				return u.ID
+			},
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"microblog/domain/follow/domain"
+	"microblog/domain/shared/page"
+	"time"
+
+	conn "microblog/infrastructure/database"
+)
+
+// FollowRepository manages the operations with the database that
+// correspond to the follow model.
+type FollowRepository struct {
+	Data *conn.Data
+}
+
+// Follow adds a follow relationship and reports whether it is new.
+func (fr *FollowRepository) Follow(ctx context.Context, follow *domain.Follow) (bool, error) {
+	now := time.Now().Truncate(time.Microsecond)
+
+	stmt, err := fr.Data.DB.PrepareContext(ctx, insertFollow)
+	if err != nil {
+		return false, err
+	}
+
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, follow.FollowerID, follow.FollowingID, now)
+	if err != nil {
+		return false, conn.Error(err, "user not found")
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	follow.CreatedAt = now
+
+	return inserted > 0, nil
+}
+
+// Unfollow removes a follow relationship.
+func (fr *FollowRepository) Unfollow(ctx context.Context, followerID, followingID uint) error {
+	stmt, err := fr.Data.DB.PrepareContext(ctx, deleteFollow)
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, followerID, followingID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetFollowers returns a page of the users that follow the given user.
+func (fr *FollowRepository) GetFollowers(ctx context.Context, userID uint, p page.Request) ([]domain.Connection, page.Info, error) {
+	return fr.connections(ctx, selectFollowers, userID, p)
+}
+
+// GetFollowing returns a page of the users followed by the given user.
+func (fr *FollowRepository) GetFollowing(ctx context.Context, userID uint, p page.Request) ([]domain.Connection, page.Info, error) {
+	return fr.connections(ctx, selectFollowing, userID, p)
+}
+
+// Counts returns the follow graph counters of the given user.
+func (fr *FollowRepository) Counts(ctx context.Context, userID uint) (domain.Counts, error) {
+	row := fr.Data.DB.QueryRowContext(ctx, selectCounts, userID)
+
+	var counts domain.Counts
+	err := row.Scan(&counts.Followers, &counts.Following)
+	if err != nil {
+		return domain.Counts{}, err
+	}
+
+	return counts, nil
+}
+
+// connections runs a query that selects a page of users of the follow
+// graph.
+func (fr *FollowRepository) connections(ctx context.Context, query string, userID uint, p page.Request) ([]domain.Connection, page.Info, error) {
+	op, order := p.Seek()
+	after, afterID := p.Position()
+
+	rows, err := fr.Data.DB.QueryContext(ctx, fmt.Sprintf(query, op, order, order), userID, after, afterID, p.Fetch())
+	if err != nil {
+		return nil, page.Info{}, err
+	}
+
+	defer rows.Close()
+
+	var connections []domain.Connection
+	for rows.Next() {
+		var c domain.Connection
+		err := rows.Scan(&c.UserID, &c.FirstName, &c.LastName, &c.Username, &c.Picture, &c.FollowedAt)
+		if err != nil {
+			return nil, page.Info{}, err
+		}
+
+		connections = append(connections, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, page.Info{}, err
+	}
+
+	keep, more := p.Trim(len(connections))
+	connections = connections[:keep]
+	if keep == 0 {
+		return connections, page.Info{}, nil
+	}
+
+	if p.Backward() {
+		for i, j := 0, keep-1; i < j; i, j = i+1, j-1 {
+			connections[i], connections[j] = connections[j], connections[i]
+		}
+	}
+
+	return connections, p.Info(connections[0].Cursor(), connections[keep-1].Cursor(), more), nil
+}
@@ -0,0 +1,145 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"microblog/domain/follow/domain"
+	"microblog/domain/shared/page"
+	data "microblog/infrastructure/database"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// represent the repository
+var (
+	dbMockFollow         *sql.DB
+	followRepositoryMock *FollowRepository
+)
+
+// NewMockFollow initialize mock connection to database
+func NewMockFollow() sqlmock.Sqlmock {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	dbMockFollow = db
+	followRepositoryMock = &FollowRepository{
+		Data: &data.Data{DB: dbMockFollow},
+	}
+
+	return mock
+}
+
+// CloseMockFollow attaches the provider and close the connection
+func CloseMockFollow() {
+	err := dbMockFollow.Close()
+	if err != nil {
+		log.Println("Error close database test")
+	}
+}
+
+func TestFollowRepository_Follow(t *testing.T) {
+
+	t.Run("Error SQL", func(tt *testing.T) {
+		mock := NewMockFollow()
+		defer CloseMockFollow()
+
+		prep := mock.ExpectPrepare(insertFollowTest)
+		prep.ExpectExec().WithArgs(1, 2, sqlmock.AnyArg()).WillReturnError(errors.New("error sql"))
+
+		_, err := followRepositoryMock.Follow(context.Background(), &domain.Follow{FollowerID: 1, FollowingID: 2})
+		assert.Error(tt, err)
+	})
+
+	t.Run("Follow Successful", func(tt *testing.T) {
+		mock := NewMockFollow()
+		defer CloseMockFollow()
+
+		prep := mock.ExpectPrepare(insertFollowTest)
+		prep.ExpectExec().WithArgs(1, 2, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		follow := &domain.Follow{FollowerID: 1, FollowingID: 2}
+		created, err := followRepositoryMock.Follow(context.Background(), follow)
+		assert.NoError(tt, err)
+		assert.True(tt, created)
+		assert.False(tt, follow.CreatedAt.IsZero())
+	})
+
+	t.Run("Follow Twice", func(tt *testing.T) {
+		mock := NewMockFollow()
+		defer CloseMockFollow()
+
+		prep := mock.ExpectPrepare(insertFollowTest)
+		prep.ExpectExec().WithArgs(1, 2, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		created, err := followRepositoryMock.Follow(context.Background(), &domain.Follow{FollowerID: 1, FollowingID: 2})
+		assert.NoError(tt, err)
+		assert.False(tt, created)
+	})
+}
+
+func TestFollowRepository_Unfollow(t *testing.T) {
+	mock := NewMockFollow()
+	defer CloseMockFollow()
+
+	prep := mock.ExpectPrepare(deleteFollowTest)
+	prep.ExpectExec().WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := followRepositoryMock.Unfollow(context.Background(), 1, 2)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFollowRepository_GetFollowers(t *testing.T) {
+
+	t.Run("Error SQL", func(tt *testing.T) {
+		mock := NewMockFollow()
+		defer CloseMockFollow()
+
+		mock.ExpectQuery(selectFollowersTest).WithArgs(2, nil, 0, 11).WillReturnError(errors.New("error sql"))
+
+		connections, _, err := followRepositoryMock.GetFollowers(context.Background(), 2, page.Request{Limit: 10, Order: page.Desc})
+		assert.Error(tt, err)
+		assert.Nil(tt, connections)
+	})
+
+	t.Run("Get Followers Successful", func(tt *testing.T) {
+		mock := NewMockFollow()
+		defer CloseMockFollow()
+
+		now := time.Now()
+		rows := sqlmock.NewRows([]string{"id", "first_name", "last_name", "username", "picture", "created_at"}).
+			AddRow(1, "Daniel", "De La Pava Suarez", "daniel.delapava", "", now).
+			AddRow(3, "Rebecca", "Romero", "rebecca.romero", "", now)
+
+		mock.ExpectQuery(selectFollowersTest).WithArgs(2, nil, 0, 2).WillReturnRows(rows)
+
+		connections, info, err := followRepositoryMock.GetFollowers(context.Background(), 2, page.Request{Limit: 1, Order: page.Desc})
+		assert.NoError(tt, err)
+		if assert.Len(tt, connections, 1) {
+			assert.Equal(tt, "daniel.delapava", connections[0].Username)
+		}
+
+		next, err := page.DecodeCursor(info.NextCursor)
+		assert.NoError(tt, err)
+		assert.Equal(tt, uint(1), next.ID)
+		assert.Empty(tt, info.PrevCursor)
+	})
+}
+
+func TestFollowRepository_Counts(t *testing.T) {
+	mock := NewMockFollow()
+	defer CloseMockFollow()
+
+	mock.ExpectQuery(selectCountsTest).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"followers", "following"}).AddRow(5, 7))
+
+	counts, err := followRepositoryMock.Counts(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Counts{Followers: 5, Following: 7}, counts)
+}
@@ -0,0 +1,26 @@
+package persistence
+
+const (
+
+	// insertFollow is a query that inserts a new row in the follows table using the values
+	// given in order for follower_id, following_id and created_at. Following twice is a no-op.
+	insertFollow = "INSERT INTO follows (follower_id, following_id, created_at) VALUES ($1, $2, $3) ON CONFLICT (follower_id, following_id) DO NOTHING;"
+
+	// deleteFollow is a query that deletes a row in the follows table given a follower_id and following_id.
+	deleteFollow = "DELETE FROM follows WHERE follower_id=$1 AND following_id=$2;"
+
+	// selectFollowers is a query that selects a page of the users that follow the given user. It must be
+	// formatted with the comparison operator and the sort direction returned by page.Request.Seek.
+	selectFollowers = "SELECT u.id, u.first_name, u.last_name, u.username, u.picture, f.created_at FROM follows f " +
+		"INNER JOIN users u ON u.id = f.follower_id WHERE f.following_id = $1 AND ($2::timestamp IS NULL OR (f.created_at, u.id) %s ($2::timestamp, $3)) " +
+		"ORDER BY f.created_at %s, u.id %s LIMIT $4;"
+
+	// selectFollowing is a query that selects a page of the users followed by the given user. It must be
+	// formatted with the comparison operator and the sort direction returned by page.Request.Seek.
+	selectFollowing = "SELECT u.id, u.first_name, u.last_name, u.username, u.picture, f.created_at FROM follows f " +
+		"INNER JOIN users u ON u.id = f.following_id WHERE f.follower_id = $1 AND ($2::timestamp IS NULL OR (f.created_at, u.id) %s ($2::timestamp, $3)) " +
+		"ORDER BY f.created_at %s, u.id %s LIMIT $4;"
+
+	// selectCounts is a query that counts the followers and the followed users of the given user.
+	selectCounts = "SELECT (SELECT count(*) FROM follows WHERE following_id = $1), (SELECT count(*) FROM follows WHERE follower_id = $1);"
+)
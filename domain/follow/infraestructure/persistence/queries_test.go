@@ -0,0 +1,22 @@
+package persistence
+
+const (
+
+	// insertFollowTest is a query test that inserts a new row in the follows table.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	insertFollowTest = "INSERT INTO follows \\(follower_id, following_id, created_at\\) VALUES \\(\\$1, \\$2, \\$3\\) ON CONFLICT \\(follower_id, following_id\\) DO NOTHING;"
+
+	// deleteFollowTest is a query that deletes a row in the follows table.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	deleteFollowTest = "DELETE FROM follows WHERE follower_id\\=\\$1 AND following_id\\=\\$2;"
+
+	// selectFollowersTest is the beginning of the query that selects the users that follow the given user.
+	selectFollowersTest = "SELECT u.id, u.first_name, u.last_name, u.username, u.picture, f.created_at FROM follows f INNER JOIN users u ON u.id \\= f.follower_id"
+
+	// selectCountsTest is a query that counts the followers and the followed users of the given user.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	selectCountsTest = "SELECT \\(SELECT count\\(\\*\\) FROM follows WHERE following_id \\= \\$1\\), \\(SELECT count\\(\\*\\) FROM follows WHERE follower_id \\= \\$1\\);"
+)
@@ -0,0 +1,163 @@
+package v1
+
+import (
+	"microblog/domain/like/domain"
+	postDomain "microblog/domain/post/domain"
+	"microblog/domain/shared/page"
+	"microblog/domain/shared/response"
+	"microblog/infrastructure/auth"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+)
+
+// LikeRouter is the router of the likes on the posts. The likes go through
+// Service, which publishes them.
+type LikeRouter struct {
+	Repository domain.Repository
+	Service    *domain.Service
+}
+
+// LikerPage is a page of the users who liked a post along with the cursors
+// of its neighbours.
+type LikerPage struct {
+	Items []domain.Liker `json:"items"`
+	page.Info
+}
+
+// LikedPostPage is a page of the posts a user liked along with the cursors
+// of its neighbours.
+type LikedPostPage struct {
+	Items []domain.LikedPost `json:"items"`
+	page.Info
+}
+
+// id reads the id URL param of the request.
+func id(r *http.Request) (uint, error) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		return 0, err
+	}
+
+	return uint(id), nil
+}
+
+// LikeHandler makes the authenticated user like the post by id.
+func (lr *LikeRouter) LikeHandler(w http.ResponseWriter, r *http.Request) {
+	postID, err := id(r)
+	if err != nil {
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	principal, ok := auth.FromContext(r.Context())
+	if !ok {
+		response.HTTPError(w, r, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	like := domain.Like{UserID: principal.UserID, PostID: postID}
+	err = lr.Service.Like(r.Context(), &like)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, r, http.StatusNoContent, nil)
+}
+
+// UnlikeHandler removes the like of the authenticated user on the post by id.
+func (lr *LikeRouter) UnlikeHandler(w http.ResponseWriter, r *http.Request) {
+	postID, err := id(r)
+	if err != nil {
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	principal, ok := auth.FromContext(r.Context())
+	if !ok {
+		response.HTTPError(w, r, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	ctx := r.Context()
+	err = lr.Repository.Unlike(ctx, principal.UserID, postID)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, r, http.StatusNoContent, nil)
+}
+
+// GetLikersHandler response a page of the users who liked the post by id,
+// most recent like first.
+func (lr *LikeRouter) GetLikersHandler(w http.ResponseWriter, r *http.Request) {
+	postID, err := id(r)
+	if err != nil {
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	p, err := page.FromQuery(r.URL.Query())
+	if err != nil {
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	likers, info, err := lr.Repository.GetLikers(ctx, postID, p)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	if likers == nil {
+		likers = []domain.Liker{}
+	}
+
+	response.JSON(w, r, http.StatusOK, LikerPage{Items: likers, Info: info})
+}
+
+// GetLikedPostsHandler response a page of the posts the user by id liked,
+// most recent like first, with their likes and the likes of the originals
+// they embed as seen by the reader.
+func (lr *LikeRouter) GetLikedPostsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := id(r)
+	if err != nil {
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	p, err := page.FromQuery(r.URL.Query())
+	if err != nil {
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	posts, info, err := lr.Repository.GetLikedPosts(ctx, userID, p)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	liked := make([]*postDomain.Post, 0, len(posts))
+	for i := range posts {
+		liked = append(liked, &posts[i].Post)
+	}
+
+	principal, _ := auth.FromContext(ctx)
+	err = WithStats(ctx, lr.Repository, principal.UserID, liked...)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	if posts == nil {
+		posts = []domain.LikedPost{}
+	}
+
+	response.JSON(w, r, http.StatusOK, LikedPostPage{Items: posts, Info: info})
+}
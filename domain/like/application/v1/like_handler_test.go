@@ -0,0 +1,162 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"microblog/domain/like/domain"
+	mockLocal "microblog/domain/like/domain/mocks"
+	postDomain "microblog/domain/post/domain"
+	"microblog/domain/shared/errs"
+	"microblog/domain/shared/page"
+	"microblog/infrastructure/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newRequest returns a request for the id URL param, authenticated as
+// principalID unless it is zero.
+func newRequest(method string, target string, id string, principalID uint) *http.Request {
+	request := httptest.NewRequest(method, target, nil)
+
+	requestCtx := chi.NewRouteContext()
+	requestCtx.URLParams.Add("id", id)
+	ctx := context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx)
+
+	if principalID != 0 {
+		ctx = auth.NewContext(ctx, auth.Principal{UserID: principalID})
+	}
+
+	return request.WithContext(ctx)
+}
+
+func TestLikeRouter_LikeHandler(t *testing.T) {
+
+	t.Run("Error Param Like Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testLikeHandler := &LikeRouter{Repository: mockRepository, Service: domain.NewService(mockRepository)}
+
+		testLikeHandler.LikeHandler(response, newRequest(http.MethodPut, "/api/v1/posts/abc/like", "abc", 1))
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Error Unauthenticated Like Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testLikeHandler := &LikeRouter{Repository: mockRepository, Service: domain.NewService(mockRepository)}
+
+		testLikeHandler.LikeHandler(response, newRequest(http.MethodPut, "/api/v1/posts/2/like", "2", 0))
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Error Post Not Found Like Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testLikeHandler := &LikeRouter{Repository: mockRepository, Service: domain.NewService(mockRepository)}
+		mockRepository.On("Like", mock.Anything, &domain.Like{UserID: 1, PostID: 2}).Return(false, errs.NotFound("post not found")).Once()
+
+		testLikeHandler.LikeHandler(response, newRequest(http.MethodPut, "/api/v1/posts/2/like", "2", 1))
+		assert.Equal(tt, http.StatusNotFound, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Like Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testLikeHandler := &LikeRouter{Repository: mockRepository, Service: domain.NewService(mockRepository)}
+		mockRepository.On("Like", mock.Anything, &domain.Like{UserID: 1, PostID: 2}).Return(true, nil).Once()
+
+		testLikeHandler.LikeHandler(response, newRequest(http.MethodPut, "/api/v1/posts/2/like", "2", 1))
+		assert.Equal(tt, http.StatusNoContent, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+}
+
+func TestLikeRouter_UnlikeHandler(t *testing.T) {
+	response := httptest.NewRecorder()
+	mockRepository := &mockLocal.Repository{}
+
+	testLikeHandler := &LikeRouter{Repository: mockRepository}
+	mockRepository.On("Unlike", mock.Anything, uint(1), uint(2)).Return(nil).Once()
+
+	testLikeHandler.UnlikeHandler(response, newRequest(http.MethodDelete, "/api/v1/posts/2/like", "2", 1))
+	assert.Equal(t, http.StatusNoContent, response.Code)
+	mockRepository.AssertExpectations(t)
+}
+
+func TestLikeRouter_GetLikersHandler(t *testing.T) {
+
+	t.Run("Error Page Get Likers Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testLikeHandler := &LikeRouter{Repository: mockRepository}
+
+		testLikeHandler.GetLikersHandler(response, newRequest(http.MethodGet, "/api/v1/posts/2/likes?limit=abc", "2", 0))
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Error SQL Get Likers Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testLikeHandler := &LikeRouter{Repository: mockRepository}
+		mockRepository.On("GetLikers", mock.Anything, uint(2), mock.Anything).Return(nil, page.Info{}, errors.New("error sql")).Once()
+
+		testLikeHandler.GetLikersHandler(response, newRequest(http.MethodGet, "/api/v1/posts/2/likes", "2", 0))
+		assert.Equal(tt, http.StatusInternalServerError, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Get Likers Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testLikeHandler := &LikeRouter{Repository: mockRepository}
+		mockRepository.On("GetLikers", mock.Anything, uint(2), mock.Anything).Return(nil, page.Info{}, nil).Once()
+
+		testLikeHandler.GetLikersHandler(response, newRequest(http.MethodGet, "/api/v1/posts/2/likes", "2", 0))
+		assert.Equal(tt, http.StatusOK, response.Code)
+		assert.JSONEq(tt, `{"items":[]}`, response.Body.String())
+		mockRepository.AssertExpectations(tt)
+	})
+}
+
+func TestLikeRouter_GetLikedPostsHandler(t *testing.T) {
+	response := httptest.NewRecorder()
+	mockRepository := &mockLocal.Repository{}
+
+	testLikeHandler := &LikeRouter{Repository: mockRepository}
+	quoteOf := uint(4)
+	liked := []domain.LikedPost{{Post: postDomain.Post{ID: 7, UserID: 2, QuoteOf: &quoteOf, Original: &postDomain.Post{ID: 4, UserID: 3}}}}
+	mockRepository.On("GetLikedPosts", mock.Anything, uint(2), mock.Anything).Return(liked, page.Info{}, nil).Once()
+	mockRepository.On("Stats", mock.Anything, uint(1), []uint{7, 4}).Return(map[uint]domain.Stats{7: {Count: 3, LikedByMe: true}, 4: {Count: 1}}, nil).Once()
+
+	testLikeHandler.GetLikedPostsHandler(response, newRequest(http.MethodGet, "/api/v1/users/2/likes", "2", 1))
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	var got LikedPostPage
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&got))
+	if assert.Len(t, got.Items, 1) {
+		assert.Equal(t, 3, got.Items[0].LikeCount)
+		assert.True(t, got.Items[0].LikedByMe)
+		if assert.NotNil(t, got.Items[0].Original) {
+			assert.Equal(t, 1, got.Items[0].Original.LikeCount)
+			assert.False(t, got.Items[0].Original.LikedByMe)
+		}
+	}
+	mockRepository.AssertExpectations(t)
+}
@@ -0,0 +1,38 @@
+package v1
+
+import (
+	"context"
+	"microblog/domain/like/domain"
+	postDomain "microblog/domain/post/domain"
+)
+
+// WithStats sets the like count of the posts and of the originals they
+// embed, and whether the reader liked them, as every router responding
+// posts does. It does nothing without likes.
+func WithStats(ctx context.Context, likes domain.Repository, readerID uint, posts ...*postDomain.Post) error {
+	if likes == nil || len(posts) == 0 {
+		return nil
+	}
+
+	for _, p := range posts {
+		if p.Original != nil {
+			posts = append(posts, p.Original)
+		}
+	}
+
+	ids := make([]uint, 0, len(posts))
+	for _, p := range posts {
+		ids = append(ids, p.ID)
+	}
+
+	stats, err := likes.Stats(ctx, readerID, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range posts {
+		stats[p.ID].Apply(p)
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"microblog/domain/like/domain"
+	mockLocal "microblog/domain/like/domain/mocks"
+	postDomain "microblog/domain/post/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWithStats(t *testing.T) {
+
+	t.Run("Without Likes", func(tt *testing.T) {
+		post := postDomain.Post{ID: 1}
+		assert.NoError(tt, WithStats(context.Background(), nil, 1, &post))
+		assert.Equal(tt, 0, post.LikeCount)
+	})
+
+	t.Run("Error SQL", func(tt *testing.T) {
+		mockRepository := &mockLocal.Repository{}
+		mockRepository.On("Stats", mock.Anything, uint(1), []uint{1}).Return(nil, errors.New("error sql")).Once()
+
+		assert.Error(tt, WithStats(context.Background(), mockRepository, 1, &postDomain.Post{ID: 1}))
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Posts And Originals", func(tt *testing.T) {
+		mockRepository := &mockLocal.Repository{}
+		mockRepository.On("Stats", mock.Anything, uint(1), []uint{7, 8, 4}).
+			Return(map[uint]domain.Stats{7: {Count: 3, LikedByMe: true}, 4: {Count: 1}}, nil).Once()
+
+		quote := postDomain.Post{ID: 7, Original: &postDomain.Post{ID: 4}}
+		plain := postDomain.Post{ID: 8, LikeCount: 5}
+
+		assert.NoError(tt, WithStats(context.Background(), mockRepository, 1, &quote, &plain))
+		assert.Equal(tt, 3, quote.LikeCount)
+		assert.True(tt, quote.LikedByMe)
+		assert.Equal(tt, 1, quote.Original.LikeCount)
+		assert.Equal(tt, 0, plain.LikeCount)
+		mockRepository.AssertExpectations(tt)
+	})
+}
@@ -0,0 +1,231 @@
+// Package contract holds the conformance suite every adapter of the like
+// port must pass, whatever its storage.
+package contract
+
+import (
+	"context"
+	"errors"
+	"microblog/domain/like/domain"
+	postDomain "microblog/domain/post/domain"
+	"microblog/domain/shared/errs"
+	"microblog/domain/shared/page"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Harness is a like repository under test along with a way to create the
+// users who like and the posts they like, and to delete those posts.
+// CreatePost stores the post as given, the post it quotes included.
+type Harness struct {
+	Repository domain.Repository
+	NewUser    func(t *testing.T) uint
+	NewPost    func(t *testing.T, userID uint) uint
+	CreatePost func(t *testing.T, post *postDomain.Post)
+	DeletePost func(t *testing.T, postID uint)
+}
+
+// like stores the like or stops the test. It reports whether the like is
+// new.
+func like(t *testing.T, repository domain.Repository, userID, postID uint) bool {
+	t.Helper()
+
+	created, err := repository.Like(context.Background(), &domain.Like{UserID: userID, PostID: postID})
+	if err != nil {
+		t.Fatalf("error liking %d by %d: %v", postID, userID, err)
+	}
+
+	return created
+}
+
+// TestRepository runs the domain.Repository suite. newHarness must return
+// an empty repository each time it is called.
+func TestRepository(t *testing.T, newHarness func(t *testing.T) Harness) {
+	ctx := context.Background()
+
+	t.Run("Like Sets Creation Time", func(tt *testing.T) {
+		h := newHarness(tt)
+		userID := h.NewUser(tt)
+
+		l := domain.Like{UserID: userID, PostID: h.NewPost(tt, userID)}
+		created, err := h.Repository.Like(ctx, &l)
+		assert.NoError(tt, err)
+		assert.True(tt, created)
+		assert.False(tt, l.CreatedAt.IsZero())
+	})
+
+	t.Run("Like Twice Is A No-op", func(tt *testing.T) {
+		h := newHarness(tt)
+		userID := h.NewUser(tt)
+		postID := h.NewPost(tt, userID)
+
+		assert.True(tt, like(tt, h.Repository, userID, postID))
+		assert.False(tt, like(tt, h.Repository, userID, postID))
+
+		stats, err := h.Repository.Stats(ctx, userID, []uint{postID})
+		assert.NoError(tt, err)
+		assert.Equal(tt, domain.Stats{Count: 1, LikedByMe: true}, stats[postID])
+	})
+
+	t.Run("Error Unknown Post", func(tt *testing.T) {
+		h := newHarness(tt)
+
+		_, err := h.Repository.Like(ctx, &domain.Like{UserID: h.NewUser(tt), PostID: 9999})
+		assert.True(tt, errors.Is(err, errs.ErrNotFound), "%v", err)
+	})
+
+	t.Run("Likers Ordered And Paginated", func(tt *testing.T) {
+		h := newHarness(tt)
+		author := h.NewUser(tt)
+		postID := h.NewPost(tt, author)
+
+		var users []uint
+		for i := 0; i < 3; i++ {
+			userID := h.NewUser(tt)
+			like(tt, h.Repository, userID, postID)
+			users = append(users, userID)
+		}
+
+		first, info, err := h.Repository.GetLikers(ctx, postID, page.Request{Limit: 2, Order: page.Desc})
+		assert.NoError(tt, err)
+		if assert.Len(tt, first, 2) {
+			assert.Equal(tt, users[2], first[0].UserID)
+			assert.Equal(tt, users[1], first[1].UserID)
+			assert.NotEmpty(tt, first[0].Username)
+			assert.False(tt, first[0].LikedAt.IsZero())
+		}
+
+		next, err := page.DecodeCursor(info.NextCursor)
+		if !assert.NoError(tt, err) {
+			return
+		}
+
+		second, _, err := h.Repository.GetLikers(ctx, postID, page.Request{Limit: 2, Order: page.Desc, Cursor: &next})
+		assert.NoError(tt, err)
+		if assert.Len(tt, second, 1) {
+			assert.Equal(tt, users[0], second[0].UserID)
+		}
+	})
+
+	t.Run("Liked Posts Newest Like First", func(tt *testing.T) {
+		h := newHarness(tt)
+		userID := h.NewUser(tt)
+		first, second := h.NewPost(tt, userID), h.NewPost(tt, userID)
+
+		like(tt, h.Repository, userID, first)
+		like(tt, h.Repository, userID, second)
+
+		posts, info, err := h.Repository.GetLikedPosts(ctx, userID, page.Request{Limit: 10, Order: page.Desc})
+		assert.NoError(tt, err)
+		assert.Equal(tt, page.Info{}, info)
+		if assert.Len(tt, posts, 2) {
+			assert.Equal(tt, second, posts[0].ID)
+			assert.Equal(tt, first, posts[1].ID)
+			assert.NotEmpty(tt, posts[0].Body)
+			assert.False(tt, posts[0].LikedAt.IsZero())
+		}
+
+		h.DeletePost(tt, second)
+
+		posts, _, err = h.Repository.GetLikedPosts(ctx, userID, page.Request{Limit: 10, Order: page.Desc})
+		assert.NoError(tt, err)
+		if assert.Len(tt, posts, 1) {
+			assert.Equal(tt, first, posts[0].ID)
+		}
+	})
+
+	t.Run("Liked Quote Embeds Original", func(tt *testing.T) {
+		h := newHarness(tt)
+		userID, author := h.NewUser(tt), h.NewUser(tt)
+
+		original := postDomain.Post{Body: "Lorem ipsum dolor sit amet.", UserID: author}
+		h.CreatePost(tt, &original)
+		quote := postDomain.Post{Body: "So true", UserID: author, QuoteOf: &original.ID}
+		h.CreatePost(tt, &quote)
+
+		like(tt, h.Repository, userID, quote.ID)
+
+		posts, _, err := h.Repository.GetLikedPosts(ctx, userID, page.Request{Limit: 10, Order: page.Desc})
+		assert.NoError(tt, err)
+		if assert.Len(tt, posts, 1) && assert.NotNil(tt, posts[0].Original) {
+			assert.Equal(tt, quote.ID, posts[0].ID)
+			assert.Equal(tt, original.ID, posts[0].Original.ID)
+			assert.Equal(tt, original.Body, posts[0].Original.Body)
+		}
+	})
+
+	t.Run("Liked Posts Carry Mentions", func(tt *testing.T) {
+		h := newHarness(tt)
+		userID, author, mentioned := h.NewUser(tt), h.NewUser(tt), h.NewUser(tt)
+
+		original := postDomain.Post{Body: "Hi @someone", UserID: author, Mentions: []postDomain.Mention{
+			{UserID: mentioned, Start: 3, End: 11},
+		}}
+		h.CreatePost(tt, &original)
+		quote := postDomain.Post{Body: "Look @someone", UserID: author, QuoteOf: &original.ID, Mentions: []postDomain.Mention{
+			{UserID: mentioned, Start: 5, End: 13},
+		}}
+		h.CreatePost(tt, &quote)
+
+		like(tt, h.Repository, userID, original.ID)
+		like(tt, h.Repository, userID, quote.ID)
+
+		posts, _, err := h.Repository.GetLikedPosts(ctx, userID, page.Request{Limit: 10, Order: page.Desc})
+		assert.NoError(tt, err)
+		if assert.Len(tt, posts, 2) {
+			if assert.Len(tt, posts[0].Mentions, 1) {
+				assert.Equal(tt, mentioned, posts[0].Mentions[0].UserID)
+				assert.Equal(tt, 5, posts[0].Mentions[0].Start)
+				assert.Equal(tt, 13, posts[0].Mentions[0].End)
+			}
+			if assert.NotNil(tt, posts[0].Original) {
+				assert.Len(tt, posts[0].Original.Mentions, 1)
+			}
+			if assert.Len(tt, posts[1].Mentions, 1) {
+				assert.Equal(tt, mentioned, posts[1].Mentions[0].UserID)
+			}
+		}
+	})
+
+	t.Run("Stats", func(tt *testing.T) {
+		h := newHarness(tt)
+		me, other := h.NewUser(tt), h.NewUser(tt)
+		liked, unliked, quiet := h.NewPost(tt, me), h.NewPost(tt, me), h.NewPost(tt, me)
+
+		like(tt, h.Repository, me, liked)
+		like(tt, h.Repository, other, liked)
+		like(tt, h.Repository, other, unliked)
+
+		stats, err := h.Repository.Stats(ctx, me, []uint{liked, unliked, quiet})
+		assert.NoError(tt, err)
+		assert.Equal(tt, domain.Stats{Count: 2, LikedByMe: true}, stats[liked])
+		assert.Equal(tt, domain.Stats{Count: 1}, stats[unliked])
+		assert.Equal(tt, domain.Stats{}, stats[quiet])
+
+		stats, err = h.Repository.Stats(ctx, 0, []uint{liked})
+		assert.NoError(tt, err)
+		assert.Equal(tt, domain.Stats{Count: 2}, stats[liked])
+
+		stats, err = h.Repository.Stats(ctx, me, nil)
+		assert.NoError(tt, err)
+		assert.Empty(tt, stats)
+	})
+
+	t.Run("Unlike", func(tt *testing.T) {
+		h := newHarness(tt)
+		userID := h.NewUser(tt)
+		postID := h.NewPost(tt, userID)
+		like(tt, h.Repository, userID, postID)
+
+		assert.NoError(tt, h.Repository.Unlike(ctx, userID, postID))
+		assert.NoError(tt, h.Repository.Unlike(ctx, userID, postID))
+
+		likers, _, err := h.Repository.GetLikers(ctx, postID, page.Request{Limit: 10, Order: page.Desc})
+		assert.NoError(tt, err)
+		assert.Empty(tt, likers)
+
+		stats, err := h.Repository.Stats(ctx, userID, []uint{postID})
+		assert.NoError(tt, err)
+		assert.Equal(tt, domain.Stats{}, stats[postID])
+	})
+}
@@ -0,0 +1,11 @@
+package domain
+
+// Liked is published once a user likes a post.
+type Liked struct {
+	Like Like
+}
+
+// Name identifies the event.
+func (Liked) Name() string {
+	return "like.created"
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	postDomain "microblog/domain/post/domain"
+	"microblog/domain/shared/page"
+	"time"
+)
+
+// Like is a user liking a post.
+type Like struct {
+	UserID    uint      `json:"user_id,omitempty"`
+	PostID    uint      `json:"post_id,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+}
+
+// Liker is a user who liked a post.
+type Liker struct {
+	UserID    uint      `json:"id"`
+	FirstName string    `json:"first_name,omitempty"`
+	LastName  string    `json:"last_name,omitempty"`
+	Username  string    `json:"username,omitempty"`
+	Picture   string    `json:"picture,omitempty"`
+	LikedAt   time.Time `json:"liked_at"`
+}
+
+// Cursor returns the position of the liker in a list ordered by like time.
+func (l Liker) Cursor() page.Cursor {
+	return page.Cursor{CreatedAt: l.LikedAt, ID: l.UserID}
+}
+
+// LikedPost is a post liked by a user.
+type LikedPost struct {
+	postDomain.Post
+	LikedAt time.Time `json:"liked_at"`
+}
+
+// Cursor returns the position of the post in a list ordered by like time.
+func (l LikedPost) Cursor() page.Cursor {
+	return page.Cursor{CreatedAt: l.LikedAt, ID: l.ID}
+}
+
+// Stats are the likes of a post as seen by a reader.
+type Stats struct {
+	Count     int
+	LikedByMe bool
+}
+
+// Apply sets the stats on the post.
+func (s Stats) Apply(p *postDomain.Post) {
+	p.LikeCount = s.Count
+	p.LikedByMe = s.LikedByMe
+}
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"context"
+	"microblog/domain/shared/page"
+)
+
+// Repository handle the operations with the likes of the posts.
+type Repository interface {
+	// Like makes the user like the post and reports whether the like is
+	// new. Liking twice is a no-op.
+	Like(ctx context.Context, like *Like) (bool, error)
+	Unlike(ctx context.Context, userID, postID uint) error
+	// GetLikers returns a page of the users who liked the post, ordered by
+	// like time.
+	GetLikers(ctx context.Context, postID uint, p page.Request) ([]Liker, page.Info, error)
+	// GetLikedPosts returns a page of the posts the user liked, ordered by
+	// like time.
+	GetLikedPosts(ctx context.Context, userID uint, p page.Request) ([]LikedPost, page.Info, error)
+	// Stats returns the likes of each post as seen by the reader. A zero
+	// reader liked nothing.
+	Stats(ctx context.Context, readerID uint, postIDs []uint) (map[uint]Stats, error)
+}
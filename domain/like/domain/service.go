@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"context"
+	"microblog/domain/shared/event"
+)
+
+// Service applies the like rules over a Repository. The new likes are
+// published to Events when it is set.
+type Service struct {
+	Repository Repository
+	Events     event.Publisher
+}
+
+// NewService returns a Service that stores the likes in the given
+// repository.
+func NewService(repository Repository) *Service {
+	return &Service{Repository: repository}
+}
+
+// Like makes the user like the post. Liking twice is a no-op and publishes
+// nothing.
+func (s *Service) Like(ctx context.Context, like *Like) error {
+	created, err := s.Repository.Like(ctx, like)
+	if err != nil {
+		return err
+	}
+
+	if created && s.Events != nil {
+		s.Events.Publish(ctx, Liked{Like: *like})
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package domain_test
+
+import (
+	"context"
+	"errors"
+	"microblog/domain/like/domain"
+	mockLocal "microblog/domain/like/domain/mocks"
+	"microblog/domain/shared/event"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestService_Like(t *testing.T) {
+	mockRepository := &mockLocal.Repository{}
+	service := domain.NewService(mockRepository)
+
+	var published []event.Event
+	bus := event.NewBus()
+	bus.Subscribe(func(ctx context.Context, e event.Event) error {
+		published = append(published, e)
+		return nil
+	})
+	service.Events = bus
+
+	like := &domain.Like{UserID: 1, PostID: 2}
+	mockRepository.On("Like", mock.Anything, like).Return(false, errors.New("error sql")).Once()
+	assert.Error(t, service.Like(context.Background(), like))
+
+	mockRepository.On("Like", mock.Anything, like).Return(true, nil).Once()
+	assert.NoError(t, service.Like(context.Background(), like))
+
+	// liking twice is a no-op, nobody is notified again.
+	mockRepository.On("Like", mock.Anything, like).Return(false, nil).Once()
+	assert.NoError(t, service.Like(context.Background(), like))
+
+	if assert.Len(t, published, 1) {
+		assert.Equal(t, "like.created", published[0].Name())
+		assert.Equal(t, uint(2), published[0].(domain.Liked).Like.PostID)
+	}
+	mockRepository.AssertExpectations(t)
+}
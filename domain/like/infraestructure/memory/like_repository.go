@@ -0,0 +1,159 @@
+package memory
+
+import (
+	"context"
+	"microblog/domain/like/domain"
+	postDomain "microblog/domain/post/domain"
+	"microblog/domain/shared/page"
+	userDomain "microblog/domain/user/domain"
+	"sort"
+	"sync"
+	"time"
+)
+
+// LikeRepository keeps the likes in memory. It reads the users who like
+// from Users and the liked posts from Posts. It is safe for concurrent use.
+type LikeRepository struct {
+	Users userDomain.Repository
+	Posts postDomain.Repository
+
+	mu    sync.RWMutex
+	likes map[domain.Like]time.Time
+}
+
+// NewLikeRepository returns an empty LikeRepository.
+func NewLikeRepository(users userDomain.Repository, posts postDomain.Repository) *LikeRepository {
+	return &LikeRepository{Users: users, Posts: posts, likes: make(map[domain.Like]time.Time)}
+}
+
+// Like makes the user like the post and reports whether the like is new.
+// Liking twice is a no-op.
+func (lr *LikeRepository) Like(ctx context.Context, like *domain.Like) (bool, error) {
+	now := time.Now().Truncate(time.Microsecond)
+
+	if _, err := lr.Users.GetOne(ctx, like.UserID); err != nil {
+		return false, err
+	}
+
+	if _, err := lr.Posts.GetOne(ctx, like.PostID); err != nil {
+		return false, err
+	}
+
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	key := domain.Like{UserID: like.UserID, PostID: like.PostID}
+	_, exists := lr.likes[key]
+	if !exists {
+		lr.likes[key] = now
+	}
+
+	like.CreatedAt = now
+
+	return !exists, nil
+}
+
+// Unlike removes the like of the user on the post.
+func (lr *LikeRepository) Unlike(ctx context.Context, userID, postID uint) error {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	delete(lr.likes, domain.Like{UserID: userID, PostID: postID})
+
+	return nil
+}
+
+// GetLikers returns a page of the users who liked the post, skipping the
+// ones that no longer exist.
+func (lr *LikeRepository) GetLikers(ctx context.Context, postID uint, p page.Request) ([]domain.Liker, page.Info, error) {
+	var likers []domain.Liker
+	for l, likedAt := range lr.matching(func(l domain.Like) bool { return l.PostID == postID }) {
+		u, err := lr.Users.GetOne(ctx, l.UserID)
+		if err != nil {
+			continue
+		}
+
+		likers = append(likers, domain.Liker{
+			UserID:    u.ID,
+			FirstName: u.FirstName,
+			LastName:  u.LastName,
+			Username:  u.Username,
+			Picture:   u.Picture,
+			LikedAt:   likedAt,
+		})
+	}
+
+	sort.Slice(likers, func(i, j int) bool {
+		return likers[i].Cursor().Before(likers[j].Cursor(), p.Order)
+	})
+
+	from, to, info := p.Slice(len(likers), func(i int) page.Cursor {
+		return likers[i].Cursor()
+	})
+
+	return likers[from:to], info, nil
+}
+
+// GetLikedPosts returns a page of the posts the user liked, skipping the
+// ones that no longer exist.
+func (lr *LikeRepository) GetLikedPosts(ctx context.Context, userID uint, p page.Request) ([]domain.LikedPost, page.Info, error) {
+	var posts []domain.LikedPost
+	for l, likedAt := range lr.matching(func(l domain.Like) bool { return l.UserID == userID }) {
+		post, err := lr.Posts.GetOne(ctx, l.PostID)
+		if err != nil {
+			continue
+		}
+
+		posts = append(posts, domain.LikedPost{Post: post, LikedAt: likedAt})
+	}
+
+	sort.Slice(posts, func(i, j int) bool {
+		return posts[i].Cursor().Before(posts[j].Cursor(), p.Order)
+	})
+
+	from, to, info := p.Slice(len(posts), func(i int) page.Cursor {
+		return posts[i].Cursor()
+	})
+
+	return posts[from:to], info, nil
+}
+
+// Stats returns the likes of each post as seen by the reader.
+func (lr *LikeRepository) Stats(ctx context.Context, readerID uint, postIDs []uint) (map[uint]domain.Stats, error) {
+	wanted := make(map[uint]bool, len(postIDs))
+	for _, id := range postIDs {
+		wanted[id] = true
+	}
+
+	lr.mu.RLock()
+	defer lr.mu.RUnlock()
+
+	stats := make(map[uint]domain.Stats, len(postIDs))
+	for l := range lr.likes {
+		if !wanted[l.PostID] {
+			continue
+		}
+
+		s := stats[l.PostID]
+		s.Count++
+		s.LikedByMe = s.LikedByMe || (readerID != 0 && l.UserID == readerID)
+		stats[l.PostID] = s
+	}
+
+	return stats, nil
+}
+
+// matching returns a copy of the likes that match along with their time.
+func (lr *LikeRepository) matching(match func(l domain.Like) bool) map[domain.Like]time.Time {
+	lr.mu.RLock()
+	defer lr.mu.RUnlock()
+
+	likes := make(map[domain.Like]time.Time)
+	for l, likedAt := range lr.likes {
+		if match(l) {
+			likes[l] = likedAt
+		}
+	}
+
+	return likes
+}
@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	memoryFollow "microblog/domain/follow/infraestructure/memory"
+	"microblog/domain/like/domain/contract"
+	postDomain "microblog/domain/post/domain"
+	memoryPost "microblog/domain/post/infraestructure/memory"
+	userContract "microblog/domain/user/domain/contract"
+	memoryUser "microblog/domain/user/infraestructure/memory"
+	"testing"
+)
+
+func TestLikeRepository_Contract(t *testing.T) {
+	contract.TestRepository(t, func(t *testing.T) contract.Harness {
+		users := memoryUser.NewUserRepository()
+		posts := memoryPost.NewPostRepository(memoryFollow.NewFollowRepository(users))
+		n := 0
+
+		return contract.Harness{
+			Repository: NewLikeRepository(users, posts),
+			NewUser: func(t *testing.T) uint {
+				n++
+				u := userContract.NewUser(fmt.Sprintf("user.%d", n))
+				if err := users.Create(context.Background(), &u); err != nil {
+					t.Fatalf("error creating user: %v", err)
+				}
+
+				return u.ID
+			},
+			NewPost: func(t *testing.T, userID uint) uint {
+				p := postDomain.Post{Body: "Lorem ipsum dolor sit amet.", UserID: userID}
+				if err := posts.Create(context.Background(), &p); err != nil {
+					t.Fatalf("error creating post: %v", err)
+				}
+
+				return p.ID
+			},
+			CreatePost: func(t *testing.T, post *postDomain.Post) {
+				if err := posts.Create(context.Background(), post); err != nil {
+					t.Fatalf("error creating post: %v", err)
+				}
+			},
+			DeletePost: func(t *testing.T, postID uint) {
+				if err := posts.Delete(context.Background(), postID); err != nil {
+					t.Fatalf("error deleting post: %v", err)
+				}
+			},
+		}
+	})
+}
@@ -0,0 +1,192 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"microblog/domain/like/domain"
+	postDomain "microblog/domain/post/domain"
+	persistencePost "microblog/domain/post/infraestructure/persistence"
+	"microblog/domain/shared/page"
+	"time"
+
+	"github.com/lib/pq"
+	conn "microblog/infrastructure/database"
+)
+
+// LikeRepository manages the operations with the database that
+// correspond to the like model.
+type LikeRepository struct {
+	Data *conn.Data
+}
+
+// Like makes the user like the post and reports whether the like is new.
+func (lr *LikeRepository) Like(ctx context.Context, like *domain.Like) (bool, error) {
+	now := time.Now().Truncate(time.Microsecond)
+
+	stmt, err := lr.Data.DB.PrepareContext(ctx, insertLike)
+	if err != nil {
+		return false, err
+	}
+
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, like.UserID, like.PostID, now)
+	if err != nil {
+		return false, conn.Error(err, "post not found")
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	like.CreatedAt = now
+
+	return inserted > 0, nil
+}
+
+// Unlike removes the like of the user on the post.
+func (lr *LikeRepository) Unlike(ctx context.Context, userID, postID uint) error {
+	stmt, err := lr.Data.DB.PrepareContext(ctx, deleteLike)
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, userID, postID)
+
+	return err
+}
+
+// GetLikers returns a page of the users who liked the post.
+func (lr *LikeRepository) GetLikers(ctx context.Context, postID uint, p page.Request) ([]domain.Liker, page.Info, error) {
+	op, order := p.Seek()
+	after, afterID := p.Position()
+
+	rows, err := lr.Data.DB.QueryContext(ctx, fmt.Sprintf(selectLikers, op, order, order), postID, after, afterID, p.Fetch())
+	if err != nil {
+		return nil, page.Info{}, err
+	}
+
+	defer rows.Close()
+
+	var likers []domain.Liker
+	for rows.Next() {
+		var l domain.Liker
+		err := rows.Scan(&l.UserID, &l.FirstName, &l.LastName, &l.Username, &l.Picture, &l.LikedAt)
+		if err != nil {
+			return nil, page.Info{}, err
+		}
+
+		likers = append(likers, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, page.Info{}, err
+	}
+
+	keep, more := p.Trim(len(likers))
+	likers = likers[:keep]
+	if keep == 0 {
+		return likers, page.Info{}, nil
+	}
+
+	if p.Backward() {
+		for i, j := 0, keep-1; i < j; i, j = i+1, j-1 {
+			likers[i], likers[j] = likers[j], likers[i]
+		}
+	}
+
+	return likers, p.Info(likers[0].Cursor(), likers[keep-1].Cursor(), more), nil
+}
+
+// GetLikedPosts returns a page of the posts the user liked, completed with
+// their mentions and the originals they embed.
+func (lr *LikeRepository) GetLikedPosts(ctx context.Context, userID uint, p page.Request) ([]domain.LikedPost, page.Info, error) {
+	op, order := p.Seek()
+	after, afterID := p.Position()
+
+	query := fmt.Sprintf(selectLikedPosts, persistencePost.Columns, op, order, order)
+	rows, err := lr.Data.DB.QueryContext(ctx, query, userID, after, afterID, p.Fetch())
+	if err != nil {
+		return nil, page.Info{}, err
+	}
+
+	defer rows.Close()
+
+	var posts []domain.LikedPost
+	for rows.Next() {
+		var l domain.LikedPost
+		l.Post, err = persistencePost.ScanPost(rows, &l.LikedAt)
+		if err != nil {
+			return nil, page.Info{}, err
+		}
+
+		posts = append(posts, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, page.Info{}, err
+	}
+
+	keep, more := p.Trim(len(posts))
+	posts = posts[:keep]
+	if keep == 0 {
+		return posts, page.Info{}, nil
+	}
+
+	complete := make([]postDomain.Post, 0, keep)
+	for _, l := range posts {
+		complete = append(complete, l.Post)
+	}
+
+	if err := (&persistencePost.PostRepository{Data: lr.Data}).Complete(ctx, complete); err != nil {
+		return nil, page.Info{}, err
+	}
+
+	for i := range posts {
+		posts[i].Post = complete[i]
+	}
+
+	if p.Backward() {
+		for i, j := 0, keep-1; i < j; i, j = i+1, j-1 {
+			posts[i], posts[j] = posts[j], posts[i]
+		}
+	}
+
+	return posts, p.Info(posts[0].Cursor(), posts[keep-1].Cursor(), more), nil
+}
+
+// Stats returns the likes of each post as seen by the reader.
+func (lr *LikeRepository) Stats(ctx context.Context, readerID uint, postIDs []uint) (map[uint]domain.Stats, error) {
+	stats := make(map[uint]domain.Stats, len(postIDs))
+	if len(postIDs) == 0 {
+		return stats, nil
+	}
+
+	ids := make([]int64, 0, len(postIDs))
+	for _, id := range postIDs {
+		ids = append(ids, int64(id))
+	}
+
+	rows, err := lr.Data.DB.QueryContext(ctx, selectStats, readerID, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID uint
+		var s domain.Stats
+		err := rows.Scan(&postID, &s.Count, &s.LikedByMe)
+		if err != nil {
+			return nil, err
+		}
+
+		stats[postID] = s
+	}
+
+	return stats, rows.Err()
+}
@@ -0,0 +1,202 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"microblog/domain/like/domain"
+	"microblog/domain/shared/errs"
+	"microblog/domain/shared/page"
+	data "microblog/infrastructure/database"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+// represent the repository
+var (
+	dbMockLike         *sql.DB
+	likeRepositoryMock *LikeRepository
+)
+
+// NewMockLike initialize mock connection to database
+func NewMockLike() sqlmock.Sqlmock {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	dbMockLike = db
+	likeRepositoryMock = &LikeRepository{
+		Data: &data.Data{DB: dbMockLike},
+	}
+
+	return mock
+}
+
+// CloseMockLike attaches the provider and close the connection
+func CloseMockLike() {
+	err := dbMockLike.Close()
+	if err != nil {
+		log.Println("Error close database test")
+	}
+}
+
+func TestLikeRepository_Like(t *testing.T) {
+
+	t.Run("Error Unknown Post", func(tt *testing.T) {
+		mock := NewMockLike()
+		defer CloseMockLike()
+
+		prep := mock.ExpectPrepare(insertLikeTest)
+		prep.ExpectExec().WithArgs(1, 9, sqlmock.AnyArg()).
+			WillReturnError(&pq.Error{Code: "23503", Constraint: "fk_likes_posts", Message: "insert or update violates foreign key"})
+
+		_, err := likeRepositoryMock.Like(context.Background(), &domain.Like{UserID: 1, PostID: 9})
+		assert.True(tt, errors.Is(err, errs.ErrNotFound))
+		assert.Equal(tt, "post not found", err.Error())
+	})
+
+	t.Run("Like Successful", func(tt *testing.T) {
+		mock := NewMockLike()
+		defer CloseMockLike()
+
+		prep := mock.ExpectPrepare(insertLikeTest)
+		prep.ExpectExec().WithArgs(1, 2, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		like := domain.Like{UserID: 1, PostID: 2}
+		created, err := likeRepositoryMock.Like(context.Background(), &like)
+		assert.NoError(tt, err)
+		assert.True(tt, created)
+		assert.False(tt, like.CreatedAt.IsZero())
+	})
+
+	t.Run("Like Twice", func(tt *testing.T) {
+		mock := NewMockLike()
+		defer CloseMockLike()
+
+		prep := mock.ExpectPrepare(insertLikeTest)
+		prep.ExpectExec().WithArgs(1, 2, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		created, err := likeRepositoryMock.Like(context.Background(), &domain.Like{UserID: 1, PostID: 2})
+		assert.NoError(tt, err)
+		assert.False(tt, created)
+	})
+}
+
+func TestLikeRepository_Unlike(t *testing.T) {
+	mock := NewMockLike()
+	defer CloseMockLike()
+
+	prep := mock.ExpectPrepare(deleteLikeTest)
+	prep.ExpectExec().WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, likeRepositoryMock.Unlike(context.Background(), 1, 2))
+}
+
+func TestLikeRepository_GetLikers(t *testing.T) {
+	mock := NewMockLike()
+	defer CloseMockLike()
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "first_name", "last_name", "username", "picture", "created_at"}).
+		AddRow(3, "Rebecca", "Romero", "rebecca.romero", "", now).
+		AddRow(2, "Daniel", "De La Pava", "daniel.delapava", "", now).
+		AddRow(1, "Ana", "Gomez", "ana.gomez", "", now)
+
+	mock.ExpectQuery(selectLikersTest).WithArgs(7, nil, 0, 3).WillReturnRows(rows)
+
+	likers, info, err := likeRepositoryMock.GetLikers(context.Background(), 7, page.Request{Limit: 2, Order: page.Desc})
+	assert.NoError(t, err)
+	assert.Len(t, likers, 2)
+	assert.Equal(t, "rebecca.romero", likers[0].Username)
+
+	next, err := page.DecodeCursor(info.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), next.ID)
+}
+
+func TestLikeRepository_GetLikedPosts(t *testing.T) {
+	mock := NewMockLike()
+	defer CloseMockLike()
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "body", "user_id", "in_reply_to", "repost_of", "quote_of", "created_at", "updated_at", "reply_count", "liked_at"}).
+		AddRow(5, "Lorem ipsum", 2, nil, nil, nil, now, now, 1, now)
+
+	mock.ExpectQuery(selectLikedPostsTest).WithArgs(1, nil, 0, 11).WillReturnRows(rows)
+
+	posts, info, err := likeRepositoryMock.GetLikedPosts(context.Background(), 1, page.Request{Limit: 10, Order: page.Desc})
+	assert.NoError(t, err)
+	assert.Equal(t, page.Info{}, info)
+	if assert.Len(t, posts, 1) {
+		assert.Equal(t, uint(5), posts[0].ID)
+		assert.Equal(t, 1, posts[0].ReplyCount)
+		assert.Equal(t, now, posts[0].LikedAt)
+	}
+}
+
+func TestLikeRepository_GetLikedPosts_Complete(t *testing.T) {
+	mock := NewMockLike()
+	defer CloseMockLike()
+
+	now := time.Now()
+	columns := []string{"id", "body", "user_id", "in_reply_to", "repost_of", "quote_of", "created_at", "updated_at", "reply_count"}
+	rows := sqlmock.NewRows(append(columns, "liked_at")).
+		AddRow(5, "So true @jane", 2, nil, nil, 3, now, now, 0, now)
+
+	mock.ExpectQuery(selectLikedPostsTest).WithArgs(1, nil, 0, 11).WillReturnRows(rows)
+	mock.ExpectQuery(selectMentionsTest).WithArgs("{5}").
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "user_id", "username", "start_offset", "end_offset"}).AddRow(5, 4, "jane", 8, 13))
+	mock.ExpectQuery(selectOriginalsTest).WithArgs("{3}").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(3, "Lorem ipsum", 4, nil, nil, nil, now, now, 0))
+
+	posts, _, err := likeRepositoryMock.GetLikedPosts(context.Background(), 1, page.Request{Limit: 10, Order: page.Desc})
+	assert.NoError(t, err)
+	if assert.Len(t, posts, 1) {
+		assert.Len(t, posts[0].Mentions, 1)
+		if assert.NotNil(t, posts[0].Original) {
+			assert.Equal(t, uint(3), posts[0].Original.ID)
+		}
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLikeRepository_Stats(t *testing.T) {
+
+	t.Run("Without Posts", func(tt *testing.T) {
+		NewMockLike()
+		defer CloseMockLike()
+
+		stats, err := likeRepositoryMock.Stats(context.Background(), 1, nil)
+		assert.NoError(tt, err)
+		assert.Empty(tt, stats)
+	})
+
+	t.Run("Error SQL", func(tt *testing.T) {
+		mock := NewMockLike()
+		defer CloseMockLike()
+
+		mock.ExpectQuery(selectStatsTest).WillReturnError(errors.New("error sql"))
+
+		_, err := likeRepositoryMock.Stats(context.Background(), 1, []uint{1})
+		assert.Error(tt, err)
+	})
+
+	t.Run("Stats Successful", func(tt *testing.T) {
+		mock := NewMockLike()
+		defer CloseMockLike()
+
+		rows := sqlmock.NewRows([]string{"post_id", "count", "bool_or"}).AddRow(1, 2, true)
+		mock.ExpectQuery(selectStatsTest).WithArgs(1, sqlmock.AnyArg()).WillReturnRows(rows)
+
+		stats, err := likeRepositoryMock.Stats(context.Background(), 1, []uint{1, 2})
+		assert.NoError(tt, err)
+		assert.Equal(tt, domain.Stats{Count: 2, LikedByMe: true}, stats[1])
+		assert.Equal(tt, domain.Stats{}, stats[2])
+	})
+}
@@ -0,0 +1,28 @@
+package persistence
+
+const (
+
+	// insertLike is a query that inserts a new row in the likes table using the values
+	// given in order for user_id, post_id and created_at. Liking twice is a no-op.
+	insertLike = "INSERT INTO likes (user_id, post_id, created_at) VALUES ($1, $2, $3) ON CONFLICT (user_id, post_id) DO NOTHING;"
+
+	// deleteLike is a query that deletes a row in the likes table given a user_id and post_id.
+	deleteLike = "DELETE FROM likes WHERE user_id=$1 AND post_id=$2;"
+
+	// selectLikers is a query that selects a page of the users who liked the given post. It must be
+	// formatted with the comparison operator and the sort direction returned by page.Request.Seek.
+	selectLikers = "SELECT u.id, u.first_name, u.last_name, u.username, u.picture, l.created_at FROM likes l " +
+		"INNER JOIN users u ON u.id = l.user_id WHERE l.post_id = $1 AND ($2::timestamp IS NULL OR (l.created_at, u.id) %s ($2::timestamp, $3)) " +
+		"ORDER BY l.created_at %s, u.id %s LIMIT $4;"
+
+	// selectLikedPosts is a query that selects a page of the posts liked by the given user. It must be
+	// formatted with the post columns, then the comparison operator and the sort direction returned
+	// by page.Request.Seek.
+	selectLikedPosts = "SELECT %s, l.created_at FROM likes l " +
+		"INNER JOIN posts p ON p.id = l.post_id WHERE l.user_id = $1 AND ($2::timestamp IS NULL OR (l.created_at, p.id) %s ($2::timestamp, $3)) " +
+		"ORDER BY l.created_at %s, p.id %s LIMIT $4;"
+
+	// selectStats is a query that counts the likes of the given posts and tells whether the given
+	// reader is among them. Posts without likes are not returned.
+	selectStats = "SELECT post_id, count(*), bool_or(user_id = $1) FROM likes WHERE post_id = ANY($2) GROUP BY post_id;"
+)
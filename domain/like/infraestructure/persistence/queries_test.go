@@ -0,0 +1,31 @@
+package persistence
+
+const (
+
+	// insertLikeTest is a query test that inserts a new row in the likes table.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	insertLikeTest = "INSERT INTO likes \\(user_id, post_id, created_at\\) VALUES \\(\\$1, \\$2, \\$3\\) ON CONFLICT \\(user_id, post_id\\) DO NOTHING;"
+
+	// deleteLikeTest is a query that deletes a row in the likes table.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	deleteLikeTest = "DELETE FROM likes WHERE user_id\\=\\$1 AND post_id\\=\\$2;"
+
+	// selectLikersTest is the beginning of the query that selects the users who liked a post.
+	selectLikersTest = "SELECT u.id, u.first_name, u.last_name, u.username, u.picture, l.created_at FROM likes l"
+
+	// selectLikedPostsTest is the end of the query that selects the posts liked by a user.
+	selectLikedPostsTest = "l.created_at FROM likes l INNER JOIN posts p"
+
+	// selectMentionsTest is the beginning of the query that selects the mentions of posts.
+	selectMentionsTest = "SELECT (.+) FROM post_mentions m"
+
+	// selectOriginalsTest is the end of the query that selects the originals of reposts and quotes.
+	selectOriginalsTest = "SELECT (.+) WHERE p.id = ANY"
+
+	// selectStatsTest is a query that counts the likes of the given posts.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	selectStatsTest = "SELECT post_id, count\\(\\*\\), bool_or\\(user_id \\= \\$1\\) FROM likes WHERE post_id \\= ANY\\(\\$2\\) GROUP BY post_id;"
+)
@@ -0,0 +1,132 @@
+package v1
+
+import (
+	"microblog/domain/notification/domain"
+	"microblog/domain/shared/page"
+	"microblog/domain/shared/response"
+	"microblog/infrastructure/auth"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+)
+
+// NotificationRouter is the router of the notifications of the
+// authenticated user.
+type NotificationRouter struct {
+	Repository domain.Repository
+}
+
+// NotificationPage is a page of notifications along with the cursors of
+// its neighbours.
+type NotificationPage struct {
+	Items []domain.Notification `json:"items"`
+	page.Info
+}
+
+// UnreadCount is the number of unread notifications of a user.
+type UnreadCount struct {
+	UnreadCount int `json:"unread_count"`
+}
+
+// GetAllHandler response a page of the notifications of the authenticated
+// user, newest first. With unread=true only the unread ones are listed.
+func (nr *NotificationRouter) GetAllHandler(w http.ResponseWriter, r *http.Request) {
+	principal, ok := auth.FromContext(r.Context())
+	if !ok {
+		response.HTTPError(w, r, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	query := r.URL.Query()
+	p, err := page.FromQuery(query)
+	if err != nil {
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filter := domain.Filter{UserID: principal.UserID}
+	if unread := query.Get("unread"); unread != "" {
+		filter.UnreadOnly, err = strconv.ParseBool(unread)
+		if err != nil {
+			response.HTTPError(w, r, http.StatusBadRequest, "unread must be a boolean")
+			return
+		}
+	}
+
+	ctx := r.Context()
+	notifications, info, err := nr.Repository.GetAll(ctx, filter, p)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	if notifications == nil {
+		notifications = []domain.Notification{}
+	}
+
+	response.JSON(w, r, http.StatusOK, NotificationPage{Items: notifications, Info: info})
+}
+
+// MarkReadHandler marks the notification by id of the authenticated user
+// as read.
+func (nr *NotificationRouter) MarkReadHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	principal, ok := auth.FromContext(r.Context())
+	if !ok {
+		response.HTTPError(w, r, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	ctx := r.Context()
+	err = nr.Repository.MarkRead(ctx, principal.UserID, uint(id))
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, r, http.StatusNoContent, nil)
+}
+
+// MarkAllReadHandler marks every notification of the authenticated user as
+// read.
+func (nr *NotificationRouter) MarkAllReadHandler(w http.ResponseWriter, r *http.Request) {
+	principal, ok := auth.FromContext(r.Context())
+	if !ok {
+		response.HTTPError(w, r, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	ctx := r.Context()
+	err := nr.Repository.MarkAllRead(ctx, principal.UserID)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, r, http.StatusNoContent, nil)
+}
+
+// UnreadCountHandler response the number of unread notifications of the
+// authenticated user.
+func (nr *NotificationRouter) UnreadCountHandler(w http.ResponseWriter, r *http.Request) {
+	principal, ok := auth.FromContext(r.Context())
+	if !ok {
+		response.HTTPError(w, r, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	ctx := r.Context()
+	count, err := nr.Repository.CountUnread(ctx, principal.UserID)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, r, http.StatusOK, UnreadCount{UnreadCount: count})
+}
@@ -0,0 +1,154 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"microblog/domain/notification/domain"
+	mockLocal "microblog/domain/notification/domain/mocks"
+	"microblog/domain/shared/errs"
+	"microblog/domain/shared/page"
+	"microblog/infrastructure/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newRequest returns a request for the id URL param, authenticated as
+// principalID unless it is zero.
+func newRequest(method string, target string, id string, principalID uint) *http.Request {
+	request := httptest.NewRequest(method, target, nil)
+
+	requestCtx := chi.NewRouteContext()
+	requestCtx.URLParams.Add("id", id)
+	ctx := context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx)
+
+	if principalID != 0 {
+		ctx = auth.NewContext(ctx, auth.Principal{UserID: principalID})
+	}
+
+	return request.WithContext(ctx)
+}
+
+func TestNotificationRouter_GetAllHandler(t *testing.T) {
+
+	t.Run("Error Unauthenticated Get All Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testNotificationHandler := &NotificationRouter{Repository: mockRepository}
+
+		testNotificationHandler.GetAllHandler(response, newRequest(http.MethodGet, "/api/v1/notifications", "", 0))
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Error Unread Get All Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testNotificationHandler := &NotificationRouter{Repository: mockRepository}
+
+		testNotificationHandler.GetAllHandler(response, newRequest(http.MethodGet, "/api/v1/notifications?unread=maybe", "", 1))
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Error SQL Get All Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testNotificationHandler := &NotificationRouter{Repository: mockRepository}
+		mockRepository.On("GetAll", mock.Anything, domain.Filter{UserID: 1}, mock.Anything).Return(nil, page.Info{}, errors.New("error sql")).Once()
+
+		testNotificationHandler.GetAllHandler(response, newRequest(http.MethodGet, "/api/v1/notifications", "", 1))
+		assert.Equal(tt, http.StatusInternalServerError, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Get All Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testNotificationHandler := &NotificationRouter{Repository: mockRepository}
+		notifications := []domain.Notification{{ID: 3, UserID: 1, ActorID: 2, Kind: domain.KindFollow}}
+		mockRepository.On("GetAll", mock.Anything, domain.Filter{UserID: 1, UnreadOnly: true}, mock.Anything).Return(notifications, page.Info{}, nil).Once()
+
+		testNotificationHandler.GetAllHandler(response, newRequest(http.MethodGet, "/api/v1/notifications?unread=true", "", 1))
+		assert.Equal(tt, http.StatusOK, response.Code)
+
+		var got NotificationPage
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&got))
+		if assert.Len(tt, got.Items, 1) {
+			assert.Equal(tt, domain.KindFollow, got.Items[0].Kind)
+		}
+		mockRepository.AssertExpectations(tt)
+	})
+}
+
+func TestNotificationRouter_MarkReadHandler(t *testing.T) {
+
+	t.Run("Error Param Mark Read Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testNotificationHandler := &NotificationRouter{Repository: mockRepository}
+
+		testNotificationHandler.MarkReadHandler(response, newRequest(http.MethodPost, "/api/v1/notifications/abc/read", "abc", 1))
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Error Not Found Mark Read Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testNotificationHandler := &NotificationRouter{Repository: mockRepository}
+		mockRepository.On("MarkRead", mock.Anything, uint(1), uint(9)).Return(errs.NotFound("notification not found")).Once()
+
+		testNotificationHandler.MarkReadHandler(response, newRequest(http.MethodPost, "/api/v1/notifications/9/read", "9", 1))
+		assert.Equal(tt, http.StatusNotFound, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Mark Read Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testNotificationHandler := &NotificationRouter{Repository: mockRepository}
+		mockRepository.On("MarkRead", mock.Anything, uint(1), uint(3)).Return(nil).Once()
+
+		testNotificationHandler.MarkReadHandler(response, newRequest(http.MethodPost, "/api/v1/notifications/3/read", "3", 1))
+		assert.Equal(tt, http.StatusNoContent, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+}
+
+func TestNotificationRouter_MarkAllReadHandler(t *testing.T) {
+	response := httptest.NewRecorder()
+	mockRepository := &mockLocal.Repository{}
+
+	testNotificationHandler := &NotificationRouter{Repository: mockRepository}
+	mockRepository.On("MarkAllRead", mock.Anything, uint(1)).Return(nil).Once()
+
+	testNotificationHandler.MarkAllReadHandler(response, newRequest(http.MethodPost, "/api/v1/notifications/read", "", 1))
+	assert.Equal(t, http.StatusNoContent, response.Code)
+	mockRepository.AssertExpectations(t)
+}
+
+func TestNotificationRouter_UnreadCountHandler(t *testing.T) {
+	response := httptest.NewRecorder()
+	mockRepository := &mockLocal.Repository{}
+
+	testNotificationHandler := &NotificationRouter{Repository: mockRepository}
+	mockRepository.On("CountUnread", mock.Anything, uint(1)).Return(4, nil).Once()
+
+	testNotificationHandler.UnreadCountHandler(response, newRequest(http.MethodGet, "/api/v1/notifications/unread-count", "", 1))
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"unread_count":4}`, response.Body.String())
+	mockRepository.AssertExpectations(t)
+}
@@ -0,0 +1,165 @@
+// Package contract holds the conformance suite every adapter of the
+// notification port must pass, whatever its storage.
+package contract
+
+import (
+	"context"
+	"errors"
+	"microblog/domain/notification/domain"
+	"microblog/domain/shared/errs"
+	"microblog/domain/shared/page"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Harness is a notification repository under test along with a way to
+// create the users notified and acting, and the posts involved.
+type Harness struct {
+	Repository domain.Repository
+	NewUser    func(t *testing.T) uint
+	NewPost    func(t *testing.T, userID uint) uint
+}
+
+// notify stores the notification or stops the test.
+func notify(t *testing.T, repository domain.Repository, n domain.Notification) domain.Notification {
+	t.Helper()
+
+	if err := repository.Create(context.Background(), &n); err != nil {
+		t.Fatalf("error creating %s notification of %d: %v", n.Kind, n.UserID, err)
+	}
+
+	return n
+}
+
+// ids returns the ids of the notifications in order.
+func ids(notifications []domain.Notification) []uint {
+	result := make([]uint, 0, len(notifications))
+	for _, n := range notifications {
+		result = append(result, n.ID)
+	}
+
+	return result
+}
+
+// TestRepository runs the domain.Repository suite. newHarness must return
+// an empty repository each time it is called.
+func TestRepository(t *testing.T, newHarness func(t *testing.T) Harness) {
+	ctx := context.Background()
+
+	t.Run("Create Sets Id And Creation Time", func(tt *testing.T) {
+		h := newHarness(tt)
+		me, actor := h.NewUser(tt), h.NewUser(tt)
+		postID := h.NewPost(tt, me)
+
+		n := notify(tt, h.Repository, domain.Notification{UserID: me, ActorID: actor, Kind: domain.KindLike, PostID: &postID})
+		assert.NotZero(tt, n.ID)
+		assert.False(tt, n.CreatedAt.IsZero())
+
+		all, _, err := h.Repository.GetAll(ctx, domain.Filter{UserID: me}, page.Request{Limit: 10, Order: page.Desc})
+		assert.NoError(tt, err)
+		if assert.Len(tt, all, 1) {
+			assert.Equal(tt, actor, all[0].ActorID)
+			assert.Equal(tt, domain.KindLike, all[0].Kind)
+			assert.False(tt, all[0].Read)
+			if assert.NotNil(tt, all[0].PostID) {
+				assert.Equal(tt, postID, *all[0].PostID)
+			}
+		}
+	})
+
+	t.Run("Create Twice Is A No-op", func(tt *testing.T) {
+		h := newHarness(tt)
+		me, actor := h.NewUser(tt), h.NewUser(tt)
+		postID := h.NewPost(tt, me)
+
+		notify(tt, h.Repository, domain.Notification{UserID: me, ActorID: actor, Kind: domain.KindFollow})
+		again := notify(tt, h.Repository, domain.Notification{UserID: me, ActorID: actor, Kind: domain.KindFollow})
+		assert.Zero(tt, again.ID)
+
+		notify(tt, h.Repository, domain.Notification{UserID: me, ActorID: actor, Kind: domain.KindLike, PostID: &postID})
+		notify(tt, h.Repository, domain.Notification{UserID: me, ActorID: actor, Kind: domain.KindLike, PostID: &postID})
+
+		count, err := h.Repository.CountUnread(ctx, me)
+		assert.NoError(tt, err)
+		assert.Equal(tt, 2, count)
+	})
+
+	t.Run("Ordered Paginated And Filtered", func(tt *testing.T) {
+		h := newHarness(tt)
+		me := h.NewUser(tt)
+
+		var created []uint
+		for i := 0; i < 3; i++ {
+			n := notify(tt, h.Repository, domain.Notification{UserID: me, ActorID: h.NewUser(tt), Kind: domain.KindFollow})
+			created = append(created, n.ID)
+		}
+		notify(tt, h.Repository, domain.Notification{UserID: h.NewUser(tt), ActorID: me, Kind: domain.KindFollow})
+
+		first, info, err := h.Repository.GetAll(ctx, domain.Filter{UserID: me}, page.Request{Limit: 2, Order: page.Desc})
+		assert.NoError(tt, err)
+		assert.Equal(tt, []uint{created[2], created[1]}, ids(first))
+
+		next, err := page.DecodeCursor(info.NextCursor)
+		if !assert.NoError(tt, err) {
+			return
+		}
+
+		second, _, err := h.Repository.GetAll(ctx, domain.Filter{UserID: me}, page.Request{Limit: 2, Order: page.Desc, Cursor: &next})
+		assert.NoError(tt, err)
+		assert.Equal(tt, []uint{created[0]}, ids(second))
+
+		assert.NoError(tt, h.Repository.MarkRead(ctx, me, created[1]))
+
+		unread, _, err := h.Repository.GetAll(ctx, domain.Filter{UserID: me, UnreadOnly: true}, page.Request{Limit: 10, Order: page.Desc})
+		assert.NoError(tt, err)
+		assert.Equal(tt, []uint{created[2], created[0]}, ids(unread))
+
+		all, _, err := h.Repository.GetAll(ctx, domain.Filter{UserID: me}, page.Request{Limit: 10, Order: page.Desc})
+		assert.NoError(tt, err)
+		if assert.Len(tt, all, 3) {
+			assert.True(tt, all[1].Read)
+		}
+	})
+
+	t.Run("Mark Read", func(tt *testing.T) {
+		h := newHarness(tt)
+		me, other := h.NewUser(tt), h.NewUser(tt)
+		n := notify(tt, h.Repository, domain.Notification{UserID: me, ActorID: other, Kind: domain.KindFollow})
+
+		err := h.Repository.MarkRead(ctx, other, n.ID)
+		assert.True(tt, errors.Is(err, errs.ErrNotFound), "%v", err)
+
+		err = h.Repository.MarkRead(ctx, me, 9999)
+		assert.True(tt, errors.Is(err, errs.ErrNotFound), "%v", err)
+
+		assert.NoError(tt, h.Repository.MarkRead(ctx, me, n.ID))
+		assert.NoError(tt, h.Repository.MarkRead(ctx, me, n.ID))
+
+		count, err := h.Repository.CountUnread(ctx, me)
+		assert.NoError(tt, err)
+		assert.Zero(tt, count)
+	})
+
+	t.Run("Mark All Read", func(tt *testing.T) {
+		h := newHarness(tt)
+		me, other := h.NewUser(tt), h.NewUser(tt)
+		notify(tt, h.Repository, domain.Notification{UserID: me, ActorID: other, Kind: domain.KindFollow})
+		notify(tt, h.Repository, domain.Notification{UserID: me, ActorID: h.NewUser(tt), Kind: domain.KindFollow})
+		notify(tt, h.Repository, domain.Notification{UserID: other, ActorID: me, Kind: domain.KindFollow})
+
+		count, err := h.Repository.CountUnread(ctx, me)
+		assert.NoError(tt, err)
+		assert.Equal(tt, 2, count)
+
+		assert.NoError(tt, h.Repository.MarkAllRead(ctx, me))
+
+		count, err = h.Repository.CountUnread(ctx, me)
+		assert.NoError(tt, err)
+		assert.Zero(tt, count)
+
+		count, err = h.Repository.CountUnread(ctx, other)
+		assert.NoError(tt, err)
+		assert.Equal(tt, 1, count)
+	})
+}
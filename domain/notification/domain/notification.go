@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"microblog/domain/shared/errs"
+	"microblog/domain/shared/page"
+	"time"
+)
+
+// Kind is what a notification tells its recipient about.
+type Kind string
+
+// Kinds of notifications.
+const (
+	KindFollow  Kind = "follow"
+	KindLike    Kind = "like"
+	KindReply   Kind = "reply"
+	KindMention Kind = "mention"
+	KindRepost  Kind = "repost"
+	KindQuote   Kind = "quote"
+)
+
+// ErrNotificationNotFound is returned when no notification of the user
+// matches.
+var ErrNotificationNotFound = errs.NotFound("notification not found")
+
+// Notification tells the user UserID that the user ActorID did something
+// that concerns it. PostID is the post involved: the liked or reposted post,
+// or the reply, quote or post that mentions the user. Follows involve no
+// post.
+type Notification struct {
+	ID        uint      `json:"id,omitempty"`
+	UserID    uint      `json:"user_id,omitempty"`
+	ActorID   uint      `json:"actor_id,omitempty"`
+	Kind      Kind      `json:"kind"`
+	PostID    *uint     `json:"post_id,omitempty"`
+	Read      bool      `json:"read"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+}
+
+// Cursor returns the position of the notification in a list ordered by
+// creation.
+func (n Notification) Cursor() page.Cursor {
+	return page.Cursor{CreatedAt: n.CreatedAt, ID: n.ID}
+}
+
+// Filter selects the notifications of a user.
+type Filter struct {
+	UserID     uint
+	UnreadOnly bool
+}
@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"context"
+	followDomain "microblog/domain/follow/domain"
+	likeDomain "microblog/domain/like/domain"
+	postDomain "microblog/domain/post/domain"
+	"microblog/domain/shared/event"
+)
+
+// Notifier turns the events of the other domains into notifications. It
+// reads the posts the events refer to from Posts.
+type Notifier struct {
+	Repository Repository
+	Posts      postDomain.Repository
+}
+
+// Handle records the notifications the event produces. It is meant to be
+// subscribed to the event bus; the other events are ignored.
+func (n *Notifier) Handle(ctx context.Context, e event.Event) error {
+	switch e := e.(type) {
+	case followDomain.Followed:
+		return n.notify(ctx, Notification{UserID: e.Follow.FollowingID, ActorID: e.Follow.FollowerID, Kind: KindFollow})
+
+	case likeDomain.Liked:
+		post, err := n.Posts.GetOne(ctx, e.Like.PostID)
+		if err != nil {
+			return err
+		}
+
+		return n.notify(ctx, Notification{UserID: post.UserID, ActorID: e.Like.UserID, Kind: KindLike, PostID: &post.ID})
+
+	case postDomain.PostCreated:
+		return n.postCreated(ctx, e.Post)
+
+	case postDomain.PostUpdated:
+		return n.mentioned(ctx, e.Post, e.Mentioned, make(map[uint]bool))
+	}
+
+	return nil
+}
+
+// postCreated notifies the author of the post a new post reposts, quotes or
+// replies to, and the users it mentions. A user is notified once per post.
+func (n *Notifier) postCreated(ctx context.Context, post postDomain.Post) error {
+	notified := make(map[uint]bool)
+
+	if post.IsRepost() {
+		original, err := n.Posts.GetOne(ctx, *post.RepostOf)
+		if err != nil {
+			return err
+		}
+
+		return n.notify(ctx, Notification{UserID: original.UserID, ActorID: post.UserID, Kind: KindRepost, PostID: &original.ID})
+	}
+
+	referenced := []struct {
+		id   *uint
+		kind Kind
+	}{
+		{post.QuoteOf, KindQuote},
+		{post.InReplyTo, KindReply},
+	}
+
+	for _, ref := range referenced {
+		if ref.id == nil {
+			continue
+		}
+
+		target, err := n.Posts.GetOne(ctx, *ref.id)
+		if err != nil {
+			return err
+		}
+
+		if notified[target.UserID] {
+			continue
+		}
+
+		notified[target.UserID] = true
+		if err := n.notify(ctx, Notification{UserID: target.UserID, ActorID: post.UserID, Kind: ref.kind, PostID: &post.ID}); err != nil {
+			return err
+		}
+	}
+
+	return n.mentioned(ctx, post, post.Mentions, notified)
+}
+
+// mentioned notifies the users of mentions that the post mentions them,
+// skipping the ones already notified.
+func (n *Notifier) mentioned(ctx context.Context, post postDomain.Post, mentions []postDomain.Mention, notified map[uint]bool) error {
+	for _, m := range mentions {
+		if notified[m.UserID] {
+			continue
+		}
+
+		notified[m.UserID] = true
+		if err := n.notify(ctx, Notification{UserID: m.UserID, ActorID: post.UserID, Kind: KindMention, PostID: &post.ID}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// notify stores the notification unless users would be notified of their
+// own actions.
+func (n *Notifier) notify(ctx context.Context, notification Notification) error {
+	if notification.UserID == notification.ActorID {
+		return nil
+	}
+
+	return n.Repository.Create(ctx, &notification)
+}
@@ -0,0 +1,120 @@
+package domain_test
+
+import (
+	"context"
+	followDomain "microblog/domain/follow/domain"
+	likeDomain "microblog/domain/like/domain"
+	"microblog/domain/notification/domain"
+	mockLocal "microblog/domain/notification/domain/mocks"
+	postDomain "microblog/domain/post/domain"
+	mockPost "microblog/domain/post/domain/mocks"
+	"microblog/domain/shared/errs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// notification matches a notification of the kind from the actor to the user.
+func notification(userID, actorID uint, kind domain.Kind) interface{} {
+	return mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.UserID == userID && n.ActorID == actorID && n.Kind == kind
+	})
+}
+
+func TestNotifier_Handle(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Followed", func(tt *testing.T) {
+		mockRepository := &mockLocal.Repository{}
+		notifier := &domain.Notifier{Repository: mockRepository}
+		mockRepository.On("Create", mock.Anything, notification(2, 1, domain.KindFollow)).Return(nil).Once()
+
+		err := notifier.Handle(ctx, followDomain.Followed{Follow: followDomain.Follow{FollowerID: 1, FollowingID: 2}})
+		assert.NoError(tt, err)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Liked", func(tt *testing.T) {
+		mockRepository := &mockLocal.Repository{}
+		mockPosts := &mockPost.Repository{}
+		notifier := &domain.Notifier{Repository: mockRepository, Posts: mockPosts}
+		mockPosts.On("GetOne", mock.Anything, uint(7)).Return(postDomain.Post{ID: 7, UserID: 2}, nil).Once()
+		mockRepository.On("Create", mock.Anything, notification(2, 1, domain.KindLike)).Return(nil).Once()
+
+		err := notifier.Handle(ctx, likeDomain.Liked{Like: likeDomain.Like{UserID: 1, PostID: 7}})
+		assert.NoError(tt, err)
+		mockRepository.AssertExpectations(tt)
+		mockPosts.AssertExpectations(tt)
+	})
+
+	t.Run("Error Liked Post Not Found", func(tt *testing.T) {
+		mockRepository := &mockLocal.Repository{}
+		mockPosts := &mockPost.Repository{}
+		notifier := &domain.Notifier{Repository: mockRepository, Posts: mockPosts}
+		mockPosts.On("GetOne", mock.Anything, uint(7)).Return(postDomain.Post{}, errs.NotFound("post not found")).Once()
+
+		err := notifier.Handle(ctx, likeDomain.Liked{Like: likeDomain.Like{UserID: 1, PostID: 7}})
+		assert.Error(tt, err)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Own Like Is Not Notified", func(tt *testing.T) {
+		mockRepository := &mockLocal.Repository{}
+		mockPosts := &mockPost.Repository{}
+		notifier := &domain.Notifier{Repository: mockRepository, Posts: mockPosts}
+		mockPosts.On("GetOne", mock.Anything, uint(7)).Return(postDomain.Post{ID: 7, UserID: 1}, nil).Once()
+
+		err := notifier.Handle(ctx, likeDomain.Liked{Like: likeDomain.Like{UserID: 1, PostID: 7}})
+		assert.NoError(tt, err)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Repost", func(tt *testing.T) {
+		mockRepository := &mockLocal.Repository{}
+		mockPosts := &mockPost.Repository{}
+		notifier := &domain.Notifier{Repository: mockRepository, Posts: mockPosts}
+		originalID := uint(7)
+		mockPosts.On("GetOne", mock.Anything, originalID).Return(postDomain.Post{ID: 7, UserID: 2}, nil).Once()
+		mockRepository.On("Create", mock.Anything, mock.MatchedBy(func(n *domain.Notification) bool {
+			return n.UserID == 2 && n.Kind == domain.KindRepost && *n.PostID == originalID
+		})).Return(nil).Once()
+
+		err := notifier.Handle(ctx, postDomain.PostCreated{Post: postDomain.Post{ID: 9, UserID: 1, RepostOf: &originalID}})
+		assert.NoError(tt, err)
+		mockRepository.AssertExpectations(tt)
+		mockPosts.AssertExpectations(tt)
+	})
+
+	t.Run("Reply Mentioning The Author And Others", func(tt *testing.T) {
+		mockRepository := &mockLocal.Repository{}
+		mockPosts := &mockPost.Repository{}
+		notifier := &domain.Notifier{Repository: mockRepository, Posts: mockPosts}
+		parentID := uint(7)
+		mockPosts.On("GetOne", mock.Anything, parentID).Return(postDomain.Post{ID: 7, UserID: 2}, nil).Once()
+		mockRepository.On("Create", mock.Anything, notification(2, 1, domain.KindReply)).Return(nil).Once()
+		mockRepository.On("Create", mock.Anything, notification(3, 1, domain.KindMention)).Return(nil).Once()
+
+		post := postDomain.Post{
+			ID:        9,
+			UserID:    1,
+			InReplyTo: &parentID,
+			Mentions:  []postDomain.Mention{{UserID: 2}, {UserID: 3}, {UserID: 1}},
+		}
+		assert.NoError(tt, notifier.Handle(ctx, postDomain.PostCreated{Post: post}))
+		mockRepository.AssertExpectations(tt)
+		mockPosts.AssertExpectations(tt)
+	})
+	t.Run("Edit Mentioning New Users", func(tt *testing.T) {
+		mockRepository := &mockLocal.Repository{}
+		notifier := &domain.Notifier{Repository: mockRepository}
+		mockRepository.On("Create", mock.Anything, mock.MatchedBy(func(n *domain.Notification) bool {
+			return n.UserID == 3 && n.ActorID == 1 && n.Kind == domain.KindMention && n.PostID != nil && *n.PostID == 9
+		})).Return(nil).Once()
+
+		post := postDomain.Post{ID: 9, UserID: 1, Mentions: []postDomain.Mention{{UserID: 2}, {UserID: 3}, {UserID: 1}}}
+		mentioned := []postDomain.Mention{{UserID: 3}, {UserID: 1}}
+		assert.NoError(tt, notifier.Handle(ctx, postDomain.PostUpdated{Post: post, Mentioned: mentioned}))
+		mockRepository.AssertExpectations(tt)
+	})
+}
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"context"
+	"microblog/domain/shared/page"
+)
+
+// Repository handle the operations with the notifications of the users.
+type Repository interface {
+	// Create adds a new notification. A notification the user already has,
+	// same actor, kind and post, is not added again and keeps a zero ID.
+	Create(ctx context.Context, notification *Notification) error
+	// GetAll returns a page of the notifications that match the filter,
+	// ordered by creation.
+	GetAll(ctx context.Context, filter Filter, p page.Request) ([]Notification, page.Info, error)
+	// MarkRead marks the notification by id of the user as read. It returns
+	// ErrNotificationNotFound when the user has no such notification.
+	MarkRead(ctx context.Context, userID, id uint) error
+	// MarkAllRead marks every notification of the user as read.
+	MarkAllRead(ctx context.Context, userID uint) error
+	// CountUnread returns the number of unread notifications of the user.
+	CountUnread(ctx context.Context, userID uint) (int, error)
+}
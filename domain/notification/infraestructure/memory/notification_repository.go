@@ -0,0 +1,133 @@
+package memory
+
+import (
+	"context"
+	"microblog/domain/notification/domain"
+	"microblog/domain/shared/page"
+	"sort"
+	"sync"
+	"time"
+)
+
+// NotificationRepository keeps the notifications in memory. It is safe for
+// concurrent use.
+type NotificationRepository struct {
+	mu            sync.RWMutex
+	lastID        uint
+	notifications map[uint]domain.Notification
+}
+
+// NewNotificationRepository returns an empty NotificationRepository.
+func NewNotificationRepository() *NotificationRepository {
+	return &NotificationRepository{notifications: make(map[uint]domain.Notification)}
+}
+
+// Create adds a new notification unless the user already has it.
+func (nr *NotificationRepository) Create(ctx context.Context, n *domain.Notification) error {
+	now := time.Now().Truncate(time.Microsecond)
+
+	nr.mu.Lock()
+	defer nr.mu.Unlock()
+
+	for _, stored := range nr.notifications {
+		if same(stored, *n) {
+			return nil
+		}
+	}
+
+	nr.lastID++
+	n.ID = nr.lastID
+	n.Read = false
+	n.CreatedAt = now
+
+	stored := *n
+	if n.PostID != nil {
+		postID := *n.PostID
+		stored.PostID = &postID
+	}
+
+	nr.notifications[n.ID] = stored
+
+	return nil
+}
+
+// same reports whether the notifications tell the same user the same thing.
+func same(a, b domain.Notification) bool {
+	if a.UserID != b.UserID || a.ActorID != b.ActorID || a.Kind != b.Kind {
+		return false
+	}
+
+	if a.PostID == nil || b.PostID == nil {
+		return a.PostID == nil && b.PostID == nil
+	}
+
+	return *a.PostID == *b.PostID
+}
+
+// GetAll returns a page of the notifications that match the filter.
+func (nr *NotificationRepository) GetAll(ctx context.Context, filter domain.Filter, p page.Request) ([]domain.Notification, page.Info, error) {
+	nr.mu.RLock()
+	var notifications []domain.Notification
+	for _, n := range nr.notifications {
+		if n.UserID == filter.UserID && !(filter.UnreadOnly && n.Read) {
+			notifications = append(notifications, n)
+		}
+	}
+	nr.mu.RUnlock()
+
+	sort.Slice(notifications, func(i, j int) bool {
+		return notifications[i].Cursor().Before(notifications[j].Cursor(), p.Order)
+	})
+
+	from, to, info := p.Slice(len(notifications), func(i int) page.Cursor {
+		return notifications[i].Cursor()
+	})
+
+	return notifications[from:to], info, nil
+}
+
+// MarkRead marks the notification by id of the user as read.
+func (nr *NotificationRepository) MarkRead(ctx context.Context, userID, id uint) error {
+	nr.mu.Lock()
+	defer nr.mu.Unlock()
+
+	n, ok := nr.notifications[id]
+	if !ok || n.UserID != userID {
+		return domain.ErrNotificationNotFound
+	}
+
+	n.Read = true
+	nr.notifications[id] = n
+
+	return nil
+}
+
+// MarkAllRead marks every notification of the user as read.
+func (nr *NotificationRepository) MarkAllRead(ctx context.Context, userID uint) error {
+	nr.mu.Lock()
+	defer nr.mu.Unlock()
+
+	for id, n := range nr.notifications {
+		if n.UserID == userID && !n.Read {
+			n.Read = true
+			nr.notifications[id] = n
+		}
+	}
+
+	return nil
+}
+
+// CountUnread returns the number of unread notifications of the user.
+func (nr *NotificationRepository) CountUnread(ctx context.Context, userID uint) (int, error) {
+	nr.mu.RLock()
+	defer nr.mu.RUnlock()
+
+	count := 0
+	for _, n := range nr.notifications {
+		if n.UserID == userID && !n.Read {
+			count++
+		}
+	}
+
+	return count, nil
+}
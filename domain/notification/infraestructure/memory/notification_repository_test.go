@@ -0,0 +1,24 @@
+package memory
+
+import (
+	"microblog/domain/notification/domain/contract"
+	"testing"
+)
+
+func TestNotificationRepository_Contract(t *testing.T) {
+	contract.TestRepository(t, func(t *testing.T) contract.Harness {
+		users, posts := uint(0), uint(0)
+
+		return contract.Harness{
+			Repository: NewNotificationRepository(),
+			NewUser: func(t *testing.T) uint {
+				users++
+				return users
+			},
+			NewPost: func(t *testing.T, userID uint) uint {
+				posts++
+				return posts
+			},
+		}
+	})
+}
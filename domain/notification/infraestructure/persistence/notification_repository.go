@@ -0,0 +1,123 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"microblog/domain/notification/domain"
+	"microblog/domain/shared/page"
+	"time"
+
+	conn "microblog/infrastructure/database"
+)
+
+// errNotificationNotFound is the message of the error returned when no
+// notification matches.
+const errNotificationNotFound = "notification not found"
+
+// NotificationRepository manages the operations with the database that
+// correspond to the notification model.
+type NotificationRepository struct {
+	Data *conn.Data
+}
+
+// Create adds a new notification unless the user already has it.
+func (nr *NotificationRepository) Create(ctx context.Context, n *domain.Notification) error {
+	now := time.Now().Truncate(time.Microsecond)
+
+	var postID sql.NullInt64
+	if n.PostID != nil {
+		postID = sql.NullInt64{Int64: int64(*n.PostID), Valid: true}
+	}
+
+	row := nr.Data.DB.QueryRowContext(ctx, insertNotification, n.UserID, n.ActorID, n.Kind, postID, now)
+
+	err := row.Scan(&n.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+
+	if err != nil {
+		return conn.Error(err, errNotificationNotFound)
+	}
+
+	n.Read = false
+	n.CreatedAt = now
+
+	return nil
+}
+
+// GetAll returns a page of the notifications that match the filter.
+func (nr *NotificationRepository) GetAll(ctx context.Context, filter domain.Filter, p page.Request) ([]domain.Notification, page.Info, error) {
+	op, order := p.Seek()
+	after, afterID := p.Position()
+
+	query := fmt.Sprintf(selectNotifications, op, order, order)
+	rows, err := nr.Data.DB.QueryContext(ctx, query, filter.UserID, filter.UnreadOnly, after, afterID, p.Fetch())
+	if err != nil {
+		return nil, page.Info{}, err
+	}
+
+	defer rows.Close()
+
+	var notifications []domain.Notification
+	for rows.Next() {
+		var n domain.Notification
+		var postID sql.NullInt64
+		err := rows.Scan(&n.ID, &n.UserID, &n.ActorID, &n.Kind, &postID, &n.Read, &n.CreatedAt)
+		if err != nil {
+			return nil, page.Info{}, err
+		}
+
+		if postID.Valid {
+			id := uint(postID.Int64)
+			n.PostID = &id
+		}
+
+		notifications = append(notifications, n)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, page.Info{}, err
+	}
+
+	keep, more := p.Trim(len(notifications))
+	notifications = notifications[:keep]
+	if keep == 0 {
+		return notifications, page.Info{}, nil
+	}
+
+	if p.Backward() {
+		for i, j := 0, keep-1; i < j; i, j = i+1, j-1 {
+			notifications[i], notifications[j] = notifications[j], notifications[i]
+		}
+	}
+
+	return notifications, p.Info(notifications[0].Cursor(), notifications[keep-1].Cursor(), more), nil
+}
+
+// MarkRead marks the notification by id of the user as read.
+func (nr *NotificationRepository) MarkRead(ctx context.Context, userID, id uint) error {
+	result, err := nr.Data.DB.ExecContext(ctx, updateRead, userID, id, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return conn.Affected(result, errNotificationNotFound)
+}
+
+// MarkAllRead marks every notification of the user as read.
+func (nr *NotificationRepository) MarkAllRead(ctx context.Context, userID uint) error {
+	_, err := nr.Data.DB.ExecContext(ctx, updateAllRead, userID, time.Now())
+
+	return err
+}
+
+// CountUnread returns the number of unread notifications of the user.
+func (nr *NotificationRepository) CountUnread(ctx context.Context, userID uint) (int, error) {
+	var count int
+	err := nr.Data.DB.QueryRowContext(ctx, countUnread, userID).Scan(&count)
+
+	return count, err
+}
@@ -0,0 +1,155 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"microblog/domain/notification/domain"
+	"microblog/domain/shared/errs"
+	"microblog/domain/shared/page"
+	data "microblog/infrastructure/database"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+// represent the repository
+var (
+	dbMockNotification         *sql.DB
+	notificationRepositoryMock *NotificationRepository
+)
+
+// NewMockNotification initialize mock connection to database
+func NewMockNotification() sqlmock.Sqlmock {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	dbMockNotification = db
+	notificationRepositoryMock = &NotificationRepository{
+		Data: &data.Data{DB: dbMockNotification},
+	}
+
+	return mock
+}
+
+// CloseMockNotification attaches the provider and close the connection
+func CloseMockNotification() {
+	err := dbMockNotification.Close()
+	if err != nil {
+		log.Println("Error close database test")
+	}
+}
+
+func TestNotificationRepository_Create(t *testing.T) {
+
+	t.Run("Error Unknown User", func(tt *testing.T) {
+		mock := NewMockNotification()
+		defer CloseMockNotification()
+
+		mock.ExpectQuery(insertNotificationTest).WithArgs(9, 1, "follow", nil, sqlmock.AnyArg()).
+			WillReturnError(&pq.Error{Code: "23503", Constraint: "fk_notifications_users", Message: "insert or update violates foreign key"})
+
+		err := notificationRepositoryMock.Create(context.Background(), &domain.Notification{UserID: 9, ActorID: 1, Kind: domain.KindFollow})
+		assert.True(tt, errors.Is(err, errs.ErrNotFound), "%v", err)
+		assert.Equal(tt, "user not found", err.Error())
+	})
+
+	t.Run("Create Duplicate", func(tt *testing.T) {
+		mock := NewMockNotification()
+		defer CloseMockNotification()
+
+		mock.ExpectQuery(insertNotificationTest).WithArgs(2, 1, "follow", nil, sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		n := domain.Notification{UserID: 2, ActorID: 1, Kind: domain.KindFollow}
+		assert.NoError(tt, notificationRepositoryMock.Create(context.Background(), &n))
+		assert.Zero(tt, n.ID)
+	})
+
+	t.Run("Create Successful", func(tt *testing.T) {
+		mock := NewMockNotification()
+		defer CloseMockNotification()
+
+		mock.ExpectQuery(insertNotificationTest).WithArgs(2, 1, "like", 7, sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+
+		postID := uint(7)
+		n := domain.Notification{UserID: 2, ActorID: 1, Kind: domain.KindLike, PostID: &postID}
+		assert.NoError(tt, notificationRepositoryMock.Create(context.Background(), &n))
+		assert.Equal(tt, uint(4), n.ID)
+		assert.False(tt, n.CreatedAt.IsZero())
+	})
+}
+
+func TestNotificationRepository_GetAll(t *testing.T) {
+	mock := NewMockNotification()
+	defer CloseMockNotification()
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "user_id", "actor_id", "kind", "post_id", "read", "created_at"}).
+		AddRow(3, 1, 2, "like", 7, false, now).
+		AddRow(2, 1, 3, "follow", nil, true, now).
+		AddRow(1, 1, 4, "follow", nil, false, now)
+
+	mock.ExpectQuery(selectNotificationsTest).WithArgs(1, true, nil, 0, 3).WillReturnRows(rows)
+
+	notifications, info, err := notificationRepositoryMock.GetAll(context.Background(), domain.Filter{UserID: 1, UnreadOnly: true}, page.Request{Limit: 2, Order: page.Desc})
+	assert.NoError(t, err)
+	if assert.Len(t, notifications, 2) {
+		assert.Equal(t, uint(7), *notifications[0].PostID)
+		assert.Nil(t, notifications[1].PostID)
+		assert.True(t, notifications[1].Read)
+	}
+
+	next, err := page.DecodeCursor(info.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), next.ID)
+}
+
+func TestNotificationRepository_MarkRead(t *testing.T) {
+
+	t.Run("Error Not Found", func(tt *testing.T) {
+		mock := NewMockNotification()
+		defer CloseMockNotification()
+
+		mock.ExpectExec(updateReadTest).WithArgs(1, 9, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := notificationRepositoryMock.MarkRead(context.Background(), 1, 9)
+		assert.True(tt, errors.Is(err, errs.ErrNotFound), "%v", err)
+	})
+
+	t.Run("Mark Read Successful", func(tt *testing.T) {
+		mock := NewMockNotification()
+		defer CloseMockNotification()
+
+		mock.ExpectExec(updateReadTest).WithArgs(1, 3, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(tt, notificationRepositoryMock.MarkRead(context.Background(), 1, 3))
+	})
+}
+
+func TestNotificationRepository_MarkAllRead(t *testing.T) {
+	mock := NewMockNotification()
+	defer CloseMockNotification()
+
+	mock.ExpectExec(updateAllReadTest).WithArgs(1, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 2))
+
+	assert.NoError(t, notificationRepositoryMock.MarkAllRead(context.Background(), 1))
+}
+
+func TestNotificationRepository_CountUnread(t *testing.T) {
+	mock := NewMockNotification()
+	defer CloseMockNotification()
+
+	mock.ExpectQuery(countUnreadTest).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+
+	count, err := notificationRepositoryMock.CountUnread(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, count)
+}
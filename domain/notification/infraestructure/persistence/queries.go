@@ -0,0 +1,27 @@
+package persistence
+
+const (
+
+	// insertNotification is a query that inserts a new row in the notifications table using the
+	// values given in order for user_id, actor_id, kind, post_id and created_at. A notification the
+	// user already has is not inserted again and no id is returned.
+	insertNotification = "INSERT INTO notifications (user_id, actor_id, kind, post_id, created_at) VALUES ($1, $2, $3, $4, $5) " +
+		"ON CONFLICT (user_id, actor_id, kind, (COALESCE(post_id, 0))) DO NOTHING RETURNING id;"
+
+	// selectNotifications is a query that selects a page of the notifications of the given user,
+	// only the unread ones when $2 is true. It must be formatted with the comparison operator and
+	// the sort direction returned by page.Request.Seek.
+	selectNotifications = "SELECT id, user_id, actor_id, kind, post_id, read_at IS NOT NULL, created_at FROM notifications " +
+		"WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL) AND ($3::timestamp IS NULL OR (created_at, id) %s ($3::timestamp, $4)) " +
+		"ORDER BY created_at %s, id %s LIMIT $5;"
+
+	// updateRead is a query that marks the given notification of the given user as read, keeping
+	// the time it was first read.
+	updateRead = "UPDATE notifications SET read_at = COALESCE(read_at, $3) WHERE user_id = $1 AND id = $2;"
+
+	// updateAllRead is a query that marks every unread notification of the given user as read.
+	updateAllRead = "UPDATE notifications SET read_at = $2 WHERE user_id = $1 AND read_at IS NULL;"
+
+	// countUnread is a query that counts the unread notifications of the given user.
+	countUnread = "SELECT count(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL;"
+)
@@ -0,0 +1,27 @@
+package persistence
+
+const (
+
+	// insertNotificationTest is a query test that inserts a new row in the notifications table.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	insertNotificationTest = "INSERT INTO notifications \\(user_id, actor_id, kind, post_id, created_at\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5\\)"
+
+	// selectNotificationsTest is the beginning of the query that selects a page of notifications.
+	selectNotificationsTest = "SELECT id, user_id, actor_id, kind, post_id, read_at IS NOT NULL, created_at FROM notifications"
+
+	// updateReadTest is a query that marks a notification as read.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	updateReadTest = "UPDATE notifications SET read_at \\= COALESCE\\(read_at, \\$3\\) WHERE user_id \\= \\$1 AND id \\= \\$2;"
+
+	// updateAllReadTest is a query that marks every notification of a user as read.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	updateAllReadTest = "UPDATE notifications SET read_at \\= \\$2 WHERE user_id \\= \\$1 AND read_at IS NULL;"
+
+	// countUnreadTest is a query that counts the unread notifications of a user.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	countUnreadTest = "SELECT count\\(\\*\\) FROM notifications WHERE user_id \\= \\$1 AND read_at IS NULL;"
+)
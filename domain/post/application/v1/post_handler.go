@@ -3,17 +3,79 @@ package v1
 import (
 	"encoding/json"
 	"fmt"
+	v1like "microblog/domain/like/application/v1"
+	likeDomain "microblog/domain/like/domain"
 	"microblog/domain/post/domain"
+	"microblog/domain/shared/page"
+	"microblog/domain/shared/response"
+	"microblog/infrastructure/auth"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi"
-	response "microblog/domain/post/application"
 )
 
-// PostRouter is the router of the posts.
+// PostRouter is the router of the posts. The posts it responds carry their
+// likes as seen by the reader when Likes is set.
 type PostRouter struct {
 	Repository domain.Repository
+	Service    *domain.Service
+	Likes      likeDomain.Repository
+}
+
+// PostPage is a page of posts along with the cursors of its neighbours.
+type PostPage struct {
+	Items []domain.Post `json:"items"`
+	page.Info
+}
+
+// newPostPage returns the response body for a page of posts.
+func newPostPage(posts []domain.Post, info page.Info) PostPage {
+	if posts == nil {
+		posts = []domain.Post{}
+	}
+
+	return PostPage{Items: posts, Info: info}
+}
+
+// actor returns the post actor for the authenticated user of the request.
+func actor(r *http.Request) (domain.Actor, bool) {
+	principal, ok := auth.FromContext(r.Context())
+	if !ok {
+		return domain.Actor{}, false
+	}
+
+	return domain.Actor{UserID: principal.UserID, Admin: principal.IsAdmin()}, true
+}
+
+// withLikes sets the like count of the posts and of the originals they
+// embed, and whether the reader of the request liked them.
+func (pr *PostRouter) withLikes(r *http.Request, posts ...*domain.Post) error {
+	reader, _ := actor(r)
+
+	return v1like.WithStats(r.Context(), pr.Likes, reader.UserID, posts...)
+}
+
+// pointers returns pointers to the posts of the slice.
+func pointers(posts []domain.Post) []*domain.Post {
+	result := make([]*domain.Post, 0, len(posts))
+	for i := range posts {
+		result = append(result, &posts[i])
+	}
+
+	return result
+}
+
+// replyPointers returns pointers to the posts of the reply tree.
+func replyPointers(replies []domain.Reply) []*domain.Post {
+	var result []*domain.Post
+	for i := range replies {
+		result = append(result, &replies[i].Post)
+		result = append(result, replyPointers(replies[i].Replies)...)
+	}
+
+	return result
 }
 
 // CreateHandler Create a new post.
@@ -27,10 +89,22 @@ func (pr *PostRouter) CreateHandler(w http.ResponseWriter, r *http.Request) {
 
 	defer r.Body.Close()
 
+	author, ok := actor(r)
+	if !ok {
+		response.HTTPError(w, r, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
 	ctx := r.Context()
-	err = pr.Repository.Create(ctx, &postResult)
+	err = pr.Service.Create(ctx, author, &postResult)
 	if err != nil {
-		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		response.Error(w, r, err)
+		return
+	}
+
+	err = pr.withLikes(r, &postResult)
+	if err != nil {
+		response.Error(w, r, err)
 		return
 	}
 
@@ -38,17 +112,61 @@ func (pr *PostRouter) CreateHandler(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, r, http.StatusCreated, postResult)
 }
 
-// GetAllPost response all the posts.
+// RepostHandler shares the post by id on behalf of the authenticated user.
+func (pr *PostRouter) RepostHandler(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sharer, ok := actor(r)
+	if !ok {
+		response.HTTPError(w, r, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	ctx := r.Context()
+	repost, err := pr.Service.Repost(ctx, sharer, uint(id))
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	err = pr.withLikes(r, &repost)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	w.Header().Add("Location", fmt.Sprintf("%s%d", strings.TrimSuffix(r.URL.Path, idStr+"/repost"), repost.ID))
+	response.JSON(w, r, http.StatusCreated, repost)
+}
+
+// GetAllPost response a page of all the posts.
 func (pr *PostRouter) GetAllPost(w http.ResponseWriter, r *http.Request) {
+	p, err := page.FromQuery(r.URL.Query())
+	if err != nil {
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	ctx := r.Context()
+	posts, info, err := pr.Repository.GetAll(ctx, p)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
 
-	posts, err := pr.Repository.GetAll(ctx)
+	err = pr.withLikes(r, pointers(posts)...)
 	if err != nil {
-		response.HTTPError(w, r, http.StatusNotFound, err.Error())
+		response.Error(w, r, err)
 		return
 	}
 
-	response.JSON(w, r, http.StatusOK, posts)
+	response.JSON(w, r, http.StatusOK, newPostPage(posts, info))
 }
 
 // GetOneHandler response one post by id.
@@ -64,13 +182,47 @@ func (pr *PostRouter) GetOneHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	postResult, err := pr.Repository.GetOne(ctx, uint(id))
 	if err != nil {
-		response.HTTPError(w, r, http.StatusNotFound, err.Error())
+		response.Error(w, r, err)
+		return
+	}
+
+	err = pr.withLikes(r, &postResult)
+	if err != nil {
+		response.Error(w, r, err)
 		return
 	}
 
 	response.JSON(w, r, http.StatusOK, postResult)
 }
 
+// ThreadHandler response the conversation around one post by id: the posts
+// it replies to and the tree of its replies.
+func (pr *PostRouter) ThreadHandler(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	thread, err := pr.Repository.GetThread(ctx, uint(id))
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	posts := append(pointers(thread.Ancestors), &thread.Post)
+	err = pr.withLikes(r, append(posts, replyPointers(thread.Replies)...)...)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, r, http.StatusOK, thread)
+}
+
 // UpdateHandler update a stored post by id.
 func (pr *PostRouter) UpdateHandler(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
@@ -90,10 +242,16 @@ func (pr *PostRouter) UpdateHandler(w http.ResponseWriter, r *http.Request) {
 
 	defer r.Body.Close()
 
+	editor, ok := actor(r)
+	if !ok {
+		response.HTTPError(w, r, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
 	ctx := r.Context()
-	err = pr.Repository.Update(ctx, uint(id), p)
+	err = pr.Service.Update(ctx, editor, uint(id), p)
 	if err != nil {
-		response.HTTPError(w, r, http.StatusNotFound, err.Error())
+		response.Error(w, r, err)
 		return
 	}
 
@@ -110,17 +268,23 @@ func (pr *PostRouter) DeleteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	remover, ok := actor(r)
+	if !ok {
+		response.HTTPError(w, r, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
 	ctx := r.Context()
-	err = pr.Repository.Delete(ctx, uint(id))
+	err = pr.Service.Delete(ctx, remover, uint(id))
 	if err != nil {
-		response.HTTPError(w, r, http.StatusNotFound, err.Error())
+		response.Error(w, r, err)
 		return
 	}
 
 	response.JSON(w, r, http.StatusOK, response.Map{})
 }
 
-// GetByUserHandler response posts by user id.
+// GetByUserHandler response a page of posts by user id.
 func (pr *PostRouter) GetByUserHandler(w http.ResponseWriter, r *http.Request) {
 	userIDStr := chi.URLParam(r, "userId")
 
@@ -130,17 +294,86 @@ func (pr *PostRouter) GetByUserHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	p, err := page.FromQuery(r.URL.Query())
+	if err != nil {
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	ctx := r.Context()
-	posts, err := pr.Repository.GetByUser(ctx, uint(userID))
+	posts, info, err := pr.Repository.GetByUser(ctx, uint(userID), p)
 	if err != nil {
-		response.HTTPError(w, r, http.StatusNotFound, err.Error())
+		response.Error(w, r, err)
 		return
 	}
 
-	if posts == nil {
-		response.HTTPError(w, r, http.StatusNotFound, "Post not found")
+	err = pr.withLikes(r, pointers(posts)...)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, r, http.StatusOK, newPostPage(posts, info))
+}
+
+// GetByTagHandler response a page of the posts tagged with the hashtag,
+// newest first. The tag is matched case insensitively, with or without #.
+func (pr *PostRouter) GetByTagHandler(w http.ResponseWriter, r *http.Request) {
+	tag := domain.NormalizeTag(chi.URLParam(r, "tag"))
+	if tag == "" {
+		response.HTTPError(w, r, http.StatusBadRequest, "tag is required")
+		return
+	}
+
+	p, err := page.FromQuery(r.URL.Query())
+	if err != nil {
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	posts, info, err := pr.Repository.GetByTag(ctx, tag, p)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	err = pr.withLikes(r, pointers(posts)...)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, r, http.StatusOK, newPostPage(posts, info))
+}
+
+// TimelineHandler response the home timeline of the authenticated user: its
+// own posts and the posts of the users it follows, newest first.
+func (pr *PostRouter) TimelineHandler(w http.ResponseWriter, r *http.Request) {
+	reader, ok := actor(r)
+	if !ok {
+		response.HTTPError(w, r, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	p, err := page.FromQuery(r.URL.Query())
+	if err != nil {
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	posts, info, err := pr.Repository.GetTimeline(ctx, reader.UserID, p)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	err = pr.withLikes(r, pointers(posts)...)
+	if err != nil {
+		response.Error(w, r, err)
 		return
 	}
 
-	response.JSON(w, r, http.StatusOK, posts)
+	response.JSON(w, r, http.StatusOK, newPostPage(posts, info))
 }
@@ -0,0 +1,390 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	likeDomain "microblog/domain/like/domain"
+	mockLike "microblog/domain/like/domain/mocks"
+	"microblog/domain/post/domain"
+	mockLocal "microblog/domain/post/domain/mocks"
+	"microblog/domain/shared/errs"
+	"microblog/domain/shared/page"
+	"microblog/infrastructure/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// dataPost is data for test
+func dataPost() domain.Post {
+	return domain.Post{
+		ID:     uint(1),
+		Body:   "Lorem ipsum dolor sit amet, consectetur adipisicing elit.",
+		UserID: uint(1),
+	}
+}
+
+// newPostRouter returns a PostRouter backed by the given mock repository.
+func newPostRouter(mockRepository *mockLocal.Repository) *PostRouter {
+	return &PostRouter{
+		Repository: mockRepository,
+		Service:    domain.NewService(mockRepository),
+	}
+}
+
+// withPrincipal returns the request authenticated as the given user.
+func withPrincipal(request *http.Request, userID uint, role string) *http.Request {
+	ctx := auth.NewContext(request.Context(), auth.Principal{UserID: userID, Role: role})
+	return request.WithContext(ctx)
+}
+
+// withID returns the request with the id URL param set.
+func withID(request *http.Request, id string) *http.Request {
+	requestCtx := chi.NewRouteContext()
+	requestCtx.URLParams.Add("id", id)
+
+	return request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+}
+
+func TestPostRouter_CreateHandler(t *testing.T) {
+
+	t.Run("Error Unauthenticated Create Handler", func(tt *testing.T) {
+		marshal, err := json.Marshal(dataPost())
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/posts/", bytes.NewReader(marshal))
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		newPostRouter(mockRepository).CreateHandler(response, request)
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Create Handler Uses Authenticated Author", func(tt *testing.T) {
+		post := dataPost()
+		post.UserID = uint(99)
+
+		marshal, err := json.Marshal(post)
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/posts/", bytes.NewReader(marshal))
+		request = withPrincipal(request, 1, "")
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		mockRepository.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+
+		newPostRouter(mockRepository).CreateHandler(response, request)
+		assert.Equal(tt, http.StatusCreated, response.Code)
+		mockRepository.AssertExpectations(tt)
+
+		var created domain.Post
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&created))
+		assert.Equal(tt, uint(1), created.UserID)
+	})
+
+	t.Run("Error Invalid Body Create Handler", func(tt *testing.T) {
+		post := dataPost()
+		post.Body = "   "
+
+		marshal, err := json.Marshal(post)
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/posts/", bytes.NewReader(marshal))
+		request = withPrincipal(request, 1, "")
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		newPostRouter(mockRepository).CreateHandler(response, request)
+		assert.Equal(tt, http.StatusUnprocessableEntity, response.Code)
+		mockRepository.AssertExpectations(tt)
+
+		var problem struct {
+			Errors []errs.FieldError `json:"errors"`
+		}
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&problem))
+		assert.Equal(tt, []errs.FieldError{{Field: "body", Message: "required body"}}, problem.Errors)
+	})
+}
+
+func TestPostRouter_UpdateHandler(t *testing.T) {
+
+	t.Run("Error Not Owner Update Handler", func(tt *testing.T) {
+		marshal, err := json.Marshal(dataPost())
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodPut, "/api/v1/posts/{id}", bytes.NewReader(marshal))
+		request = withPrincipal(withID(request, "1"), 2, "")
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		mockRepository.On("GetOne", mock.Anything, uint(1)).Return(dataPost(), nil).Once()
+
+		newPostRouter(mockRepository).UpdateHandler(response, request)
+		assert.Equal(tt, http.StatusForbidden, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Update Handler", func(tt *testing.T) {
+		marshal, err := json.Marshal(dataPost())
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodPut, "/api/v1/posts/{id}", bytes.NewReader(marshal))
+		request = withPrincipal(withID(request, "1"), 1, "")
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		mockRepository.On("GetOne", mock.Anything, uint(1)).Return(dataPost(), nil).Once()
+		mockRepository.On("Update", mock.Anything, uint(1), mock.Anything).Return(nil).Once()
+
+		newPostRouter(mockRepository).UpdateHandler(response, request)
+		assert.Equal(tt, http.StatusOK, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+}
+
+func TestPostRouter_DeleteHandler(t *testing.T) {
+
+	t.Run("Error Not Owner Delete Handler", func(tt *testing.T) {
+		request := httptest.NewRequest(http.MethodDelete, "/api/v1/posts/{id}", nil)
+		request = withPrincipal(withID(request, "1"), 2, "")
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		mockRepository.On("GetOne", mock.Anything, uint(1)).Return(dataPost(), nil).Once()
+
+		newPostRouter(mockRepository).DeleteHandler(response, request)
+		assert.Equal(tt, http.StatusForbidden, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Delete Handler By Admin", func(tt *testing.T) {
+		request := httptest.NewRequest(http.MethodDelete, "/api/v1/posts/{id}", nil)
+		request = withPrincipal(withID(request, "1"), 2, "admin")
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		mockRepository.On("GetOne", mock.Anything, uint(1)).Return(dataPost(), nil).Once()
+		mockRepository.On("Delete", mock.Anything, uint(1)).Return(nil).Once()
+
+		newPostRouter(mockRepository).DeleteHandler(response, request)
+		assert.Equal(tt, http.StatusOK, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+}
+
+func TestPostRouter_RepostHandler(t *testing.T) {
+
+	t.Run("Error Already Reposted Repost Handler", func(tt *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/posts/1/repost", nil)
+		request = withPrincipal(withID(request, "1"), 2, "")
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		mockRepository.On("GetOne", mock.Anything, uint(1)).Return(dataPost(), nil).Once()
+		mockRepository.On("Create", mock.Anything, mock.Anything).Return(domain.ErrReposted).Once()
+
+		newPostRouter(mockRepository).RepostHandler(response, request)
+		assert.Equal(tt, http.StatusConflict, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Repost Handler", func(tt *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/posts/1/repost", nil)
+		request = withPrincipal(withID(request, "1"), 2, "")
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		mockRepository.On("GetOne", mock.Anything, uint(1)).Return(dataPost(), nil).Once()
+		mockRepository.On("Create", mock.Anything, mock.MatchedBy(func(p *domain.Post) bool {
+			return p.UserID == uint(2) && p.RepostOf != nil && *p.RepostOf == uint(1)
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Post).ID = 5
+		}).Return(nil).Once()
+
+		newPostRouter(mockRepository).RepostHandler(response, request)
+		assert.Equal(tt, http.StatusCreated, response.Code)
+		assert.Equal(tt, "/api/v1/posts/5", response.Header().Get("Location"))
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Repost Handler Embeds The Original With Likes", func(tt *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/posts/1/repost", nil)
+		request = withPrincipal(withID(request, "1"), 2, "")
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+		mockLikes := &mockLike.Repository{}
+
+		mockRepository.On("GetOne", mock.Anything, uint(1)).Return(dataPost(), nil).Once()
+		mockRepository.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Post).ID = 5
+		}).Return(nil).Once()
+		mockLikes.On("Stats", mock.Anything, uint(2), []uint{5, 1}).Return(map[uint]likeDomain.Stats{1: {Count: 3, LikedByMe: true}}, nil).Once()
+
+		router := newPostRouter(mockRepository)
+		router.Likes = mockLikes
+		router.RepostHandler(response, request)
+		assert.Equal(tt, http.StatusCreated, response.Code)
+
+		var repost domain.Post
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&repost))
+		if assert.NotNil(tt, repost.Original) {
+			assert.Equal(tt, dataPost().Body, repost.Original.Body)
+			assert.Equal(tt, 3, repost.Original.LikeCount)
+			assert.True(tt, repost.Original.LikedByMe)
+		}
+		mockRepository.AssertExpectations(tt)
+		mockLikes.AssertExpectations(tt)
+	})
+}
+
+func TestPostRouter_TimelineHandler(t *testing.T) {
+
+	t.Run("Error Unauthenticated Timeline Handler", func(tt *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/timeline", nil)
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		newPostRouter(mockRepository).TimelineHandler(response, request)
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Error Cursor Timeline Handler", func(tt *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/timeline?cursor=invalid", nil)
+		request = withPrincipal(request, 1, "")
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		newPostRouter(mockRepository).TimelineHandler(response, request)
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Timeline Handler", func(tt *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/timeline?limit=1", nil)
+		request = withPrincipal(request, 1, "")
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		next := page.Info{NextCursor: "next"}
+		mockRepository.On("GetTimeline", mock.Anything, uint(1), page.Request{Limit: 1, Order: page.Desc}).
+			Return([]domain.Post{dataPost()}, next, nil).Once()
+
+		newPostRouter(mockRepository).TimelineHandler(response, request)
+		assert.Equal(tt, http.StatusOK, response.Code)
+		mockRepository.AssertExpectations(tt)
+
+		var body PostPage
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&body))
+		assert.Len(tt, body.Items, 1)
+		assert.Equal(tt, "next", body.NextCursor)
+	})
+}
+
+func TestPostRouter_GetByUserHandler(t *testing.T) {
+
+	t.Run("Get By User Handler Without Posts", func(tt *testing.T) {
+		requestCtx := chi.NewRouteContext()
+		requestCtx.URLParams.Add("userId", "1")
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/posts/user/{userId}?order=asc", nil)
+		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		mockRepository.On("GetByUser", mock.Anything, uint(1), page.Request{Limit: page.DefaultLimit, Order: page.Asc}).
+			Return(nil, page.Info{}, nil).Once()
+
+		newPostRouter(mockRepository).GetByUserHandler(response, request)
+		assert.Equal(tt, http.StatusOK, response.Code)
+		mockRepository.AssertExpectations(tt)
+
+		var body PostPage
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&body))
+		assert.NotNil(tt, body.Items)
+		assert.Empty(tt, body.Items)
+	})
+}
+
+func TestPostRouter_GetByTagHandler(t *testing.T) {
+	// withTag returns the request with the tag URL param set.
+	withTag := func(request *http.Request, tag string) *http.Request {
+		requestCtx := chi.NewRouteContext()
+		requestCtx.URLParams.Add("tag", tag)
+
+		return request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+	}
+
+	t.Run("Error Empty Tag Get By Tag Handler", func(tt *testing.T) {
+		request := withTag(httptest.NewRequest(http.MethodGet, "/api/v1/tags/%23/posts", nil), "#")
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		newPostRouter(mockRepository).GetByTagHandler(response, request)
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Get By Tag Handler Normalizes The Tag", func(tt *testing.T) {
+		request := withTag(httptest.NewRequest(http.MethodGet, "/api/v1/tags/GoLang/posts", nil), "GoLang")
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		mockRepository.On("GetByTag", mock.Anything, "golang", page.Request{Limit: page.DefaultLimit, Order: page.Desc}).
+			Return([]domain.Post{dataPost()}, page.Info{}, nil).Once()
+
+		newPostRouter(mockRepository).GetByTagHandler(response, request)
+		assert.Equal(tt, http.StatusOK, response.Code)
+		mockRepository.AssertExpectations(tt)
+
+		var body PostPage
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&body))
+		assert.Len(tt, body.Items, 1)
+	})
+}
+
+func TestPostRouter_ThreadHandler(t *testing.T) {
+
+	t.Run("Error Not Found Thread Handler", func(tt *testing.T) {
+		request := withID(httptest.NewRequest(http.MethodGet, "/api/v1/posts/{id}/thread", nil), "9")
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		mockRepository.On("GetThread", mock.Anything, uint(9)).Return(domain.Thread{}, errs.NotFound("post not found")).Once()
+
+		newPostRouter(mockRepository).ThreadHandler(response, request)
+		assert.Equal(tt, http.StatusNotFound, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Thread Handler", func(tt *testing.T) {
+		request := withID(httptest.NewRequest(http.MethodGet, "/api/v1/posts/{id}/thread", nil), "1")
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		parentID := uint(1)
+		reply := dataPost()
+		reply.ID = 2
+		reply.InReplyTo = &parentID
+		thread := domain.NewThread(nil, dataPost(), []domain.Post{reply})
+		mockRepository.On("GetThread", mock.Anything, uint(1)).Return(thread, nil).Once()
+
+		newPostRouter(mockRepository).ThreadHandler(response, request)
+		assert.Equal(tt, http.StatusOK, response.Code)
+		mockRepository.AssertExpectations(tt)
+
+		var body domain.Thread
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&body))
+		assert.NotNil(tt, body.Ancestors)
+		if assert.Len(tt, body.Replies, 1) {
+			assert.Equal(tt, uint(2), body.Replies[0].ID)
+		}
+	})
+}
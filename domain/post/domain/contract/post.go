@@ -0,0 +1,433 @@
+// Package contract holds the conformance suite every adapter of the post
+// port must pass, whatever its storage.
+package contract
+
+import (
+	"context"
+	"errors"
+	"microblog/domain/post/domain"
+	"microblog/domain/shared/errs"
+	"microblog/domain/shared/page"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Harness is a post repository under test along with a way to create the
+// authors of the posts and the follow graph the timeline reads.
+type Harness struct {
+	Repository domain.Repository
+	NewUser    func(t *testing.T) uint
+	Follow     func(t *testing.T, followerID, followingID uint)
+}
+
+// create stores a post of the user or stops the test.
+func create(t *testing.T, repository domain.Repository, userID uint) domain.Post {
+	t.Helper()
+
+	post := domain.Post{Body: "Lorem ipsum dolor sit amet, consectetur adipisicing elit.", UserID: userID}
+	if err := repository.Create(context.Background(), &post); err != nil {
+		t.Fatalf("error creating post of %d: %v", userID, err)
+	}
+
+	return post
+}
+
+// ids returns the ids of the posts in order.
+func ids(posts []domain.Post) []uint {
+	result := make([]uint, 0, len(posts))
+	for _, p := range posts {
+		result = append(result, p.ID)
+	}
+
+	return result
+}
+
+// TestRepository runs the domain.Repository suite. newHarness must return
+// an empty repository each time it is called.
+func TestRepository(t *testing.T, newHarness func(t *testing.T) Harness) {
+	ctx := context.Background()
+
+	t.Run("Create And Get One", func(tt *testing.T) {
+		h := newHarness(tt)
+		userID := h.NewUser(tt)
+
+		first := create(tt, h.Repository, userID)
+		second := create(tt, h.Repository, userID)
+		assert.NotZero(tt, first.ID)
+		assert.Greater(tt, second.ID, first.ID)
+
+		stored, err := h.Repository.GetOne(ctx, first.ID)
+		assert.NoError(tt, err)
+		assert.Equal(tt, first.Body, stored.Body)
+		assert.Equal(tt, userID, stored.UserID)
+		assert.False(tt, stored.CreatedAt.IsZero())
+		assert.False(tt, stored.UpdatedAt.IsZero())
+	})
+
+	t.Run("Create Returns Stored Fields", func(tt *testing.T) {
+		h := newHarness(tt)
+		userID := h.NewUser(tt)
+
+		post := domain.Post{Body: "Lorem ipsum dolor sit amet.", UserID: userID, ReplyCount: 3, LikeCount: 5, LikedByMe: true}
+		assert.NoError(tt, h.Repository.Create(ctx, &post))
+		assert.Zero(tt, post.ReplyCount)
+		assert.Zero(tt, post.LikeCount)
+		assert.False(tt, post.LikedByMe)
+
+		stored, err := h.Repository.GetOne(ctx, post.ID)
+		assert.NoError(tt, err)
+		assert.False(tt, post.CreatedAt.IsZero())
+		assert.True(tt, stored.CreatedAt.Equal(post.CreatedAt), "%s != %s", stored.CreatedAt, post.CreatedAt)
+		assert.True(tt, stored.UpdatedAt.Equal(post.UpdatedAt), "%s != %s", stored.UpdatedAt, post.UpdatedAt)
+	})
+
+	t.Run("Error Not Found", func(tt *testing.T) {
+		h := newHarness(tt)
+
+		_, err := h.Repository.GetOne(ctx, 9999)
+		assert.True(tt, errors.Is(err, errs.ErrNotFound), "%v", err)
+
+		err = h.Repository.Update(ctx, 9999, domain.Post{Body: "Edited"})
+		assert.True(tt, errors.Is(err, errs.ErrNotFound), "%v", err)
+
+		err = h.Repository.Delete(ctx, 9999)
+		assert.True(tt, errors.Is(err, errs.ErrNotFound), "%v", err)
+	})
+
+	t.Run("Update", func(tt *testing.T) {
+		h := newHarness(tt)
+		post := create(tt, h.Repository, h.NewUser(tt))
+
+		assert.NoError(tt, h.Repository.Update(ctx, post.ID, domain.Post{Body: "Edited"}))
+
+		stored, err := h.Repository.GetOne(ctx, post.ID)
+		assert.NoError(tt, err)
+		assert.Equal(tt, "Edited", stored.Body)
+		assert.Equal(tt, post.UserID, stored.UserID)
+	})
+
+	t.Run("Delete", func(tt *testing.T) {
+		h := newHarness(tt)
+		post := create(tt, h.Repository, h.NewUser(tt))
+
+		assert.NoError(tt, h.Repository.Delete(ctx, post.ID))
+
+		_, err := h.Repository.GetOne(ctx, post.ID)
+		assert.True(tt, errors.Is(err, errs.ErrNotFound), "%v", err)
+	})
+
+	t.Run("Reply", func(tt *testing.T) {
+		h := newHarness(tt)
+		userID := h.NewUser(tt)
+		parent := create(tt, h.Repository, userID)
+
+		reply := domain.Post{Body: "Ut enim ad minim veniam.", UserID: userID, InReplyTo: &parent.ID}
+		assert.NoError(tt, h.Repository.Create(ctx, &reply))
+
+		stored, err := h.Repository.GetOne(ctx, reply.ID)
+		assert.NoError(tt, err)
+		if assert.NotNil(tt, stored.InReplyTo) {
+			assert.Equal(tt, parent.ID, *stored.InReplyTo)
+		}
+
+		stored, err = h.Repository.GetOne(ctx, parent.ID)
+		assert.NoError(tt, err)
+		assert.Nil(tt, stored.InReplyTo)
+		assert.Equal(tt, 1, stored.ReplyCount)
+
+		unknown := uint(9999)
+		err = h.Repository.Create(ctx, &domain.Post{Body: "Orphan", UserID: userID, InReplyTo: &unknown})
+		assert.True(tt, errors.Is(err, errs.ErrNotFound), "%v", err)
+	})
+
+	t.Run("Get Thread", func(tt *testing.T) {
+		h := newHarness(tt)
+		userID := h.NewUser(tt)
+
+		reply := func(parentID uint) domain.Post {
+			post := domain.Post{Body: "Ut enim ad minim veniam.", UserID: userID, InReplyTo: &parentID}
+			if err := h.Repository.Create(ctx, &post); err != nil {
+				tt.Fatalf("error replying to %d: %v", parentID, err)
+			}
+
+			return post
+		}
+
+		root := create(tt, h.Repository, userID)
+		middle := reply(root.ID)
+		first := reply(middle.ID)
+		nested := reply(first.ID)
+		second := reply(middle.ID)
+		reply(root.ID)
+
+		thread, err := h.Repository.GetThread(ctx, middle.ID)
+		assert.NoError(tt, err)
+		assert.Equal(tt, []uint{root.ID}, ids(thread.Ancestors))
+		assert.Equal(tt, middle.ID, thread.Post.ID)
+		assert.Equal(tt, 2, thread.Post.ReplyCount)
+		if assert.Len(tt, thread.Replies, 2) {
+			assert.Equal(tt, first.ID, thread.Replies[0].ID)
+			assert.Equal(tt, second.ID, thread.Replies[1].ID)
+			if assert.Len(tt, thread.Replies[0].Replies, 1) {
+				assert.Equal(tt, nested.ID, thread.Replies[0].Replies[0].ID)
+			}
+		}
+
+		thread, err = h.Repository.GetThread(ctx, nested.ID)
+		assert.NoError(tt, err)
+		assert.Equal(tt, []uint{root.ID, middle.ID, first.ID}, ids(thread.Ancestors))
+		assert.Empty(tt, thread.Replies)
+
+		_, err = h.Repository.GetThread(ctx, 9999)
+		assert.True(tt, errors.Is(err, errs.ErrNotFound), "%v", err)
+	})
+
+	t.Run("Delete Detaches Replies", func(tt *testing.T) {
+		h := newHarness(tt)
+		userID := h.NewUser(tt)
+		parent := create(tt, h.Repository, userID)
+
+		reply := domain.Post{Body: "Ut enim ad minim veniam.", UserID: userID, InReplyTo: &parent.ID}
+		assert.NoError(tt, h.Repository.Create(ctx, &reply))
+		assert.NoError(tt, h.Repository.Delete(ctx, parent.ID))
+
+		stored, err := h.Repository.GetOne(ctx, reply.ID)
+		assert.NoError(tt, err)
+		assert.Nil(tt, stored.InReplyTo)
+
+		thread, err := h.Repository.GetThread(ctx, reply.ID)
+		assert.NoError(tt, err)
+		assert.Empty(tt, thread.Ancestors)
+	})
+
+	t.Run("Repost And Quote Embed The Original", func(tt *testing.T) {
+		h := newHarness(tt)
+		author, sharer := h.NewUser(tt), h.NewUser(tt)
+		original := create(tt, h.Repository, author)
+
+		repost := domain.Post{UserID: sharer, RepostOf: &original.ID}
+		assert.NoError(tt, h.Repository.Create(ctx, &repost))
+
+		quote := domain.Post{Body: "Ut enim ad minim veniam.", UserID: sharer, QuoteOf: &original.ID}
+		assert.NoError(tt, h.Repository.Create(ctx, &quote))
+
+		posts, _, err := h.Repository.GetByUser(ctx, sharer, page.Request{Limit: 10, Order: page.Desc})
+		assert.NoError(tt, err)
+		if assert.Equal(tt, []uint{quote.ID, repost.ID}, ids(posts)) {
+			for _, p := range posts {
+				if assert.NotNil(tt, p.Original) {
+					assert.Equal(tt, original.ID, p.Original.ID)
+					assert.Equal(tt, original.Body, p.Original.Body)
+				}
+			}
+			assert.Empty(tt, posts[1].Body)
+		}
+
+		stored, err := h.Repository.GetOne(ctx, quote.ID)
+		assert.NoError(tt, err)
+		if assert.NotNil(tt, stored.QuoteOf) && assert.NotNil(tt, stored.Original) {
+			assert.Equal(tt, original.ID, *stored.QuoteOf)
+			assert.Equal(tt, original.ID, stored.Original.ID)
+		}
+
+		stored, err = h.Repository.GetOne(ctx, original.ID)
+		assert.NoError(tt, err)
+		assert.Nil(tt, stored.Original)
+	})
+
+	t.Run("Error Repost Twice", func(tt *testing.T) {
+		h := newHarness(tt)
+		userID := h.NewUser(tt)
+		original := create(tt, h.Repository, userID)
+
+		assert.NoError(tt, h.Repository.Create(ctx, &domain.Post{UserID: userID, RepostOf: &original.ID}))
+
+		err := h.Repository.Create(ctx, &domain.Post{UserID: userID, RepostOf: &original.ID})
+		assert.True(tt, errors.Is(err, errs.ErrConflict), "%v", err)
+
+		unknown := uint(9999)
+		err = h.Repository.Create(ctx, &domain.Post{UserID: userID, RepostOf: &unknown})
+		assert.True(tt, errors.Is(err, errs.ErrNotFound), "%v", err)
+
+		err = h.Repository.Create(ctx, &domain.Post{Body: "Orphan", UserID: userID, QuoteOf: &unknown})
+		assert.True(tt, errors.Is(err, errs.ErrNotFound), "%v", err)
+	})
+
+	t.Run("Delete Removes Reposts And Detaches Quotes", func(tt *testing.T) {
+		h := newHarness(tt)
+		userID := h.NewUser(tt)
+		original := create(tt, h.Repository, userID)
+
+		repost := domain.Post{UserID: userID, RepostOf: &original.ID}
+		assert.NoError(tt, h.Repository.Create(ctx, &repost))
+
+		quote := domain.Post{Body: "Ut enim ad minim veniam.", UserID: userID, QuoteOf: &original.ID}
+		assert.NoError(tt, h.Repository.Create(ctx, &quote))
+
+		assert.NoError(tt, h.Repository.Delete(ctx, original.ID))
+
+		_, err := h.Repository.GetOne(ctx, repost.ID)
+		assert.True(tt, errors.Is(err, errs.ErrNotFound), "%v", err)
+
+		stored, err := h.Repository.GetOne(ctx, quote.ID)
+		assert.NoError(tt, err)
+		assert.Nil(tt, stored.QuoteOf)
+		assert.Nil(tt, stored.Original)
+		assert.Equal(tt, quote.Body, stored.Body)
+	})
+
+	t.Run("Get All Ordered And Paginated", func(tt *testing.T) {
+		h := newHarness(tt)
+		userID := h.NewUser(tt)
+
+		var created []uint
+		for i := 0; i < 3; i++ {
+			created = append(created, create(tt, h.Repository, userID).ID)
+		}
+
+		first, info, err := h.Repository.GetAll(ctx, page.Request{Limit: 2, Order: page.Desc})
+		assert.NoError(tt, err)
+		assert.Equal(tt, []uint{created[2], created[1]}, ids(first))
+		assert.Empty(tt, info.PrevCursor)
+
+		next, err := page.DecodeCursor(info.NextCursor)
+		if !assert.NoError(tt, err) {
+			return
+		}
+
+		second, info, err := h.Repository.GetAll(ctx, page.Request{Limit: 2, Order: page.Desc, Cursor: &next})
+		assert.NoError(tt, err)
+		assert.Equal(tt, []uint{created[0]}, ids(second))
+		assert.Empty(tt, info.NextCursor)
+		assert.NotEmpty(tt, info.PrevCursor)
+
+		ascending, _, err := h.Repository.GetAll(ctx, page.Request{Limit: 10, Order: page.Asc})
+		assert.NoError(tt, err)
+		assert.Equal(tt, created, ids(ascending))
+	})
+
+	t.Run("Get By User", func(tt *testing.T) {
+		h := newHarness(tt)
+		author, other := h.NewUser(tt), h.NewUser(tt)
+
+		first := create(tt, h.Repository, author)
+		create(tt, h.Repository, other)
+		second := create(tt, h.Repository, author)
+
+		posts, info, err := h.Repository.GetByUser(ctx, author, page.Request{Limit: 10, Order: page.Desc})
+		assert.NoError(tt, err)
+		assert.Equal(tt, []uint{second.ID, first.ID}, ids(posts))
+		assert.Equal(tt, page.Info{}, info)
+
+		posts, _, err = h.Repository.GetByUser(ctx, 9999, page.Request{Limit: 10, Order: page.Desc})
+		assert.NoError(tt, err)
+		assert.Empty(tt, posts)
+	})
+
+	t.Run("Mentions Stored And Replaced", func(tt *testing.T) {
+		h := newHarness(tt)
+		author, mentioned := h.NewUser(tt), h.NewUser(tt)
+
+		post := domain.Post{Body: "Hi @someone and @nobody", UserID: author, Mentions: []domain.Mention{
+			{UserID: mentioned, Start: 3, End: 11},
+		}}
+		assert.NoError(tt, h.Repository.Create(ctx, &post))
+
+		stored, err := h.Repository.GetOne(ctx, post.ID)
+		assert.NoError(tt, err)
+		if assert.Len(tt, stored.Mentions, 1) {
+			assert.Equal(tt, mentioned, stored.Mentions[0].UserID)
+			assert.Equal(tt, 3, stored.Mentions[0].Start)
+			assert.Equal(tt, 11, stored.Mentions[0].End)
+		}
+
+		quote := domain.Post{Body: "Look", UserID: author, QuoteOf: &post.ID}
+		assert.NoError(tt, h.Repository.Create(ctx, &quote))
+
+		posts, _, err := h.Repository.GetByUser(ctx, author, page.Request{Limit: 10, Order: page.Desc})
+		assert.NoError(tt, err)
+		if assert.Len(tt, posts, 2) && assert.NotNil(tt, posts[0].Original) {
+			assert.Empty(tt, posts[0].Mentions)
+			assert.Len(tt, posts[0].Original.Mentions, 1)
+			assert.Len(tt, posts[1].Mentions, 1)
+		}
+
+		assert.NoError(tt, h.Repository.Update(ctx, post.ID, domain.Post{Body: "Bye @nobody"}))
+
+		stored, err = h.Repository.GetOne(ctx, post.ID)
+		assert.NoError(tt, err)
+		assert.Empty(tt, stored.Mentions)
+	})
+
+	t.Run("Get By Tag", func(tt *testing.T) {
+		h := newHarness(tt)
+		userID := h.NewUser(tt)
+
+		tagged := func(body string) domain.Post {
+			post := domain.Post{Body: body, UserID: userID}
+			if err := h.Repository.Create(ctx, &post); err != nil {
+				tt.Fatalf("error creating post %q: %v", body, err)
+			}
+
+			return post
+		}
+
+		first := tagged("Learning #Go today")
+		second := tagged("#go #go #GO and #gophers")
+		other := tagged("Only #gophers here")
+		create(tt, h.Repository, userID)
+
+		posts, _, err := h.Repository.GetByTag(ctx, "go", page.Request{Limit: 1, Order: page.Desc})
+		assert.NoError(tt, err)
+		assert.Equal(tt, []uint{second.ID}, ids(posts))
+
+		posts, _, err = h.Repository.GetByTag(ctx, "go", page.Request{Limit: 10, Order: page.Desc})
+		assert.NoError(tt, err)
+		assert.Equal(tt, []uint{second.ID, first.ID}, ids(posts))
+
+		assert.NoError(tt, h.Repository.Update(ctx, first.ID, domain.Post{Body: "Learning #gophers today"}))
+		assert.NoError(tt, h.Repository.Update(ctx, other.ID, domain.Post{Body: "Now about #go"}))
+
+		posts, _, err = h.Repository.GetByTag(ctx, "go", page.Request{Limit: 10, Order: page.Desc})
+		assert.NoError(tt, err)
+		assert.Equal(tt, []uint{other.ID, second.ID}, ids(posts))
+
+		posts, _, err = h.Repository.GetByTag(ctx, "gophers", page.Request{Limit: 10, Order: page.Desc})
+		assert.NoError(tt, err)
+		assert.Equal(tt, []uint{second.ID, first.ID}, ids(posts))
+
+		assert.NoError(tt, h.Repository.Delete(ctx, second.ID))
+
+		posts, _, err = h.Repository.GetByTag(ctx, "go", page.Request{Limit: 10, Order: page.Desc})
+		assert.NoError(tt, err)
+		assert.Equal(tt, []uint{other.ID}, ids(posts))
+	})
+
+	t.Run("Get Timeline", func(tt *testing.T) {
+		h := newHarness(tt)
+		me, followed, stranger := h.NewUser(tt), h.NewUser(tt), h.NewUser(tt)
+		h.Follow(tt, me, followed)
+
+		own := create(tt, h.Repository, me)
+		create(tt, h.Repository, stranger)
+		theirs := create(tt, h.Repository, followed)
+
+		posts, _, err := h.Repository.GetTimeline(ctx, me, page.Request{Limit: 10, Order: page.Desc})
+		assert.NoError(tt, err)
+		assert.Equal(tt, []uint{theirs.ID, own.ID}, ids(posts))
+
+		posts, _, err = h.Repository.GetTimeline(ctx, followed, page.Request{Limit: 10, Order: page.Desc})
+		assert.NoError(tt, err)
+		assert.Equal(tt, []uint{theirs.ID}, ids(posts))
+
+		repost := domain.Post{UserID: followed, RepostOf: &own.ID}
+		assert.NoError(tt, h.Repository.Create(ctx, &repost))
+
+		posts, _, err = h.Repository.GetTimeline(ctx, me, page.Request{Limit: 1, Order: page.Desc})
+		assert.NoError(tt, err)
+		if assert.Equal(tt, []uint{repost.ID}, ids(posts)) && assert.NotNil(tt, posts[0].Original) {
+			assert.Equal(tt, own.ID, posts[0].Original.ID)
+		}
+	})
+}
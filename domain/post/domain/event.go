@@ -0,0 +1,25 @@
+package domain
+
+// PostCreated is published once a post, a reply, a repost or a quote is
+// stored. The post carries its id and its resolved mentions.
+type PostCreated struct {
+	Post Post
+}
+
+// Name identifies the event.
+func (PostCreated) Name() string {
+	return "post.created"
+}
+
+// PostUpdated is published once the body of a post is edited. The post
+// carries its author and its resolved mentions; Mentioned holds the ones
+// the edit added, the users not mentioned before.
+type PostUpdated struct {
+	Post      Post
+	Mentioned []Mention
+}
+
+// Name identifies the event.
+func (PostUpdated) Name() string {
+	return "post.updated"
+}
@@ -1,12 +1,122 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"microblog/domain/shared/errs"
+	"microblog/domain/shared/page"
+	"strings"
+	"time"
+)
 
-// Post created by a user.
+// Limits bound the body of a post. A zero limit is not enforced.
+type Limits struct {
+	MaxLength   int
+	MaxLinks    int
+	MaxMentions int
+}
+
+// DefaultLimits are the limits a post body respects unless others are
+// configured at startup.
+var DefaultLimits = Limits{MaxLength: 280, MaxLinks: 5, MaxMentions: 10}
+
+// Mention links the @username written in a post body to the user it names.
+// Start and End are the offsets of the mention, @ included, in Unicode code
+// points of the body, End being exclusive.
+type Mention struct {
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
+}
+
+// Post created by a user. A reply points to the post it answers with
+// InReplyTo; ReplyCount is the number of direct replies the post has.
+// A repost shares the post RepostOf and has no body, a quote comments the
+// post QuoteOf with its own body; both carry the post they refer to in
+// Original when it is read. Mentions are the mentions of the body that name
+// an existing user. LikeCount and LikedByMe are filled for the reader from
+// the likes.
 type Post struct {
-	ID        uint      `json:"id,omitempty"`
-	Body      string    `json:"body,omitempty"`
-	UserID    uint      `json:"user_id,omitempty"`
-	CreatedAt time.Time `json:"created_at,omitempty"`
-	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	ID         uint      `json:"id,omitempty"`
+	Body       string    `json:"body,omitempty"`
+	UserID     uint      `json:"user_id,omitempty"`
+	InReplyTo  *uint     `json:"in_reply_to,omitempty"`
+	RepostOf   *uint     `json:"repost_of,omitempty"`
+	QuoteOf    *uint     `json:"quote_of,omitempty"`
+	Original   *Post     `json:"original,omitempty"`
+	Mentions   []Mention `json:"mentions,omitempty"`
+	ReplyCount int       `json:"reply_count"`
+	LikeCount  int       `json:"like_count"`
+	LikedByMe  bool      `json:"liked_by_me"`
+	CreatedAt  time.Time `json:"created_at,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at,omitempty"`
+}
+
+// Cursor returns the position of the post in a list ordered by creation.
+func (p Post) Cursor() page.Cursor {
+	return page.Cursor{CreatedAt: p.CreatedAt, ID: p.ID}
+}
+
+// ErrReposted is returned when a user reposts a post twice.
+var ErrReposted = errs.Conflict("post already reposted")
+
+// IsRepost reports whether the post shares another one without a body.
+func (p Post) IsRepost() bool {
+	return p.RepostOf != nil
+}
+
+// Refers returns the id of the post the repost or quote refers to, nil for
+// any other post.
+func (p Post) Refers() *uint {
+	if p.RepostOf != nil {
+		return p.RepostOf
+	}
+
+	return p.QuoteOf
+}
+
+// Tags returns the distinct hashtags of the body, normalized.
+func (p Post) Tags() []string {
+	return hashtags(p.Body)
+}
+
+// Validate checks the body of the post against the limits. The length is
+// measured in user-perceived characters, so an emoji counts as one. A
+// repost must have no body and can neither reply nor quote.
+func (p Post) Validate(limits Limits) error {
+	const field = "body"
+
+	body := strings.TrimSpace(p.Body)
+	if p.IsRepost() {
+		var fields []errs.FieldError
+		if body != "" {
+			fields = append(fields, errs.FieldError{Field: field, Message: "a repost has no body"})
+		}
+
+		if p.InReplyTo != nil || p.QuoteOf != nil {
+			fields = append(fields, errs.FieldError{Field: "repost_of", Message: "a repost can not reply nor quote"})
+		}
+
+		return errs.Invalid(fields...)
+	}
+
+	if body == "" {
+		return errs.Invalid(errs.FieldError{Field: field, Message: "required body"})
+	}
+
+	var fields []errs.FieldError
+
+	if limits.MaxLength > 0 && graphemes(body) > limits.MaxLength {
+		fields = append(fields, errs.FieldError{Field: field, Message: fmt.Sprintf("body is longer than %d characters", limits.MaxLength)})
+	}
+
+	if limits.MaxLinks > 0 && len(links(body)) > limits.MaxLinks {
+		fields = append(fields, errs.FieldError{Field: field, Message: fmt.Sprintf("body has more than %d links", limits.MaxLinks)})
+	}
+
+	if limits.MaxMentions > 0 && len(mentions(body)) > limits.MaxMentions {
+		fields = append(fields, errs.FieldError{Field: field, Message: fmt.Sprintf("body mentions more than %d users", limits.MaxMentions)})
+	}
+
+	return errs.Invalid(fields...)
 }
@@ -0,0 +1,99 @@
+package domain_test
+
+import (
+	"errors"
+	"microblog/domain/post/domain"
+	"microblog/domain/shared/errs"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPost_Validate(t *testing.T) {
+	limits := domain.Limits{MaxLength: 5, MaxLinks: 1, MaxMentions: 2}
+
+	tests := []struct {
+		Name   string
+		Body   string
+		Limits domain.Limits
+		Errors []string
+	}{
+		{Name: "Valid", Body: "hello", Limits: limits},
+		{Name: "Blank", Body: " \n\t ", Limits: limits, Errors: []string{"required body"}},
+		{Name: "Trimmed Before Measured", Body: "  hello  ", Limits: limits},
+		{Name: "Too Long", Body: "hello!", Limits: limits, Errors: []string{"body is longer than 5 characters"}},
+		{Name: "Combining Marks", Body: "ééééé", Limits: limits},
+		{Name: "Emoji Sequences", Body: "👩‍👩‍👧👍🏽🇨🇴❤️\r\n", Limits: limits},
+		{Name: "Flags In Pairs", Body: "🇨🇴🇨🇴🇨🇴🇨🇴🇨🇴🇨🇴", Limits: limits, Errors: []string{"body is longer than 5 characters"}},
+		{Name: "Flags", Body: "🇨🇴🇦🇷🇲🇽🇪🇸🏴󠁧󠁢󠁳󠁣󠁴󠁿", Limits: limits},
+		{Name: "Unpaired Regional Indicator", Body: "🇨🇴🇦🇷🇲🇽🇪🇸🇯🇵🇨", Limits: limits, Errors: []string{"body is longer than 5 characters"}},
+		{Name: "ZWJ Families", Body: "👨‍👩‍👧‍👦👨‍👩‍👧‍👦👨‍👩‍👧‍👦👨‍👩‍👧‍👦👨‍👩‍👧‍👦", Limits: limits},
+		{Name: "ZWJ Families Too Long", Body: "👨‍👩‍👧‍👦👨‍👩‍👧‍👦👨‍👩‍👧‍👦👩🏽‍❤️‍👨🏿👨‍👩‍👧‍👦👨‍👩‍👧‍👦", Limits: limits, Errors: []string{"body is longer than 5 characters"}},
+		{Name: "Too Many Links", Body: "http://a.co https://b.co", Limits: domain.Limits{MaxLinks: 1}, Errors: []string{"body has more than 1 links"}},
+		{Name: "Too Many Mentions", Body: "@ana @bob @Ana @carl", Limits: domain.Limits{MaxMentions: 2}, Errors: []string{"body mentions more than 2 users"}},
+		{Name: "Mentions Case Sensitive", Body: "@ana @Ana @ana", Limits: domain.Limits{MaxMentions: 1}, Errors: []string{"body mentions more than 1 users"}},
+		{Name: "Emails Are Not Mentions", Body: "ana@jikkosoft.com bob@jikkosoft.com carl@jikkosoft.com", Limits: domain.Limits{MaxMentions: 2}},
+		{Name: "Zero Limits Disabled", Body: strings.Repeat("@ana https://a.co ", 100), Limits: domain.Limits{}},
+		{Name: "Every Limit Reported", Body: "@a @b @c http://a.co http://b.co", Limits: limits, Errors: []string{
+			"body is longer than 5 characters", "body has more than 1 links", "body mentions more than 2 users",
+		}},
+	}
+
+	for _, test := range tests {
+		err := domain.Post{Body: test.Body}.Validate(test.Limits)
+		if len(test.Errors) == 0 {
+			assert.NoError(t, err, test.Name)
+			continue
+		}
+
+		assert.True(t, errors.Is(err, errs.ErrValidation), test.Name)
+
+		var messages []string
+		for _, f := range errs.Fields(err) {
+			assert.Equal(t, "body", f.Field, test.Name)
+			messages = append(messages, f.Message)
+		}
+		assert.Equal(t, test.Errors, messages, test.Name)
+	}
+}
+
+func TestPost_ValidateRepost(t *testing.T) {
+	originalID := uint(1)
+
+	assert.NoError(t, domain.Post{RepostOf: &originalID}.Validate(domain.DefaultLimits))
+	assert.NoError(t, domain.Post{Body: "hello", QuoteOf: &originalID}.Validate(domain.DefaultLimits))
+
+	err := domain.Post{Body: "hello", RepostOf: &originalID, QuoteOf: &originalID}.Validate(domain.DefaultLimits)
+	assert.True(t, errors.Is(err, errs.ErrValidation), "%v", err)
+	assert.Len(t, errs.Fields(err), 2)
+
+	err = domain.Post{QuoteOf: &originalID}.Validate(domain.DefaultLimits)
+	assert.True(t, errors.Is(err, errs.ErrValidation), "%v", err)
+}
+
+func TestPost_Tags(t *testing.T) {
+	tests := []struct {
+		Name string
+		Body string
+		Tags []string
+	}{
+		{Name: "None", Body: "hello"},
+		{Name: "Normalized And Distinct", Body: "#Go is #go, #GoLang!", Tags: []string{"go", "golang"}},
+		{Name: "Unicode Letters", Body: "#Café y #añoNuevo", Tags: []string{"café", "añonuevo"}},
+		{Name: "Letter Required", Body: "#1 #2020 #v2 #_", Tags: []string{"v2", "_"}},
+		{Name: "Not After A Word", Body: "C# a#b &#35; ##double", Tags: nil},
+		{Name: "Not In Links", Body: "https://a.co/#top", Tags: nil},
+		{Name: "After Punctuation", Body: "(#one) [#two]", Tags: []string{"one", "two"}},
+		{Name: "Too Long", Body: "#" + strings.Repeat("a", domain.MaxTagLength+1)},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.Tags, domain.Post{Body: test.Body}.Tags(), test.Name)
+	}
+}
+
+func TestNormalizeTag(t *testing.T) {
+	assert.Equal(t, "golang", domain.NormalizeTag("#GoLang"))
+	assert.Equal(t, "café", domain.NormalizeTag("CAFÉ"))
+}
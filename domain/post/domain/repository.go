@@ -1,13 +1,34 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"microblog/domain/shared/page"
+)
 
-// Repository handle the CRUD operations with Posts.
+// Repository handle the CRUD operations with Posts. The posts it reads carry
+// their mentions, ordered by offset, and the reposts and quotes the post
+// they refer to in Original.
 type Repository interface {
-	GetAll(ctx context.Context) ([]Post, error)
+	GetAll(ctx context.Context, p page.Request) ([]Post, page.Info, error)
 	GetOne(ctx context.Context, id uint) (Post, error)
-	GetByUser(ctx context.Context, userID uint) ([]Post, error)
+	GetByUser(ctx context.Context, userID uint, p page.Request) ([]Post, page.Info, error)
+	// GetByTag returns the posts tagged with the normalized hashtag, newest
+	// first.
+	GetByTag(ctx context.Context, tag string, p page.Request) ([]Post, page.Info, error)
+	// GetTimeline returns the posts of the user and of the users it follows,
+	// newest first.
+	GetTimeline(ctx context.Context, userID uint, p page.Request) ([]Post, page.Info, error)
+	// GetThread returns the post by id along with its ancestors and the
+	// tree of its replies.
+	GetThread(ctx context.Context, id uint) (Thread, error)
+	// Create adds a new post, a reply when InReplyTo is set, a repost when
+	// RepostOf is and a quote when QuoteOf is, along with its mentions, and
+	// indexes its hashtags. Reposting a post twice returns ErrReposted.
 	Create(ctx context.Context, post *Post) error
+	// Update updates the body and the mentions of a post by id and indexes
+	// its hashtags again.
 	Update(ctx context.Context, id uint, post Post) error
+	// Delete removes a post by id along with its reposts. Its replies and
+	// quotes are kept and no longer refer to any post.
 	Delete(ctx context.Context, id uint) error
 }
@@ -0,0 +1,222 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"microblog/domain/shared/errs"
+	"microblog/domain/shared/event"
+	userDomain "microblog/domain/user/domain"
+)
+
+// ErrForbidden is returned when an actor is not allowed to modify a post.
+var ErrForbidden = errs.Forbidden("you are not allowed to modify this post")
+
+// Actor is the authenticated user performing an operation over posts.
+type Actor struct {
+	UserID uint
+	Admin  bool
+}
+
+// CanModify reports whether the actor may update or delete the given post.
+func (a Actor) CanModify(p Post) bool {
+	if a.Admin {
+		return true
+	}
+
+	return a.UserID != 0 && a.UserID == p.UserID
+}
+
+// Service applies the post business rules over a Repository. The mentions
+// of the bodies are resolved through Users and the creations published to
+// Events when they are set.
+type Service struct {
+	Repository Repository
+	Users      userDomain.Repository
+	Events     event.Publisher
+	Limits     Limits
+}
+
+// NewService returns a Service that stores posts in the given repository
+// and bounds their bodies with the default limits.
+func NewService(repository Repository) *Service {
+	return &Service{Repository: repository, Limits: DefaultLimits}
+}
+
+// Create adds a new post authored by the actor, whatever author the post
+// carried before. Only the body and the posts it refers to are taken from
+// post, the rest is set by the server. Reposting or quoting a repost refers
+// to the post it shares instead, and the post referred to is embedded.
+func (s *Service) Create(ctx context.Context, actor Actor, post *Post) error {
+	if err := post.Validate(s.Limits); err != nil {
+		return err
+	}
+
+	*post = Post{
+		Body:      post.Body,
+		UserID:    actor.UserID,
+		InReplyTo: post.InReplyTo,
+		RepostOf:  post.RepostOf,
+		QuoteOf:   post.QuoteOf,
+	}
+
+	if err := s.resolve(ctx, post); err != nil {
+		return err
+	}
+
+	if err := s.mention(ctx, post); err != nil {
+		return err
+	}
+
+	if err := s.Repository.Create(ctx, post); err != nil {
+		return err
+	}
+
+	if s.Events != nil {
+		s.Events.Publish(ctx, PostCreated{Post: *post})
+	}
+
+	return nil
+}
+
+// Repost shares the post by id on behalf of the actor.
+func (s *Service) Repost(ctx context.Context, actor Actor, id uint) (Post, error) {
+	post := Post{RepostOf: &id}
+	if err := s.Create(ctx, actor, &post); err != nil {
+		return Post{}, err
+	}
+
+	return post, nil
+}
+
+// resolve points a repost or a quote of a repost to the post the repost
+// shares, so that chains of reposts always refer to an original post, and
+// embeds that post as the original.
+func (s *Service) resolve(ctx context.Context, post *Post) error {
+	ref := post.Refers()
+	if ref == nil {
+		return nil
+	}
+
+	referenced, err := s.Repository.GetOne(ctx, *ref)
+	if err != nil {
+		return err
+	}
+
+	if !referenced.IsRepost() {
+		referenced.Original = nil
+		post.Original = &referenced
+		return nil
+	}
+
+	original := *referenced.RepostOf
+	if post.IsRepost() {
+		post.RepostOf = &original
+	} else {
+		post.QuoteOf = &original
+	}
+
+	post.Original = referenced.Original
+
+	return nil
+}
+
+// mention sets the mentions of the body that name an existing user. The
+// ones naming no user are left as plain text.
+func (s *Service) mention(ctx context.Context, post *Post) error {
+	post.Mentions = nil
+	if s.Users == nil {
+		return nil
+	}
+
+	users := make(map[string]uint)
+	for _, m := range mentionSpans(post.Body) {
+		id, ok := users[m.Username]
+		if !ok {
+			u, err := s.Users.GetByUsername(ctx, m.Username)
+			if err != nil && !errors.Is(err, errs.ErrNotFound) {
+				return err
+			}
+
+			id = u.ID
+			users[m.Username] = id
+		}
+
+		if id != 0 {
+			m.UserID = id
+			post.Mentions = append(post.Mentions, m)
+		}
+	}
+
+	return nil
+}
+
+// Update updates a post by id when the actor owns it or is an admin. The
+// users the edit mentions for the first time are published to Events.
+func (s *Service) Update(ctx context.Context, actor Actor, id uint, post Post) error {
+	if err := post.Validate(s.Limits); err != nil {
+		return err
+	}
+
+	current, err := s.Repository.GetOne(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !actor.CanModify(current) {
+		return ErrForbidden
+	}
+
+	if current.IsRepost() {
+		return errs.Invalid(errs.FieldError{Field: "body", Message: "a repost has no body"})
+	}
+
+	if err := s.mention(ctx, &post); err != nil {
+		return err
+	}
+
+	if err := s.Repository.Update(ctx, id, post); err != nil {
+		return err
+	}
+
+	if s.Events != nil {
+		updated := current
+		updated.Body = post.Body
+		updated.Mentions = post.Mentions
+		s.Events.Publish(ctx, PostUpdated{Post: updated, Mentioned: added(current.Mentions, post.Mentions)})
+	}
+
+	return nil
+}
+
+// added returns the mentions of after naming users no mention of before
+// names.
+func added(before, after []Mention) []Mention {
+	mentioned := make(map[uint]bool)
+	for _, m := range before {
+		mentioned[m.UserID] = true
+	}
+
+	var result []Mention
+	for _, m := range after {
+		if !mentioned[m.UserID] {
+			mentioned[m.UserID] = true
+			result = append(result, m)
+		}
+	}
+
+	return result
+}
+
+// Delete removes a post by id when the actor owns it or is an admin.
+func (s *Service) Delete(ctx context.Context, actor Actor, id uint) error {
+	current, err := s.Repository.GetOne(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !actor.CanModify(current) {
+		return ErrForbidden
+	}
+
+	return s.Repository.Delete(ctx, id)
+}
@@ -0,0 +1,337 @@
+package domain_test
+
+import (
+	"context"
+	"errors"
+	"microblog/domain/post/domain"
+	mockLocal "microblog/domain/post/domain/mocks"
+	"microblog/domain/shared/errs"
+	"microblog/domain/shared/event"
+	userDomain "microblog/domain/user/domain"
+	mockUser "microblog/domain/user/domain/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// dataPost is data for test
+func dataPost() domain.Post {
+	return domain.Post{
+		ID:     uint(1),
+		Body:   "Lorem ipsum dolor sit amet, consectetur adipisicing elit.",
+		UserID: uint(1),
+	}
+}
+
+func TestActor_CanModify(t *testing.T) {
+	post := dataPost()
+
+	assert.True(t, domain.Actor{UserID: 1}.CanModify(post))
+	assert.False(t, domain.Actor{UserID: 2}.CanModify(post))
+	assert.False(t, domain.Actor{}.CanModify(domain.Post{}))
+	assert.True(t, domain.Actor{UserID: 2, Admin: true}.CanModify(post))
+}
+
+func TestService_Create(t *testing.T) {
+	mockRepository := &mockLocal.Repository{}
+	service := domain.NewService(mockRepository)
+
+	post := dataPost()
+	post.UserID = uint(99)
+	post.Original = &domain.Post{ID: 5, Body: "forged"}
+	post.ReplyCount, post.LikeCount, post.LikedByMe = 3, 5, true
+
+	mockRepository.On("Create", mock.Anything, mock.MatchedBy(func(p *domain.Post) bool {
+		return p.UserID == uint(7) && p.ID == 0 && p.Original == nil &&
+			p.ReplyCount == 0 && p.LikeCount == 0 && !p.LikedByMe
+	})).Return(nil).Once()
+
+	err := service.Create(context.Background(), domain.Actor{UserID: 7}, &post)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), post.UserID)
+	assert.Equal(t, dataPost().Body, post.Body)
+	mockRepository.AssertExpectations(t)
+}
+
+func TestService_CreatePublishes(t *testing.T) {
+	mockRepository := &mockLocal.Repository{}
+	service := domain.NewService(mockRepository)
+
+	var published []event.Event
+	bus := event.NewBus()
+	bus.Subscribe(func(ctx context.Context, e event.Event) error {
+		published = append(published, e)
+		return nil
+	})
+	service.Events = bus
+
+	mockRepository.On("Create", mock.Anything, mock.Anything).Return(errors.New("error sql")).Once()
+	err := service.Create(context.Background(), domain.Actor{UserID: 7}, &domain.Post{Body: "Lorem ipsum"})
+	assert.Error(t, err)
+	assert.Empty(t, published)
+
+	mockRepository.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+	err = service.Create(context.Background(), domain.Actor{UserID: 7}, &domain.Post{Body: "Lorem ipsum"})
+	assert.NoError(t, err)
+	if assert.Len(t, published, 1) {
+		assert.Equal(t, "post.created", published[0].Name())
+		assert.Equal(t, uint(7), published[0].(domain.PostCreated).Post.UserID)
+	}
+	mockRepository.AssertExpectations(t)
+}
+
+func TestService_CreateMentions(t *testing.T) {
+
+	t.Run("Error Resolving Username", func(tt *testing.T) {
+		mockRepository := &mockLocal.Repository{}
+		mockUsers := &mockUser.Repository{}
+		service := domain.NewService(mockRepository)
+		service.Users = mockUsers
+
+		mockUsers.On("GetByUsername", mock.Anything, "ana").Return(userDomain.User{}, errors.New("error sql")).Once()
+
+		err := service.Create(context.Background(), domain.Actor{UserID: 1}, &domain.Post{Body: "Hi @ana"})
+		assert.Error(tt, err)
+		mockRepository.AssertExpectations(tt)
+		mockUsers.AssertExpectations(tt)
+	})
+
+	t.Run("Unknown Usernames Left As Text", func(tt *testing.T) {
+		mockRepository := &mockLocal.Repository{}
+		mockUsers := &mockUser.Repository{}
+		service := domain.NewService(mockRepository)
+		service.Users = mockUsers
+
+		mockUsers.On("GetByUsername", mock.Anything, "ana").Return(userDomain.User{ID: 7, Username: "ana"}, nil).Once()
+		mockUsers.On("GetByUsername", mock.Anything, "ghost").Return(userDomain.User{}, errs.NotFound("user not found")).Once()
+		mockRepository.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+
+		post := domain.Post{Body: "héllo @ana, @ghost and @ana"}
+		assert.NoError(tt, service.Create(context.Background(), domain.Actor{UserID: 1}, &post))
+		assert.Equal(tt, []domain.Mention{
+			{UserID: 7, Username: "ana", Start: 6, End: 10},
+			{UserID: 7, Username: "ana", Start: 23, End: 27},
+		}, post.Mentions)
+		mockRepository.AssertExpectations(tt)
+		mockUsers.AssertExpectations(tt)
+	})
+
+	t.Run("Non-ASCII Usernames", func(tt *testing.T) {
+		mockRepository := &mockLocal.Repository{}
+		mockUsers := &mockUser.Repository{}
+		service := domain.NewService(mockRepository)
+		service.Users = mockUsers
+
+		mockUsers.On("GetByUsername", mock.Anything, "josé").Return(userDomain.User{ID: 7, Username: "josé"}, nil).Once()
+		mockUsers.On("GetByUsername", mock.Anything, "ñandú.rojo").Return(userDomain.User{ID: 8, Username: "ñandú.rojo"}, nil).Once()
+		mockRepository.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+
+		post := domain.Post{Body: "hola @josé y @ñandú.rojo, no ana@josé.es"}
+		assert.NoError(tt, service.Create(context.Background(), domain.Actor{UserID: 1}, &post))
+		assert.Equal(tt, []domain.Mention{
+			{UserID: 7, Username: "josé", Start: 5, End: 10},
+			{UserID: 8, Username: "ñandú.rojo", Start: 13, End: 24},
+		}, post.Mentions)
+		mockRepository.AssertExpectations(tt)
+		mockUsers.AssertExpectations(tt)
+	})
+}
+
+func TestService_Repost(t *testing.T) {
+
+	t.Run("Error Post Not Found", func(tt *testing.T) {
+		mockRepository := &mockLocal.Repository{}
+		service := domain.NewService(mockRepository)
+
+		mockRepository.On("GetOne", mock.Anything, uint(9)).Return(domain.Post{}, errs.NotFound("post not found")).Once()
+
+		_, err := service.Repost(context.Background(), domain.Actor{UserID: 7}, 9)
+		assert.True(tt, errors.Is(err, errs.ErrNotFound), "%v", err)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Repost Of A Repost Shares The Original", func(tt *testing.T) {
+		mockRepository := &mockLocal.Repository{}
+		service := domain.NewService(mockRepository)
+
+		originalID := uint(1)
+		original := domain.Post{ID: originalID, Body: "Lorem ipsum", UserID: 4}
+		mockRepository.On("GetOne", mock.Anything, uint(2)).Return(domain.Post{ID: 2, UserID: 3, RepostOf: &originalID, Original: &original}, nil).Once()
+		mockRepository.On("Create", mock.Anything, mock.MatchedBy(func(p *domain.Post) bool {
+			return p.UserID == uint(7) && *p.RepostOf == originalID
+		})).Return(nil).Once()
+
+		post, err := service.Repost(context.Background(), domain.Actor{UserID: 7}, 2)
+		assert.NoError(tt, err)
+		assert.Equal(tt, originalID, *post.RepostOf)
+		assert.Equal(tt, &original, post.Original)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Quote Of A Repost Quotes The Original", func(tt *testing.T) {
+		mockRepository := &mockLocal.Repository{}
+		service := domain.NewService(mockRepository)
+
+		originalID, repostID := uint(1), uint(2)
+		mockRepository.On("GetOne", mock.Anything, repostID).Return(domain.Post{ID: repostID, UserID: 3, RepostOf: &originalID}, nil).Once()
+		mockRepository.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+
+		quote := domain.Post{Body: "Ut enim ad minim veniam.", QuoteOf: &repostID}
+		assert.NoError(tt, service.Create(context.Background(), domain.Actor{UserID: 7}, &quote))
+		assert.Equal(tt, originalID, *quote.QuoteOf)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Quote Embeds The Post Quoted", func(tt *testing.T) {
+		mockRepository := &mockLocal.Repository{}
+		service := domain.NewService(mockRepository)
+
+		quotedID, originalID := uint(2), uint(1)
+		quoted := domain.Post{ID: quotedID, Body: "Lorem ipsum", UserID: 3, QuoteOf: &originalID, Original: &domain.Post{ID: originalID}}
+		mockRepository.On("GetOne", mock.Anything, quotedID).Return(quoted, nil).Once()
+		mockRepository.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+
+		quote := domain.Post{Body: "Ut enim ad minim veniam.", QuoteOf: &quotedID, Original: &domain.Post{ID: 9, Body: "forged"}}
+		assert.NoError(tt, service.Create(context.Background(), domain.Actor{UserID: 7}, &quote))
+		if assert.NotNil(tt, quote.Original) {
+			assert.Equal(tt, quotedID, quote.Original.ID)
+			assert.Equal(tt, quoted.Body, quote.Original.Body)
+			assert.Nil(tt, quote.Original.Original)
+		}
+		mockRepository.AssertExpectations(tt)
+	})
+}
+
+func TestService_Update(t *testing.T) {
+
+	t.Run("Error Repost Has No Body", func(tt *testing.T) {
+		mockRepository := &mockLocal.Repository{}
+		service := domain.NewService(mockRepository)
+
+		originalID := uint(2)
+		mockRepository.On("GetOne", mock.Anything, uint(1)).Return(domain.Post{ID: 1, UserID: 1, RepostOf: &originalID}, nil).Once()
+
+		err := service.Update(context.Background(), domain.Actor{UserID: 1}, 1, dataPost())
+		assert.True(tt, errors.Is(err, errs.ErrValidation), "%v", err)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Error Post Not Found", func(tt *testing.T) {
+		mockRepository := &mockLocal.Repository{}
+		service := domain.NewService(mockRepository)
+
+		mockRepository.On("GetOne", mock.Anything, uint(1)).Return(domain.Post{}, errors.New("error sql")).Once()
+
+		err := service.Update(context.Background(), domain.Actor{UserID: 1}, 1, dataPost())
+		assert.Error(tt, err)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Error Not Owner", func(tt *testing.T) {
+		mockRepository := &mockLocal.Repository{}
+		service := domain.NewService(mockRepository)
+
+		mockRepository.On("GetOne", mock.Anything, uint(1)).Return(dataPost(), nil).Once()
+
+		err := service.Update(context.Background(), domain.Actor{UserID: 2}, 1, dataPost())
+		assert.Equal(tt, domain.ErrForbidden, err)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Update By Admin", func(tt *testing.T) {
+		mockRepository := &mockLocal.Repository{}
+		service := domain.NewService(mockRepository)
+
+		mockRepository.On("GetOne", mock.Anything, uint(1)).Return(dataPost(), nil).Once()
+		mockRepository.On("Update", mock.Anything, uint(1), mock.Anything).Return(nil).Once()
+
+		err := service.Update(context.Background(), domain.Actor{UserID: 2, Admin: true}, 1, dataPost())
+		assert.NoError(tt, err)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Publishes The Mentions Added", func(tt *testing.T) {
+		mockRepository := &mockLocal.Repository{}
+		mockUsers := &mockUser.Repository{}
+		service := domain.NewService(mockRepository)
+		service.Users = mockUsers
+
+		var published []event.Event
+		bus := event.NewBus()
+		bus.Subscribe(func(ctx context.Context, e event.Event) error {
+			published = append(published, e)
+			return nil
+		})
+		service.Events = bus
+
+		current := dataPost()
+		current.Body = "hola @ana"
+		current.Mentions = []domain.Mention{{UserID: 7, Username: "ana", Start: 5, End: 9}}
+		mockRepository.On("GetOne", mock.Anything, uint(1)).Return(current, nil).Once()
+		mockUsers.On("GetByUsername", mock.Anything, "ana").Return(userDomain.User{ID: 7}, nil).Once()
+		mockUsers.On("GetByUsername", mock.Anything, "bob").Return(userDomain.User{ID: 8}, nil).Once()
+		mockRepository.On("Update", mock.Anything, uint(1), mock.Anything).Return(nil).Once()
+
+		err := service.Update(context.Background(), domain.Actor{UserID: 2, Admin: true}, 1, domain.Post{Body: "hola @ana y @bob"})
+		assert.NoError(tt, err)
+		if assert.Len(tt, published, 1) {
+			updated := published[0].(domain.PostUpdated)
+			assert.Equal(tt, "post.updated", updated.Name())
+			assert.Equal(tt, uint(1), updated.Post.ID)
+			assert.Equal(tt, uint(1), updated.Post.UserID)
+			assert.Equal(tt, "hola @ana y @bob", updated.Post.Body)
+			assert.Len(tt, updated.Post.Mentions, 2)
+			assert.Equal(tt, []domain.Mention{{UserID: 8, Username: "bob", Start: 12, End: 16}}, updated.Mentioned)
+		}
+		mockRepository.AssertExpectations(tt)
+		mockUsers.AssertExpectations(tt)
+	})
+
+	t.Run("Error Update Publishes Nothing", func(tt *testing.T) {
+		mockRepository := &mockLocal.Repository{}
+		service := domain.NewService(mockRepository)
+
+		var published []event.Event
+		bus := event.NewBus()
+		bus.Subscribe(func(ctx context.Context, e event.Event) error {
+			published = append(published, e)
+			return nil
+		})
+		service.Events = bus
+
+		mockRepository.On("GetOne", mock.Anything, uint(1)).Return(dataPost(), nil).Once()
+		mockRepository.On("Update", mock.Anything, uint(1), mock.Anything).Return(errors.New("error sql")).Once()
+
+		err := service.Update(context.Background(), domain.Actor{UserID: 1}, 1, dataPost())
+		assert.Error(tt, err)
+		assert.Empty(tt, published)
+		mockRepository.AssertExpectations(tt)
+	})
+}
+
+func TestService_Delete(t *testing.T) {
+
+	t.Run("Error Not Owner", func(tt *testing.T) {
+		mockRepository := &mockLocal.Repository{}
+		service := domain.NewService(mockRepository)
+
+		mockRepository.On("GetOne", mock.Anything, uint(1)).Return(dataPost(), nil).Once()
+
+		err := service.Delete(context.Background(), domain.Actor{UserID: 2}, 1)
+		assert.Equal(tt, domain.ErrForbidden, err)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Delete By Owner", func(tt *testing.T) {
+		mockRepository := &mockLocal.Repository{}
+		service := domain.NewService(mockRepository)
+
+		mockRepository.On("GetOne", mock.Anything, uint(1)).Return(dataPost(), nil).Once()
+		mockRepository.On("Delete", mock.Anything, uint(1)).Return(nil).Once()
+
+		err := service.Delete(context.Background(), domain.Actor{UserID: 1}, 1)
+		assert.NoError(tt, err)
+		mockRepository.AssertExpectations(tt)
+	})
+}
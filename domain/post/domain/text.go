@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
+
+var (
+	// linkPattern matches the http and https links of a post body.
+	linkPattern = regexp.MustCompile(`https?://[^\s]+`)
+
+	// mentionPattern matches the @username mentions of a post body. A
+	// username is made of letters, digits and underscores of any script,
+	// dot separated. The character before the @ keeps emails from being
+	// taken as mentions.
+	mentionPattern = regexp.MustCompile(`(?:^|[^\pL\pN_@.])@([\pL\pN_]+(?:\.[\pL\pN_]+)*)`)
+
+	// hashtagPattern matches the #hashtags of a post body. A hashtag has a
+	// letter at least and does not follow a word, so C# and the fragments
+	// of links are left out.
+	hashtagPattern = regexp.MustCompile(`(?:^|[^\pL\pN\pM_#&/])#([\pL\pN\pM_]*[\pL_][\pL\pN\pM_]*)`)
+)
+
+// MaxTagLength is the length in bytes of the longest hashtag stored.
+const MaxTagLength = 100
+
+// graphemes returns the number of user-perceived characters of s, the
+// extended grapheme clusters of Unicode.
+func graphemes(s string) int {
+	return uniseg.GraphemeClusterCount(s)
+}
+
+// links returns the links of a post body, in order.
+func links(body string) []string {
+	return linkPattern.FindAllString(body, -1)
+}
+
+// hashtags returns the distinct hashtags of a post body, in order of
+// appearance and normalized, skipping the ones longer than MaxTagLength.
+func hashtags(body string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+
+	for _, match := range hashtagPattern.FindAllStringSubmatch(body, -1) {
+		tag := NormalizeTag(match[1])
+		if len(tag) <= MaxTagLength && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
+// NormalizeTag returns the form a hashtag is stored and looked up with: lower
+// cased and without the leading #.
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(tag, "#"))
+}
+
+// mentionSpans returns the @username mentions of a post body as written, in
+// order, with their offsets in runes and no user resolved.
+func mentionSpans(body string) []Mention {
+	var spans []Mention
+	for _, match := range mentionPattern.FindAllStringSubmatchIndex(body, -1) {
+		// match[2] is where the username starts, right after the @.
+		start := utf8.RuneCountInString(body[:match[2]-1])
+		username := body[match[2]:match[3]]
+
+		spans = append(spans, Mention{
+			Username: username,
+			Start:    start,
+			End:      start + 1 + utf8.RuneCountInString(username),
+		})
+	}
+
+	return spans
+}
+
+// mentions returns the distinct usernames mentioned in a post body, in
+// order of appearance and as written. Usernames are case sensitive, like
+// the lookup that resolves them.
+func mentions(body string) []string {
+	var usernames []string
+	seen := make(map[string]bool)
+
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		username := match[1]
+		if !seen[username] {
+			seen[username] = true
+			usernames = append(usernames, username)
+		}
+	}
+
+	return usernames
+}
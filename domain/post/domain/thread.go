@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"microblog/domain/shared/page"
+	"sort"
+)
+
+// Thread is the conversation around a post: the chain of posts it replies
+// to, root first, and the tree of its replies.
+type Thread struct {
+	Ancestors []Post  `json:"ancestors"`
+	Post      Post    `json:"post"`
+	Replies   []Reply `json:"replies"`
+}
+
+// Reply is a post of a thread along with its own replies, oldest first.
+type Reply struct {
+	Post
+	Replies []Reply `json:"replies"`
+}
+
+// NewThread returns the thread of post given its ancestors, root first, and
+// its descendants in any order.
+func NewThread(ancestors []Post, post Post, descendants []Post) Thread {
+	children := make(map[uint][]Post)
+	for _, d := range descendants {
+		if d.InReplyTo != nil {
+			children[*d.InReplyTo] = append(children[*d.InReplyTo], d)
+		}
+	}
+
+	if ancestors == nil {
+		ancestors = []Post{}
+	}
+
+	return Thread{Ancestors: ancestors, Post: post, Replies: replies(children, post.ID)}
+}
+
+// replies returns the tree of the replies to the post with the given id.
+func replies(children map[uint][]Post, id uint) []Reply {
+	posts := children[id]
+	sort.Slice(posts, func(i, j int) bool {
+		return posts[i].Cursor().Before(posts[j].Cursor(), page.Asc)
+	})
+
+	tree := make([]Reply, 0, len(posts))
+	for _, p := range posts {
+		tree = append(tree, Reply{Post: p, Replies: replies(children, p.ID)})
+	}
+
+	return tree
+}
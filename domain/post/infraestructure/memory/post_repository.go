@@ -0,0 +1,317 @@
+package memory
+
+import (
+	"context"
+	followDomain "microblog/domain/follow/domain"
+	"microblog/domain/post/domain"
+	"microblog/domain/shared/errs"
+	"microblog/domain/shared/page"
+	"sort"
+	"sync"
+	"time"
+)
+
+// errPostNotFound is returned when no post matches.
+var errPostNotFound = errs.NotFound("post not found")
+
+// PostRepository keeps the posts in memory. The timeline reads the follow
+// graph from Follows. It is safe for concurrent use.
+type PostRepository struct {
+	Follows followDomain.Repository
+
+	mu     sync.RWMutex
+	lastID uint
+	posts  map[uint]domain.Post
+}
+
+// NewPostRepository returns an empty PostRepository.
+func NewPostRepository(follows followDomain.Repository) *PostRepository {
+	return &PostRepository{Follows: follows, posts: make(map[uint]domain.Post)}
+}
+
+// GetAll returns a page of all posts.
+func (pr *PostRepository) GetAll(ctx context.Context, p page.Request) ([]domain.Post, page.Info, error) {
+	posts, info := pr.page(p, func(post domain.Post) bool { return true })
+
+	return posts, info, nil
+}
+
+// GetOne returns one post by id.
+func (pr *PostRepository) GetOne(ctx context.Context, id uint) (domain.Post, error) {
+	pr.mu.RLock()
+	defer pr.mu.RUnlock()
+
+	post, ok := pr.posts[id]
+	if !ok {
+		return domain.Post{}, errPostNotFound
+	}
+
+	return pr.embed(post), nil
+}
+
+// GetByUser returns a page of the user posts.
+func (pr *PostRepository) GetByUser(ctx context.Context, userID uint, p page.Request) ([]domain.Post, page.Info, error) {
+	posts, info := pr.page(p, func(post domain.Post) bool { return post.UserID == userID })
+
+	return posts, info, nil
+}
+
+// GetByTag returns a page of the posts tagged with the hashtag, read from
+// their current bodies.
+func (pr *PostRepository) GetByTag(ctx context.Context, tag string, p page.Request) ([]domain.Post, page.Info, error) {
+	posts, info := pr.page(p, func(post domain.Post) bool {
+		for _, t := range post.Tags() {
+			if t == tag {
+				return true
+			}
+		}
+
+		return false
+	})
+
+	return posts, info, nil
+}
+
+// GetTimeline returns the posts of the user and of the users it follows, newest first.
+func (pr *PostRepository) GetTimeline(ctx context.Context, userID uint, p page.Request) ([]domain.Post, page.Info, error) {
+	authors, err := pr.authors(ctx, userID)
+	if err != nil {
+		return nil, page.Info{}, err
+	}
+
+	posts, info := pr.page(p, func(post domain.Post) bool { return authors[post.UserID] })
+
+	return posts, info, nil
+}
+
+// authors returns the user and the users it follows, reading the follow
+// graph page by page.
+func (pr *PostRepository) authors(ctx context.Context, userID uint) (map[uint]bool, error) {
+	authors := map[uint]bool{userID: true}
+
+	p := page.Request{Limit: page.MaxLimit, Order: page.Desc}
+	for {
+		following, info, err := pr.Follows.GetFollowing(ctx, userID, p)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range following {
+			authors[c.UserID] = true
+		}
+
+		if info.NextCursor == "" {
+			return authors, nil
+		}
+
+		next, err := page.DecodeCursor(info.NextCursor)
+		if err != nil {
+			return nil, err
+		}
+
+		p.Cursor = &next
+	}
+}
+
+// GetThread returns the post by id along with its ancestors and the tree of
+// its replies.
+func (pr *PostRepository) GetThread(ctx context.Context, id uint) (domain.Thread, error) {
+	pr.mu.RLock()
+	defer pr.mu.RUnlock()
+
+	post, ok := pr.posts[id]
+	if !ok {
+		return domain.Thread{}, errPostNotFound
+	}
+
+	var ancestors []domain.Post
+	for parent := post.InReplyTo; parent != nil; {
+		ancestor := pr.posts[*parent]
+		ancestors = append([]domain.Post{ancestor}, ancestors...)
+		parent = ancestor.InReplyTo
+	}
+
+	var descendants []domain.Post
+	for pending := []uint{id}; len(pending) > 0; pending = pending[1:] {
+		for _, p := range pr.posts {
+			if p.InReplyTo != nil && *p.InReplyTo == pending[0] {
+				descendants = append(descendants, p)
+				pending = append(pending, p.ID)
+			}
+		}
+	}
+
+	for i := range ancestors {
+		ancestors[i] = pr.embed(ancestors[i])
+	}
+
+	for i := range descendants {
+		descendants[i] = pr.embed(descendants[i])
+	}
+
+	return domain.NewThread(ancestors, pr.embed(post), descendants), nil
+}
+
+// embed returns the post with the post it refers to as its original. The
+// caller must hold the lock.
+func (pr *PostRepository) embed(post domain.Post) domain.Post {
+	if ref := post.Refers(); ref != nil {
+		if original, ok := pr.posts[*ref]; ok {
+			post.Original = &original
+		}
+	}
+
+	return post
+}
+
+// Create adds a new post. A reply counts on the post it answers.
+func (pr *PostRepository) Create(ctx context.Context, p *domain.Post) error {
+	now := time.Now().Truncate(time.Microsecond)
+
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	if ref := p.Refers(); ref != nil {
+		if _, ok := pr.posts[*ref]; !ok {
+			return errPostNotFound
+		}
+	}
+
+	if p.RepostOf != nil {
+		for _, stored := range pr.posts {
+			if stored.UserID == p.UserID && stored.RepostOf != nil && *stored.RepostOf == *p.RepostOf {
+				return domain.ErrReposted
+			}
+		}
+	}
+
+	if p.InReplyTo != nil {
+		parent, ok := pr.posts[*p.InReplyTo]
+		if !ok {
+			return errPostNotFound
+		}
+
+		parent.ReplyCount++
+		pr.posts[parent.ID] = parent
+	}
+
+	pr.lastID++
+	p.ID = pr.lastID
+	p.ReplyCount, p.LikeCount, p.LikedByMe = 0, 0, false
+	p.CreatedAt = now
+	p.UpdatedAt = now
+
+	stored := *p
+	stored.InReplyTo = copyID(p.InReplyTo)
+	stored.RepostOf = copyID(p.RepostOf)
+	stored.QuoteOf = copyID(p.QuoteOf)
+	stored.Mentions = append([]domain.Mention(nil), p.Mentions...)
+	stored.Original = nil
+
+	pr.posts[p.ID] = stored
+
+	return nil
+}
+
+// Update updates the body and the mentions of a post by id.
+func (pr *PostRepository) Update(ctx context.Context, id uint, p domain.Post) error {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	stored, ok := pr.posts[id]
+	if !ok {
+		return errPostNotFound
+	}
+
+	stored.Body = p.Body
+	stored.Mentions = append([]domain.Mention(nil), p.Mentions...)
+	stored.UpdatedAt = time.Now().Truncate(time.Microsecond)
+	pr.posts[id] = stored
+
+	return nil
+}
+
+// copyID returns a copy of the optional id, so the stored posts share no
+// memory with the callers.
+func copyID(id *uint) *uint {
+	if id == nil {
+		return nil
+	}
+
+	value := *id
+
+	return &value
+}
+
+// Delete removes a post by id along with its reposts. Its replies and
+// quotes are kept and no longer refer to any post.
+func (pr *PostRepository) Delete(ctx context.Context, id uint) error {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	if _, ok := pr.posts[id]; !ok {
+		return errPostNotFound
+	}
+
+	pr.remove(id)
+
+	return nil
+}
+
+// remove deletes the post by id as Delete does. The caller must hold the
+// lock.
+func (pr *PostRepository) remove(id uint) {
+	post := pr.posts[id]
+
+	if post.InReplyTo != nil {
+		if parent, ok := pr.posts[*post.InReplyTo]; ok {
+			parent.ReplyCount--
+			pr.posts[parent.ID] = parent
+		}
+	}
+
+	delete(pr.posts, id)
+
+	var reposts []uint
+	for _, p := range pr.posts {
+		if p.RepostOf != nil && *p.RepostOf == id {
+			reposts = append(reposts, p.ID)
+		}
+
+		if p.InReplyTo != nil && *p.InReplyTo == id {
+			p.InReplyTo = nil
+			pr.posts[p.ID] = p
+		}
+
+		if p.QuoteOf != nil && *p.QuoteOf == id {
+			p.QuoteOf = nil
+			pr.posts[p.ID] = p
+		}
+	}
+
+	for _, repost := range reposts {
+		pr.remove(repost)
+	}
+}
+
+// page returns the page of the posts that match.
+func (pr *PostRepository) page(p page.Request, match func(post domain.Post) bool) ([]domain.Post, page.Info) {
+	pr.mu.RLock()
+	var posts []domain.Post
+	for _, post := range pr.posts {
+		if match(post) {
+			posts = append(posts, pr.embed(post))
+		}
+	}
+	pr.mu.RUnlock()
+
+	sort.Slice(posts, func(i, j int) bool {
+		return posts[i].Cursor().Before(posts[j].Cursor(), p.Order)
+	})
+
+	from, to, info := p.Slice(len(posts), func(i int) page.Cursor {
+		return posts[i].Cursor()
+	})
+
+	return posts[from:to], info
+}
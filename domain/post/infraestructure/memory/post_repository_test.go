@@ -0,0 +1,39 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	followDomain "microblog/domain/follow/domain"
+	memoryFollow "microblog/domain/follow/infraestructure/memory"
+	"microblog/domain/post/domain/contract"
+	userContract "microblog/domain/user/domain/contract"
+	memoryUser "microblog/domain/user/infraestructure/memory"
+	"testing"
+)
+
+func TestPostRepository_Contract(t *testing.T) {
+	contract.TestRepository(t, func(t *testing.T) contract.Harness {
+		users := memoryUser.NewUserRepository()
+		follows := memoryFollow.NewFollowRepository(users)
+		n := 0
+
+		return contract.Harness{
+			Repository: NewPostRepository(follows),
+			NewUser: func(t *testing.T) uint {
+				n++
+				u := userContract.NewUser(fmt.Sprintf("user.%d", n))
+				if err := users.Create(context.Background(), &u); err != nil {
+					t.Fatalf("error creating user: %v", err)
+				}
+
+				return u.ID
+			},
+			Follow: func(t *testing.T, followerID, followingID uint) {
+				f := followDomain.Follow{FollowerID: followerID, FollowingID: followingID}
+				if _, err := follows.Follow(context.Background(), &f); err != nil {
+					t.Fatalf("error following: %v", err)
+				}
+			},
+		}
+	})
+}
@@ -2,59 +2,333 @@ package persistence
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 	"microblog/domain/post/domain"
+	"microblog/domain/shared/page"
+	"strings"
 	"time"
 
+	"github.com/lib/pq"
 	conn "microblog/infrastructure/database"
+	"microblog/infrastructure/tracing"
 )
 
+// errPostNotFound is the message of the error returned when no post matches.
+const errPostNotFound = "post not found"
+
+// Columns are the columns of a post p read by ScanPost, the count of its
+// direct replies included. Other adapters joining posts select them too.
+const Columns = `p.id, p.body, p.user_id, p.in_reply_to, p.repost_of, p.quote_of, p.created_at, p.updated_at,
+	(SELECT count(*) FROM posts r WHERE r.in_reply_to = p.id) AS reply_count`
+
+// Scanner is implemented by *sql.Row and *sql.Rows.
+type Scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// ScanPost reads a post selected with Columns, followed by the extra
+// columns the query selects after them.
+func ScanPost(row Scanner, extra ...interface{}) (domain.Post, error) {
+	var p domain.Post
+	var inReplyTo, repostOf, quoteOf sql.NullInt64
+
+	dest := append([]interface{}{&p.ID, &p.Body, &p.UserID, &inReplyTo, &repostOf, &quoteOf, &p.CreatedAt, &p.UpdatedAt, &p.ReplyCount}, extra...)
+	err := row.Scan(dest...)
+	if err != nil {
+		return domain.Post{}, err
+	}
+
+	p.InReplyTo = fromNull(inReplyTo)
+	p.RepostOf = fromNull(repostOf)
+	p.QuoteOf = fromNull(quoteOf)
+
+	return p, nil
+}
+
+// fromNull returns the id held by a nullable column, nil when it is NULL.
+func fromNull(n sql.NullInt64) *uint {
+	if !n.Valid {
+		return nil
+	}
+
+	id := uint(n.Int64)
+
+	return &id
+}
+
+// toNull returns the nullable column value of an optional id.
+func toNull(id *uint) sql.NullInt64 {
+	if id == nil {
+		return sql.NullInt64{}
+	}
+
+	return sql.NullInt64{Int64: int64(*id), Valid: true}
+}
+
 // PostRepository manages the operations with the database that
 // correspond to the post model.
 type PostRepository struct {
 	Data *conn.Data
 }
 
-// GetAll returns all posts.
-func (pr *PostRepository) GetAll(ctx context.Context) ([]domain.Post, error) {
-	query := `SELECT id, body, user_id, created_at, updated_at FROM posts;`
+// GetAll returns a page of all posts.
+func (pr *PostRepository) GetAll(ctx context.Context, p page.Request) (posts []domain.Post, info page.Info, err error) {
+	ctx, span := tracing.Query(ctx, "PostRepository.GetAll", "selectPosts")
+	defer span.End(&err)
 
-	rows, err := pr.Data.DB.QueryContext(ctx, query)
+	op, order := p.Seek()
+	query := fmt.Sprintf(`SELECT %s FROM posts p
+		WHERE ($1::timestamp IS NULL OR (p.created_at, p.id) %s ($1::timestamp, $2))
+		ORDER BY p.created_at %s, p.id %s LIMIT $3;`, Columns, op, order, order)
+
+	after, afterID := p.Position()
+
+	posts, info, err = pr.page(ctx, p, query, after, afterID, p.Fetch())
+	span.Rows(len(posts))
+
+	return posts, info, err
+}
+
+// GetOne returns one post by id.
+func (pr *PostRepository) GetOne(ctx context.Context, id uint) (post domain.Post, err error) {
+	ctx, span := tracing.Query(ctx, "PostRepository.GetOne", "selectPostById")
+	defer span.End(&err)
+
+	query := `SELECT ` + Columns + ` FROM posts p WHERE p.id = $1;`
+
+	row := pr.Data.DB.QueryRowContext(ctx, query, id)
+
+	p, err := ScanPost(row)
 	if err != nil {
-		return nil, err
+		return domain.Post{}, conn.Error(err, errPostNotFound)
+	}
+
+	span.Rows(1)
+
+	posts := []domain.Post{p}
+	if err := pr.Complete(ctx, posts); err != nil {
+		return domain.Post{}, err
+	}
+
+	return posts[0], nil
+}
+
+// GetByUser returns a page of the user posts.
+func (pr *PostRepository) GetByUser(ctx context.Context, userID uint, p page.Request) (posts []domain.Post, info page.Info, err error) {
+	ctx, span := tracing.Query(ctx, "PostRepository.GetByUser", "selectPostsByUser")
+	defer span.End(&err)
+
+	op, order := p.Seek()
+	query := fmt.Sprintf(`SELECT %s FROM posts p
+		WHERE p.user_id = $1 AND ($2::timestamp IS NULL OR (p.created_at, p.id) %s ($2::timestamp, $3))
+		ORDER BY p.created_at %s, p.id %s LIMIT $4;`, Columns, op, order, order)
+
+	after, afterID := p.Position()
+
+	posts, info, err = pr.page(ctx, p, query, userID, after, afterID, p.Fetch())
+	span.Rows(len(posts))
+
+	return posts, info, err
+}
+
+// GetByTag returns a page of the posts tagged with the hashtag.
+func (pr *PostRepository) GetByTag(ctx context.Context, tag string, p page.Request) (posts []domain.Post, info page.Info, err error) {
+	ctx, span := tracing.Query(ctx, "PostRepository.GetByTag", "selectPostsByTag")
+	defer span.End(&err)
+
+	op, order := p.Seek()
+	query := fmt.Sprintf(`SELECT %s FROM posts p JOIN post_tags t ON t.post_id = p.id
+		WHERE t.tag = $1 AND ($2::timestamp IS NULL OR (p.created_at, p.id) %s ($2::timestamp, $3))
+		ORDER BY p.created_at %s, p.id %s LIMIT $4;`, Columns, op, order, order)
+
+	after, afterID := p.Position()
+
+	posts, info, err = pr.page(ctx, p, query, tag, after, afterID, p.Fetch())
+	span.Rows(len(posts))
+
+	return posts, info, err
+}
+
+// GetTimeline returns the posts of the user and of the users it follows, newest first.
+func (pr *PostRepository) GetTimeline(ctx context.Context, userID uint, p page.Request) (posts []domain.Post, info page.Info, err error) {
+	ctx, span := tracing.Query(ctx, "PostRepository.GetTimeline", "selectTimeline")
+	defer span.End(&err)
+
+	op, order := p.Seek()
+	query := fmt.Sprintf(`SELECT %s FROM posts p
+		WHERE (p.user_id = $1 OR p.user_id IN (SELECT following_id FROM follows WHERE follower_id = $1))
+		AND ($2::timestamp IS NULL OR (p.created_at, p.id) %s ($2::timestamp, $3))
+		ORDER BY p.created_at %s, p.id %s LIMIT $4;`, Columns, op, order, order)
+
+	after, afterID := p.Position()
+
+	posts, info, err = pr.page(ctx, p, query, userID, after, afterID, p.Fetch())
+	span.Rows(len(posts))
+
+	return posts, info, err
+}
+
+// GetThread returns the post by id along with its ancestors and the tree of
+// its replies, both walked with recursive queries.
+func (pr *PostRepository) GetThread(ctx context.Context, id uint) (thread domain.Thread, err error) {
+	ctx, span := tracing.Query(ctx, "PostRepository.GetThread", "selectThread")
+	defer span.End(&err)
+
+	post, err := pr.GetOne(ctx, id)
+	if err != nil {
+		return domain.Thread{}, err
+	}
+
+	ancestors, err := pr.list(ctx, `WITH RECURSIVE ancestors AS (
+			SELECT in_reply_to AS id, 1 AS depth FROM posts WHERE id = $1
+			UNION ALL
+			SELECT p.in_reply_to, a.depth + 1 FROM posts p JOIN ancestors a ON p.id = a.id
+		)
+		SELECT `+Columns+` FROM posts p JOIN ancestors a ON p.id = a.id
+		ORDER BY a.depth DESC;`, id)
+	if err != nil {
+		return domain.Thread{}, err
+	}
+
+	descendants, err := pr.list(ctx, `WITH RECURSIVE descendants AS (
+			SELECT id FROM posts WHERE in_reply_to = $1
+			UNION ALL
+			SELECT p.id FROM posts p JOIN descendants d ON p.in_reply_to = d.id
+		)
+		SELECT `+Columns+` FROM posts p JOIN descendants d ON p.id = d.id;`, id)
+	if err != nil {
+		return domain.Thread{}, err
+	}
+
+	if err := pr.Complete(ctx, ancestors); err != nil {
+		return domain.Thread{}, err
+	}
+
+	if err := pr.Complete(ctx, descendants); err != nil {
+		return domain.Thread{}, err
+	}
+
+	span.Rows(len(ancestors) + 1 + len(descendants))
+
+	return domain.NewThread(ancestors, post, descendants), nil
+}
+
+// Complete sets the mentions of the posts and embeds the originals of the
+// reposts and quotes among them. Other adapters reading posts with Columns
+// complete them too.
+func (pr *PostRepository) Complete(ctx context.Context, posts []domain.Post) error {
+	if err := pr.mentions(ctx, posts); err != nil {
+		return err
+	}
+
+	return pr.embed(ctx, posts)
+}
+
+// mentions sets the mentions of the posts, read along with the current
+// username of the users they name. Only the bodies with an @ are looked up.
+func (pr *PostRepository) mentions(ctx context.Context, posts []domain.Post) (err error) {
+	var ids []int64
+	for _, p := range posts {
+		if strings.Contains(p.Body, "@") {
+			ids = append(ids, int64(p.ID))
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	ctx, span := tracing.Query(ctx, "PostRepository.mentions", "selectMentions")
+	defer span.End(&err)
+
+	rows, err := pr.Data.DB.QueryContext(ctx, `SELECT m.post_id, m.user_id, u.username, m.start_offset, m.end_offset
+		FROM post_mentions m JOIN users u ON u.id = m.user_id
+		WHERE m.post_id = ANY($1) ORDER BY m.post_id, m.start_offset;`, pq.Array(ids))
+	if err != nil {
+		return err
 	}
 
 	defer rows.Close()
 
-	var posts []domain.Post
+	n := 0
+	byPost := make(map[uint][]domain.Mention)
 	for rows.Next() {
-		var p domain.Post
-		_ = rows.Scan(&p.ID, &p.Body, &p.UserID, &p.CreatedAt, &p.UpdatedAt)
-		posts = append(posts, p)
+		var postID uint
+		var m domain.Mention
+		err := rows.Scan(&postID, &m.UserID, &m.Username, &m.Start, &m.End)
+		if err != nil {
+			return err
+		}
+
+		byPost[postID] = append(byPost[postID], m)
+		n++
 	}
 
-	return posts, nil
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	span.Rows(n)
+
+	for i := range posts {
+		posts[i].Mentions = byPost[posts[i].ID]
+	}
+
+	return nil
 }
 
-// GetOne returns one post by id.
-func (pr *PostRepository) GetOne(ctx context.Context, id uint) (domain.Post, error) {
-	query := `SELECT id, body, user_id, created_at, updated_at FROM posts WHERE id = $1;`
+// embed sets the post each repost or quote refers to as its original, its
+// mentions included. A quote whose original was deleted no longer refers to
+// it.
+func (pr *PostRepository) embed(ctx context.Context, posts []domain.Post) (err error) {
+	var ids []int64
+	for _, p := range posts {
+		if ref := p.Refers(); ref != nil {
+			ids = append(ids, int64(*ref))
+		}
+	}
 
-	row := pr.Data.DB.QueryRowContext(ctx, query, id)
+	if len(ids) == 0 {
+		return nil
+	}
 
-	var p domain.Post
-	err := row.Scan(&p.ID, &p.Body, &p.UserID, &p.CreatedAt, &p.UpdatedAt)
+	ctx, span := tracing.Query(ctx, "PostRepository.embed", "selectOriginals")
+	defer span.End(&err)
+
+	originals, err := pr.list(ctx, `SELECT `+Columns+` FROM posts p WHERE p.id = ANY($1);`, pq.Array(ids))
 	if err != nil {
-		return domain.Post{}, err
+		return err
 	}
 
-	return p, nil
-}
+	span.Rows(len(originals))
+
+	if err := pr.mentions(ctx, originals); err != nil {
+		return err
+	}
+
+	byID := make(map[uint]domain.Post, len(originals))
+	for _, o := range originals {
+		byID[o.ID] = o
+	}
+
+	for i := range posts {
+		ref := posts[i].Refers()
+		if ref == nil {
+			continue
+		}
 
-// GetByUser returns all user posts.
-func (pr *PostRepository) GetByUser(ctx context.Context, userID uint) ([]domain.Post, error) {
-	query := `SELECT id, body, user_id, created_at, updated_at FROM posts WHERE user_id = $1;`
+		if original, ok := byID[*ref]; ok {
+			posts[i].Original = &original
+		}
+	}
+
+	return nil
+}
 
-	rows, err := pr.Data.DB.QueryContext(ctx, query, userID)
+// list runs a query that selects Columns and returns the posts it reads.
+func (pr *PostRepository) list(ctx context.Context, query string, args ...interface{}) ([]domain.Post, error) {
+	rows, err := pr.Data.DB.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -63,57 +337,170 @@ func (pr *PostRepository) GetByUser(ctx context.Context, userID uint) ([]domain.
 
 	var posts []domain.Post
 	for rows.Next() {
-		var p domain.Post
-		_ = rows.Scan(&p.ID, &p.Body, &p.UserID, &p.CreatedAt, &p.UpdatedAt)
-		posts = append(posts, p)
+		post, err := ScanPost(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		posts = append(posts, post)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
 	return posts, nil
 }
 
-// Create adds a new post.
-func (pr *PostRepository) Create(ctx context.Context, p *domain.Post) error {
-	query := `INSERT INTO posts (body, user_id, created_at, updated_at) VALUES ($1, $2, $3, $4) RETURNING id;`
+// page runs a query that reads posts in the order given by p.Seek and
+// returns them in list order along with the page cursors.
+func (pr *PostRepository) page(ctx context.Context, p page.Request, query string, args ...interface{}) ([]domain.Post, page.Info, error) {
+	posts, err := pr.list(ctx, query, args...)
+	if err != nil {
+		return nil, page.Info{}, err
+	}
 
-	stmt, err := pr.Data.DB.PrepareContext(ctx, query)
+	keep, more := p.Trim(len(posts))
+	posts = posts[:keep]
+	if keep == 0 {
+		return posts, page.Info{}, nil
+	}
+
+	if p.Backward() {
+		for i, j := 0, keep-1; i < j; i, j = i+1, j-1 {
+			posts[i], posts[j] = posts[j], posts[i]
+		}
+	}
+
+	if err := pr.Complete(ctx, posts); err != nil {
+		return nil, page.Info{}, err
+	}
+
+	return posts, p.Info(posts[0].Cursor(), posts[keep-1].Cursor(), more), nil
+}
+
+// Create adds a new post along with its hashtags in a transaction. The post
+// gets the id and timestamps stored and no replies nor likes.
+func (pr *PostRepository) Create(ctx context.Context, p *domain.Post) (err error) {
+	ctx, span := tracing.Query(ctx, "PostRepository.Create", "insertPost")
+	defer span.End(&err)
+
+	query := `INSERT INTO posts (body, user_id, in_reply_to, repost_of, quote_of, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6) RETURNING id, created_at, updated_at;`
+
+	tx, err := pr.Data.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 
-	defer stmt.Close()
-	row := stmt.QueryRowContext(ctx, p.Body, p.UserID, time.Now(), time.Now())
+	defer tx.Rollback()
+
+	row := tx.QueryRowContext(ctx, query, p.Body, p.UserID, toNull(p.InReplyTo), toNull(p.RepostOf), toNull(p.QuoteOf), time.Now())
 
-	err = row.Scan(&p.ID)
+	err = row.Scan(&p.ID, &p.CreatedAt, &p.UpdatedAt)
 	if err != nil {
+		return conn.Error(err, errPostNotFound)
+	}
+
+	p.ReplyCount, p.LikeCount, p.LikedByMe = 0, 0, false
+
+	span.Rows(1)
+
+	if err := syncTags(ctx, tx, p.ID, p.Tags()); err != nil {
 		return err
 	}
 
-	return nil
+	if err := syncMentions(ctx, tx, p.ID, p.Mentions); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-// Update updates a post by id.
-func (pr *PostRepository) Update(ctx context.Context, id uint, p domain.Post) error {
+// Update updates a post by id and replaces its hashtags in a transaction.
+func (pr *PostRepository) Update(ctx context.Context, id uint, p domain.Post) (err error) {
+	ctx, span := tracing.Query(ctx, "PostRepository.Update", "updatePost")
+	defer span.End(&err)
+
 	query := `UPDATE posts set body=$1, updated_at=$2 WHERE id=$3;`
 
-	stmt, err := pr.Data.DB.PrepareContext(ctx, query)
+	tx, err := pr.Data.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 
-	defer stmt.Close()
-	_, err = stmt.ExecContext(
-		ctx, p.Body, time.Now(), id,
-	)
+	defer tx.Rollback()
 
+	result, err := tx.ExecContext(ctx, query, p.Body, time.Now(), id)
 	if err != nil {
+		return conn.Error(err, errPostNotFound)
+	}
+
+	span.Affected(result)
+
+	if err := conn.Affected(result, errPostNotFound); err != nil {
 		return err
 	}
 
-	return nil
+	if err := syncTags(ctx, tx, id, p.Tags()); err != nil {
+		return err
+	}
+
+	if err := syncMentions(ctx, tx, id, p.Mentions); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-// Delete removes a post by id.
-func (pr *PostRepository) Delete(ctx context.Context, id uint) error {
+// syncTags replaces the hashtags of the post by id with tags.
+func syncTags(ctx context.Context, tx *sql.Tx, id uint, tags []string) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM post_tags WHERE post_id = $1;`, id)
+	if err != nil {
+		return err
+	}
+
+	if len(tags) == 0 {
+		return nil
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO post_tags (post_id, tag) SELECT $1, unnest($2::text[]);`, id, pq.Array(tags))
+
+	return err
+}
+
+// syncMentions replaces the mentions of the post by id with mentions.
+func syncMentions(ctx context.Context, tx *sql.Tx, id uint, mentions []domain.Mention) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM post_mentions WHERE post_id = $1;`, id)
+	if err != nil {
+		return err
+	}
+
+	if len(mentions) == 0 {
+		return nil
+	}
+
+	users := make([]int64, 0, len(mentions))
+	starts := make([]int64, 0, len(mentions))
+	ends := make([]int64, 0, len(mentions))
+	for _, m := range mentions {
+		users = append(users, int64(m.UserID))
+		starts = append(starts, int64(m.Start))
+		ends = append(ends, int64(m.End))
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO post_mentions (post_id, user_id, start_offset, end_offset)
+		SELECT $1, * FROM unnest($2::int[], $3::int[], $4::int[]);`, id, pq.Array(users), pq.Array(starts), pq.Array(ends))
+
+	return conn.Error(err, errPostNotFound)
+}
+
+// Delete removes a post by id. The foreign keys delete its reposts and
+// detach its replies and quotes.
+func (pr *PostRepository) Delete(ctx context.Context, id uint) (err error) {
+	ctx, span := tracing.Query(ctx, "PostRepository.Delete", "deletePost")
+	defer span.End(&err)
+
 	query := `DELETE FROM posts WHERE id=$1;`
 
 	stmt, err := pr.Data.DB.PrepareContext(ctx, query)
@@ -123,10 +510,12 @@ func (pr *PostRepository) Delete(ctx context.Context, id uint) error {
 
 	defer stmt.Close()
 
-	_, err = stmt.ExecContext(ctx, id)
+	result, err := stmt.ExecContext(ctx, id)
 	if err != nil {
-		return err
+		return conn.Error(err, errPostNotFound)
 	}
 
-	return nil
+	span.Affected(result)
+
+	return conn.Affected(result, errPostNotFound)
 }
@@ -1,13 +1,23 @@
 package persistence
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
 	"log"
 	"microblog/domain/post/domain"
+	"microblog/domain/shared/errs"
+	"microblog/domain/shared/page"
 	data "microblog/infrastructure/database"
+	"microblog/infrastructure/tracing"
 	"testing"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 // represent the repository
@@ -44,6 +54,9 @@ func CloseMockPost() {
 	}
 }
 
+// postColumnsTest are the columns selected for every post.
+var postColumnsTest = []string{"id", "body", "user_id", "in_reply_to", "repost_of", "quote_of", "created_at", "updated_at", "reply_count"}
+
 // dataUSer is data for test
 func dataPost() []domain.Post {
 	now := time.Now().Truncate(time.Second).Truncate(time.Millisecond).Truncate(time.Microsecond)
@@ -67,7 +80,72 @@ func dataPost() []domain.Post {
 }
 
 func TestPostRepository_Create(t *testing.T) {
+	const (
+		insertPostTest = "INSERT INTO posts"
+		deleteTagsTest = "DELETE FROM post_tags"
+		insertTagsTest = "INSERT INTO post_tags"
+
+		deleteMentionsTest = "DELETE FROM post_mentions"
+		insertMentionsTest = "INSERT INTO post_mentions"
+	)
+
+	createdAt := time.Date(2020, 9, 4, 10, 30, 0, 0, time.UTC)
+
+	t.Run("Error Tags Rolls Back", func(tt *testing.T) {
+		mock := NewMockPost()
+		defer CloseMockPost()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(insertPostTest).WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(3, createdAt, createdAt))
+		mock.ExpectExec(deleteTagsTest).WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(insertTagsTest).WillReturnError(errors.New("error sql"))
+		mock.ExpectRollback()
+
+		err := postRepositoryMock.Create(context.Background(), &domain.Post{Body: "#go", UserID: 1})
+		assert.Error(tt, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
 
+	t.Run("Create With Tags", func(tt *testing.T) {
+		mock := NewMockPost()
+		defer CloseMockPost()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(insertPostTest).WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(3, createdAt, createdAt))
+		mock.ExpectExec(deleteTagsTest).WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(insertTagsTest).WithArgs(3, "{\"go\",\"gophers\"}").WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(deleteMentionsTest).WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		post := domain.Post{Body: "#Go #gophers #go", UserID: 1, ReplyCount: 2, LikeCount: 5, LikedByMe: true}
+		assert.NoError(tt, postRepositoryMock.Create(context.Background(), &post))
+		assert.Equal(tt, uint(3), post.ID)
+		assert.Equal(tt, createdAt, post.CreatedAt)
+		assert.Equal(tt, createdAt, post.UpdatedAt)
+		assert.Zero(tt, post.ReplyCount)
+		assert.Zero(tt, post.LikeCount)
+		assert.False(tt, post.LikedByMe)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Create With Mentions", func(tt *testing.T) {
+		mock := NewMockPost()
+		defer CloseMockPost()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(insertPostTest).WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(4, createdAt, createdAt))
+		mock.ExpectExec(deleteTagsTest).WithArgs(4).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(deleteMentionsTest).WithArgs(4).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(insertMentionsTest).WithArgs(4, "{7,8}", "{0,5}", "{4,9}").WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		post := domain.Post{Body: "@ana @bob", UserID: 1, Mentions: []domain.Mention{
+			{UserID: 7, Username: "ana", Start: 0, End: 4},
+			{UserID: 8, Username: "bob", Start: 5, End: 9},
+		}}
+		assert.NoError(tt, postRepositoryMock.Create(context.Background(), &post))
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
 }
 
 func TestPostRepository_Delete(t *testing.T) {
@@ -75,17 +153,252 @@ func TestPostRepository_Delete(t *testing.T) {
 }
 
 func TestPostRepository_GetAll(t *testing.T) {
+	const selectAllPostTest = "SELECT p.id, p.body, p.user_id, p.in_reply_to"
+
+	t.Run("Backward Page", func(tt *testing.T) {
+		mock := NewMockPost()
+		defer CloseMockPost()
+
+		postsData := dataPost()
+		before := page.Cursor{CreatedAt: postsData[0].CreatedAt, ID: 3, Backward: true}
+		rows := sqlmock.NewRows(postColumnsTest).
+			AddRow(4, postsData[0].Body, 1, nil, nil, nil, postsData[0].CreatedAt, postsData[0].UpdatedAt, 0).
+			AddRow(5, postsData[1].Body, 1, nil, nil, nil, postsData[1].CreatedAt, postsData[1].UpdatedAt, 0)
 
+		mock.ExpectQuery(selectAllPostTest).WithArgs(before.CreatedAt, before.ID, 3).WillReturnRows(rows)
+
+		posts, info, err := postRepositoryMock.GetAll(context.Background(), page.Request{Limit: 2, Order: page.Desc, Cursor: &before})
+		assert.NoError(tt, err)
+		assert.Len(tt, posts, 2)
+		assert.Equal(tt, uint(5), posts[0].ID)
+		assert.Equal(tt, uint(4), posts[1].ID)
+		assert.NotEmpty(tt, info.NextCursor)
+		assert.Empty(tt, info.PrevCursor)
+	})
+
+	t.Run("Traced", func(tt *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		previous := otel.GetTracerProvider()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+		defer otel.SetTracerProvider(previous)
+
+		mock := NewMockPost()
+		defer CloseMockPost()
+
+		at := dataPost()[0].CreatedAt
+		mock.ExpectQuery(selectAllPostTest).WithArgs(nil, 0, 3).
+			WillReturnRows(sqlmock.NewRows(postColumnsTest).
+				AddRow(5, "", 2, nil, 1, nil, at, at, 0).
+				AddRow(4, "Lorem ipsum", 1, nil, nil, nil, at, at, 0))
+		mock.ExpectQuery("SELECT (.+) WHERE p.id = ANY").
+			WillReturnRows(sqlmock.NewRows(postColumnsTest).AddRow(1, "Lorem ipsum", 1, nil, nil, nil, at, at, 0))
+
+		_, _, err := postRepositoryMock.GetAll(context.Background(), page.Request{Limit: 2, Order: page.Desc})
+		assert.NoError(tt, err)
+
+		spans := recorder.Ended()
+		if assert.Len(tt, spans, 2) {
+			embed, getAll := spans[0], spans[1]
+			assert.Equal(tt, "PostRepository.GetAll", getAll.Name())
+			assert.Equal(tt, "PostRepository.embed", embed.Name())
+			assert.Equal(tt, getAll.SpanContext().SpanID(), embed.Parent().SpanID())
+			assert.Contains(tt, getAll.Attributes(), tracing.StatementKey.String("selectPosts"))
+			assert.Contains(tt, getAll.Attributes(), tracing.RowsKey.Int(2))
+			assert.Contains(tt, embed.Attributes(), tracing.RowsKey.Int(1))
+		}
+	})
 }
 
 func TestPostRepository_GetByUser(t *testing.T) {
+	const (
+		selectByUserTest    = "SELECT (.+) WHERE p.user_id"
+		selectOriginalsTest = "SELECT (.+) WHERE p.id = ANY"
+	)
+
+	t.Run("Reposts Embed The Original", func(tt *testing.T) {
+		mock := NewMockPost()
+		defer CloseMockPost()
+
+		postsData := dataPost()
+		at := postsData[0].CreatedAt
+		mock.ExpectQuery(selectByUserTest).WithArgs(2, nil, 0, 11).
+			WillReturnRows(sqlmock.NewRows(postColumnsTest).
+				AddRow(4, "", 2, nil, 1, nil, at, at, 0).
+				AddRow(3, postsData[0].Body, 2, nil, nil, 9, at, at, 0))
+		mock.ExpectQuery(selectOriginalsTest).
+			WillReturnRows(sqlmock.NewRows(postColumnsTest).AddRow(1, postsData[0].Body, 1, nil, nil, nil, at, at, 0))
+
+		posts, _, err := postRepositoryMock.GetByUser(context.Background(), 2, page.Request{Limit: 10, Order: page.Desc})
+		assert.NoError(tt, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+		if assert.Len(tt, posts, 2) {
+			if assert.NotNil(tt, posts[0].Original) {
+				assert.Equal(tt, uint(1), posts[0].Original.ID)
+			}
+			assert.Nil(tt, posts[1].Original)
+		}
+	})
 
 }
 
 func TestPostRepository_GetOne(t *testing.T) {
+	const (
+		selectOnePostTest  = "SELECT (.+) FROM posts p WHERE p.id"
+		selectMentionsTest = "SELECT (.+) FROM post_mentions m"
+	)
+
+	t.Run("With Mentions", func(tt *testing.T) {
+		mock := NewMockPost()
+		defer CloseMockPost()
+
+		at := dataPost()[0].CreatedAt
+		mock.ExpectQuery(selectOnePostTest).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(postColumnsTest).AddRow(1, "Hi @ana and @nobody", 2, nil, nil, nil, at, at, 0))
+		mock.ExpectQuery(selectMentionsTest).WithArgs("{1}").
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "user_id", "username", "start_offset", "end_offset"}).AddRow(1, 7, "ana", 3, 7))
+
+		post, err := postRepositoryMock.GetOne(context.Background(), 1)
+		assert.NoError(tt, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+		assert.Equal(tt, []domain.Mention{{UserID: 7, Username: "ana", Start: 3, End: 7}}, post.Mentions)
+	})
+}
 
+func TestPostRepository_GetThread(t *testing.T) {
+	const (
+		selectOnePostTest     = "SELECT (.+) FROM posts p WHERE p.id"
+		selectAncestorsTest   = "WITH RECURSIVE ancestors"
+		selectDescendantsTest = "WITH RECURSIVE descendants"
+	)
+
+	t.Run("Error Not Found", func(tt *testing.T) {
+		mock := NewMockPost()
+		defer CloseMockPost()
+
+		mock.ExpectQuery(selectOnePostTest).WithArgs(9).WillReturnError(sql.ErrNoRows)
+
+		_, err := postRepositoryMock.GetThread(context.Background(), 9)
+		assert.True(tt, errors.Is(err, errs.ErrNotFound))
+	})
+
+	t.Run("Ancestors And Replies", func(tt *testing.T) {
+		mock := NewMockPost()
+		defer CloseMockPost()
+
+		postsData := dataPost()
+		at := postsData[0].CreatedAt
+
+		mock.ExpectQuery(selectOnePostTest).WithArgs(2).
+			WillReturnRows(sqlmock.NewRows(postColumnsTest).AddRow(2, postsData[0].Body, 1, 1, nil, nil, at, at, 2))
+		mock.ExpectQuery(selectAncestorsTest).WithArgs(2).
+			WillReturnRows(sqlmock.NewRows(postColumnsTest).AddRow(1, postsData[0].Body, 1, nil, nil, nil, at, at, 1))
+		mock.ExpectQuery(selectDescendantsTest).WithArgs(2).
+			WillReturnRows(sqlmock.NewRows(postColumnsTest).
+				AddRow(5, postsData[0].Body, 2, 3, nil, nil, at, at, 0).
+				AddRow(4, postsData[0].Body, 1, 2, nil, nil, at, at, 0).
+				AddRow(3, postsData[0].Body, 2, 2, nil, nil, at, at, 1))
+
+		thread, err := postRepositoryMock.GetThread(context.Background(), 2)
+		assert.NoError(tt, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+
+		assert.Len(tt, thread.Ancestors, 1)
+		assert.Nil(tt, thread.Ancestors[0].InReplyTo)
+		assert.Equal(tt, uint(2), thread.Post.ID)
+		assert.Equal(tt, 2, thread.Post.ReplyCount)
+		if assert.Len(tt, thread.Replies, 2) {
+			assert.Equal(tt, uint(3), thread.Replies[0].ID)
+			assert.Equal(tt, uint(4), thread.Replies[1].ID)
+			assert.Len(tt, thread.Replies[0].Replies, 1)
+		}
+	})
 }
 
 func TestPostRepository_Update(t *testing.T) {
+	const (
+		updatePostTest = "UPDATE posts"
+		deleteTagsTest = "DELETE FROM post_tags"
+	)
+
+	t.Run("Error Not Found", func(tt *testing.T) {
+		mock := NewMockPost()
+		defer CloseMockPost()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(updatePostTest).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := postRepositoryMock.Update(context.Background(), 9, domain.Post{Body: "#go"})
+		assert.True(tt, errors.Is(err, errs.ErrNotFound), "%v", err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Update Without Tags Clears Them", func(tt *testing.T) {
+		mock := NewMockPost()
+		defer CloseMockPost()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(updatePostTest).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(deleteTagsTest).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("DELETE FROM post_mentions").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(tt, postRepositoryMock.Update(context.Background(), 1, domain.Post{Body: "No tags"}))
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostRepository_GetTimeline(t *testing.T) {
+	const selectTimelineTest = "SELECT p.id, p.body, p.user_id, p.in_reply_to(.+)follows"
+
+	t.Run("Error SQL", func(tt *testing.T) {
+		mock := NewMockPost()
+		defer CloseMockPost()
+
+		mock.ExpectQuery(selectTimelineTest).WillReturnError(errors.New("error sql"))
+
+		posts, info, err := postRepositoryMock.GetTimeline(context.Background(), 1, page.Request{Limit: 2})
+		assert.Error(tt, err)
+		assert.Nil(tt, posts)
+		assert.Empty(tt, info.NextCursor)
+	})
+
+	t.Run("First Page With More Posts", func(tt *testing.T) {
+		mock := NewMockPost()
+		defer CloseMockPost()
+
+		postsData := dataPost()
+		rows := sqlmock.NewRows(postColumnsTest).
+			AddRow(3, postsData[0].Body, 1, nil, nil, nil, postsData[0].CreatedAt, postsData[0].UpdatedAt, 0).
+			AddRow(2, postsData[1].Body, 2, nil, nil, nil, postsData[1].CreatedAt, postsData[1].UpdatedAt, 0).
+			AddRow(1, postsData[1].Body, 2, nil, nil, nil, postsData[1].CreatedAt, postsData[1].UpdatedAt, 0)
+
+		mock.ExpectQuery(selectTimelineTest).WithArgs(1, nil, 0, 3).WillReturnRows(rows)
+
+		posts, info, err := postRepositoryMock.GetTimeline(context.Background(), 1, page.Request{Limit: 2})
+		assert.NoError(tt, err)
+		assert.Len(tt, posts, 2)
+
+		next, err := page.DecodeCursor(info.NextCursor)
+		assert.NoError(tt, err)
+		assert.Equal(tt, uint(2), next.ID)
+	})
+
+	t.Run("Last Page", func(tt *testing.T) {
+		mock := NewMockPost()
+		defer CloseMockPost()
+
+		postsData := dataPost()
+		after := page.Cursor{CreatedAt: postsData[0].CreatedAt, ID: 2}
+		rows := sqlmock.NewRows(postColumnsTest).
+			AddRow(1, postsData[1].Body, 2, nil, nil, nil, postsData[1].CreatedAt, postsData[1].UpdatedAt, 0)
+
+		mock.ExpectQuery(selectTimelineTest).WithArgs(1, after.CreatedAt, after.ID, 3).WillReturnRows(rows)
 
+		posts, info, err := postRepositoryMock.GetTimeline(context.Background(), 1, page.Request{Limit: 2, Cursor: &after})
+		assert.NoError(tt, err)
+		assert.Len(tt, posts, 1)
+		assert.Empty(tt, info.NextCursor)
+		assert.NotEmpty(tt, info.PrevCursor)
+	})
 }
@@ -0,0 +1,178 @@
+package v1
+
+import (
+	"errors"
+	v1like "microblog/domain/like/application/v1"
+	likeDomain "microblog/domain/like/domain"
+	postDomain "microblog/domain/post/domain"
+	"microblog/domain/search/domain"
+	"microblog/domain/shared/page"
+	"microblog/domain/shared/response"
+	userDomain "microblog/domain/user/domain"
+	"microblog/infrastructure/auth"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// errQueryRequired is returned when the request has no q query string
+// parameter.
+var errQueryRequired = errors.New("q is required")
+
+// SearchRouter is the router of the searches. The likes of the posts found
+// are read from Likes when it is set.
+type SearchRouter struct {
+	Repository domain.Repository
+	Likes      likeDomain.Repository
+}
+
+// HitPage is a page of the posts found by a search along with the cursor of
+// the next page.
+type HitPage struct {
+	Items []domain.Hit `json:"items"`
+	page.Info
+}
+
+// UserHitPage is a page of the users found by a search along with the
+// cursor of the next page.
+type UserHitPage struct {
+	Items []domain.UserHit `json:"items"`
+	page.Info
+}
+
+// ProfileList is a list of public user profiles.
+type ProfileList struct {
+	Items []userDomain.Profile `json:"items"`
+}
+
+// userQuery reads the q query string parameter of a user search.
+func userQuery(r *http.Request) (string, error) {
+	q := r.URL.Query().Get("q")
+	if strings.TrimSpace(q) == "" {
+		return "", errQueryRequired
+	}
+
+	return domain.ParseUserQuery(q)
+}
+
+// withLikes sets the like count of the posts found and of the originals
+// they embed, and whether the reader of the request liked them.
+func (sr *SearchRouter) withLikes(r *http.Request, hits []domain.Hit) error {
+	posts := make([]*postDomain.Post, 0, len(hits))
+	for i := range hits {
+		posts = append(posts, &hits[i].Post)
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+
+	return v1like.WithStats(r.Context(), sr.Likes, principal.UserID, posts...)
+}
+
+// SearchPostsHandler response a page of the posts having all the terms of
+// the q query string parameter, most relevant first, with a snippet of
+// their body.
+func (sr *SearchRouter) SearchPostsHandler(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	if strings.TrimSpace(values.Get("q")) == "" {
+		response.HTTPError(w, r, http.StatusBadRequest, errQueryRequired.Error())
+		return
+	}
+
+	q, err := domain.ParseQuery(values.Get("q"))
+	if err != nil {
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	p, err := domain.FromQuery(values)
+	if err != nil {
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	hits, info, err := sr.Repository.SearchPosts(ctx, q, p)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	err = sr.withLikes(r, hits)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	if hits == nil {
+		hits = []domain.Hit{}
+	}
+
+	response.JSON(w, r, http.StatusOK, HitPage{Items: hits, Info: info})
+}
+
+// SearchUsersHandler response a page of the public profiles of the users
+// whose username or name look like the q query string parameter, most
+// similar first.
+func (sr *SearchRouter) SearchUsersHandler(w http.ResponseWriter, r *http.Request) {
+	q, err := userQuery(r)
+	if err != nil {
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	p, err := domain.FromQuery(r.URL.Query())
+	if err != nil {
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	hits, info, err := sr.Repository.SearchUsers(ctx, q, p)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	if hits == nil {
+		hits = []domain.UserHit{}
+	}
+
+	response.JSON(w, r, http.StatusOK, UserHitPage{Items: hits, Info: info})
+}
+
+// AutocompleteHandler response the public profiles of the users whose
+// username starts with the q query string parameter, shortest first, up to
+// the limit one.
+func (sr *SearchRouter) AutocompleteHandler(w http.ResponseWriter, r *http.Request) {
+	prefix, err := userQuery(r)
+	if err != nil {
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limit := domain.DefaultSuggestions
+	if s := r.URL.Query().Get("limit"); s != "" {
+		limit, err = strconv.Atoi(s)
+		if err != nil || limit <= 0 {
+			response.HTTPError(w, r, http.StatusBadRequest, page.ErrInvalidLimit.Error())
+			return
+		}
+	}
+
+	if limit > domain.MaxSuggestions {
+		limit = domain.MaxSuggestions
+	}
+
+	ctx := r.Context()
+	profiles, err := sr.Repository.CompleteUsername(ctx, prefix, limit)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	if profiles == nil {
+		profiles = []userDomain.Profile{}
+	}
+
+	response.JSON(w, r, http.StatusOK, ProfileList{Items: profiles})
+}
@@ -0,0 +1,179 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	likeDomain "microblog/domain/like/domain"
+	mockLike "microblog/domain/like/domain/mocks"
+	postDomain "microblog/domain/post/domain"
+	"microblog/domain/search/domain"
+	mockLocal "microblog/domain/search/domain/mocks"
+	"microblog/domain/shared/page"
+	userDomain "microblog/domain/user/domain"
+	"microblog/infrastructure/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newRequest returns a request authenticated as principalID unless it is
+// zero.
+func newRequest(target string, principalID uint) *http.Request {
+	request := httptest.NewRequest(http.MethodGet, target, nil)
+	if principalID == 0 {
+		return request
+	}
+
+	return request.WithContext(auth.NewContext(context.Background(), auth.Principal{UserID: principalID}))
+}
+
+func TestSearchRouter_SearchPostsHandler(t *testing.T) {
+
+	t.Run("Error Missing Query Search Posts Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testSearchHandler := &SearchRouter{Repository: mockRepository}
+
+		testSearchHandler.SearchPostsHandler(response, newRequest("/api/v1/search/posts?q=+", 0))
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Error Query Search Posts Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testSearchHandler := &SearchRouter{Repository: mockRepository}
+
+		testSearchHandler.SearchPostsHandler(response, newRequest("/api/v1/search/posts?q=%26%26", 0))
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Error Page Search Posts Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testSearchHandler := &SearchRouter{Repository: mockRepository}
+
+		testSearchHandler.SearchPostsHandler(response, newRequest("/api/v1/search/posts?q=go&cursor=abc", 0))
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Error SQL Search Posts Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testSearchHandler := &SearchRouter{Repository: mockRepository}
+		mockRepository.On("SearchPosts", mock.Anything, mock.Anything, mock.Anything).Return(nil, page.Info{}, errors.New("error sql")).Once()
+
+		testSearchHandler.SearchPostsHandler(response, newRequest("/api/v1/search/posts?q=go", 0))
+		assert.Equal(tt, http.StatusInternalServerError, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Search Posts Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+		mockLikes := &mockLike.Repository{}
+
+		testSearchHandler := &SearchRouter{Repository: mockRepository, Likes: mockLikes}
+		q := domain.Query{Terms: []domain.Term{{Words: []string{"go"}}}}
+		hits := []domain.Hit{{Post: postDomain.Post{ID: 7, Body: "go"}, Snippet: "<mark>go</mark>"}}
+		mockRepository.On("SearchPosts", mock.Anything, q, domain.Request{Limit: 5}).Return(hits, page.Info{NextCursor: "next"}, nil).Once()
+		mockLikes.On("Stats", mock.Anything, uint(1), []uint{7}).Return(map[uint]likeDomain.Stats{7: {Count: 2, LikedByMe: true}}, nil).Once()
+
+		testSearchHandler.SearchPostsHandler(response, newRequest("/api/v1/search/posts?q=Go&limit=5", 1))
+		assert.Equal(tt, http.StatusOK, response.Code)
+
+		var got struct {
+			Items []struct {
+				ID        uint   `json:"id"`
+				Snippet   string `json:"snippet"`
+				LikeCount int    `json:"like_count"`
+			} `json:"items"`
+			NextCursor string `json:"next_cursor"`
+		}
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&got))
+		if assert.Len(tt, got.Items, 1) {
+			assert.Equal(tt, "<mark>go</mark>", got.Items[0].Snippet)
+			assert.Equal(tt, 2, got.Items[0].LikeCount)
+		}
+		assert.Equal(tt, "next", got.NextCursor)
+		mockRepository.AssertExpectations(tt)
+		mockLikes.AssertExpectations(tt)
+	})
+}
+
+func TestSearchRouter_SearchUsersHandler(t *testing.T) {
+
+	t.Run("Error Missing Query Search Users Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testSearchHandler := &SearchRouter{Repository: mockRepository}
+
+		testSearchHandler.SearchUsersHandler(response, newRequest("/api/v1/users/search", 0))
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Search Users Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testSearchHandler := &SearchRouter{Repository: mockRepository}
+		hits := []domain.UserHit{{Profile: userDomain.Profile{ID: 2, Username: "rebecca.romero"}, Rank: 0.8}}
+		mockRepository.On("SearchUsers", mock.Anything, "rebeca", domain.Request{Limit: page.DefaultLimit}).Return(hits, page.Info{}, nil).Once()
+
+		testSearchHandler.SearchUsersHandler(response, newRequest("/api/v1/users/search?q=+Rebeca", 0))
+		assert.Equal(tt, http.StatusOK, response.Code)
+		assert.JSONEq(tt, `{"items":[{"id":2,"username":"rebecca.romero"}]}`, response.Body.String())
+		mockRepository.AssertExpectations(tt)
+	})
+}
+
+func TestSearchRouter_AutocompleteHandler(t *testing.T) {
+
+	t.Run("Error Limit Autocomplete Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testSearchHandler := &SearchRouter{Repository: mockRepository}
+
+		testSearchHandler.AutocompleteHandler(response, newRequest("/api/v1/users/autocomplete?q=dan&limit=0", 0))
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Error SQL Autocomplete Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testSearchHandler := &SearchRouter{Repository: mockRepository}
+		mockRepository.On("CompleteUsername", mock.Anything, "dan", domain.DefaultSuggestions).Return(nil, errors.New("error sql")).Once()
+
+		testSearchHandler.AutocompleteHandler(response, newRequest("/api/v1/users/autocomplete?q=dan", 0))
+		assert.Equal(tt, http.StatusInternalServerError, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Autocomplete Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testSearchHandler := &SearchRouter{Repository: mockRepository}
+		mockRepository.On("CompleteUsername", mock.Anything, "dan", domain.MaxSuggestions).Return(nil, nil).Once()
+
+		testSearchHandler.AutocompleteHandler(response, newRequest("/api/v1/users/autocomplete?q=%40Dan&limit=500", 0))
+		assert.Equal(tt, http.StatusOK, response.Code)
+		assert.JSONEq(tt, `{"items":[]}`, response.Body.String())
+		mockRepository.AssertExpectations(tt)
+	})
+}
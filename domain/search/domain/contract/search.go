@@ -0,0 +1,224 @@
+// Package contract holds the conformance suite every adapter of the search
+// port must pass, whatever its storage.
+package contract
+
+import (
+	"context"
+	"microblog/domain/search/domain"
+	"microblog/domain/shared/page"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Harness is a search repository under test along with a way to create the
+// users who post, the users searched and the posts searched.
+type Harness struct {
+	Repository domain.Repository
+	NewUser    func(t *testing.T) uint
+	NewProfile func(t *testing.T, username, firstName, lastName string) uint
+	NewPost    func(t *testing.T, userID uint, body string) uint
+}
+
+// search runs the query or stops the test.
+func search(t *testing.T, repository domain.Repository, s string, r domain.Request) ([]domain.Hit, page.Info) {
+	t.Helper()
+
+	q, err := domain.ParseQuery(s)
+	if err != nil {
+		t.Fatalf("error parsing %q: %v", s, err)
+	}
+
+	hits, info, err := repository.SearchPosts(context.Background(), q, r)
+	if err != nil {
+		t.Fatalf("error searching %q: %v", s, err)
+	}
+
+	return hits, info
+}
+
+// ids returns the post ids of the hits in order.
+func ids(hits []domain.Hit) []uint {
+	result := make([]uint, 0, len(hits))
+	for _, h := range hits {
+		result = append(result, h.ID)
+	}
+
+	return result
+}
+
+// userIDs returns the user ids of the hits in order.
+func userIDs(hits []domain.UserHit) []uint {
+	result := make([]uint, 0, len(hits))
+	for _, h := range hits {
+		result = append(result, h.ID)
+	}
+
+	return result
+}
+
+// TestRepository runs the domain.Repository suite. newHarness must return
+// a repository without users nor posts each time it is called.
+func TestRepository(t *testing.T, newHarness func(t *testing.T) Harness) {
+	ctx := context.Background()
+	first := domain.Request{Limit: 10}
+
+	t.Run("Matches Every Term Case Insensitively", func(tt *testing.T) {
+		h := newHarness(tt)
+		userID := h.NewUser(tt)
+		both := h.NewPost(tt, userID, "Hello, Gopher world")
+		h.NewPost(tt, userID, "hello there")
+		h.NewPost(tt, userID, "GOPHER only")
+
+		hits, info := search(tt, h.Repository, "HELLO gopher", first)
+		assert.Equal(tt, []uint{both}, ids(hits))
+		assert.Equal(tt, page.Info{}, info)
+		if assert.Len(tt, hits, 1) {
+			assert.Equal(tt, "<mark>Hello</mark>, <mark>Gopher</mark> world", hits[0].Snippet)
+			assert.Equal(tt, userID, hits[0].UserID)
+		}
+	})
+
+	t.Run("Phrase", func(tt *testing.T) {
+		h := newHarness(tt)
+		userID := h.NewUser(tt)
+		ordered := h.NewPost(tt, userID, "the quick brown fox")
+		h.NewPost(tt, userID, "brown and quick")
+
+		hits, _ := search(tt, h.Repository, `"quick brown"`, first)
+		assert.Equal(tt, []uint{ordered}, ids(hits))
+	})
+
+	t.Run("Prefix", func(tt *testing.T) {
+		h := newHarness(tt)
+		userID := h.NewUser(tt)
+		gophers := h.NewPost(tt, userID, "gophers unite")
+		h.NewPost(tt, userID, "a goal")
+
+		hits, _ := search(tt, h.Repository, "goph*", first)
+		assert.Equal(tt, []uint{gophers}, ids(hits))
+
+		hits, _ = search(tt, h.Repository, "goph", first)
+		assert.Empty(tt, hits)
+	})
+
+	t.Run("Most Relevant First", func(tt *testing.T) {
+		h := newHarness(tt)
+		userID := h.NewUser(tt)
+		once := h.NewPost(tt, userID, "learning go today")
+		thrice := h.NewPost(tt, userID, "go go go")
+
+		hits, _ := search(tt, h.Repository, "go", first)
+		assert.Equal(tt, []uint{thrice, once}, ids(hits))
+	})
+
+	t.Run("Paginated", func(tt *testing.T) {
+		h := newHarness(tt)
+		userID := h.NewUser(tt)
+
+		var posts []uint
+		for i := 0; i < 3; i++ {
+			posts = append(posts, h.NewPost(tt, userID, "lorem ipsum"))
+		}
+		h.NewPost(tt, userID, "dolor sit amet")
+
+		hits, info := search(tt, h.Repository, "lorem", domain.Request{Limit: 2})
+		assert.Equal(tt, []uint{posts[2], posts[1]}, ids(hits))
+		assert.Empty(tt, info.PrevCursor)
+
+		next, err := domain.DecodeCursor(info.NextCursor)
+		if !assert.NoError(tt, err) {
+			return
+		}
+
+		hits, info = search(tt, h.Repository, "lorem", domain.Request{Limit: 2, Cursor: &next})
+		assert.Equal(tt, []uint{posts[0]}, ids(hits))
+		assert.Equal(tt, page.Info{}, info)
+	})
+
+	t.Run("No Match", func(tt *testing.T) {
+		h := newHarness(tt)
+		h.NewPost(tt, h.NewUser(tt), "lorem ipsum")
+
+		hits, info := search(tt, h.Repository, "nothing", first)
+		assert.Empty(tt, hits)
+		assert.Equal(tt, page.Info{}, info)
+	})
+
+	t.Run("Search Users By Name Or Username", func(tt *testing.T) {
+		h := newHarness(tt)
+		daniel := h.NewProfile(tt, "daniel.delapava", "Daniel", "De La Pava")
+		rebecca := h.NewProfile(tt, "rebecca.romero", "Rebecca", "Romero")
+		h.NewProfile(tt, "ana.gomez", "Ana", "Gomez")
+
+		hits, info, err := h.Repository.SearchUsers(ctx, "danel", first)
+		assert.NoError(tt, err)
+		assert.Equal(tt, []uint{daniel}, userIDs(hits))
+		assert.Equal(tt, page.Info{}, info)
+		if assert.Len(tt, hits, 1) {
+			assert.Equal(tt, "daniel.delapava", hits[0].Username)
+			assert.Equal(tt, "De La Pava", hits[0].LastName)
+		}
+
+		hits, _, err = h.Repository.SearchUsers(ctx, "rebeca romero", first)
+		assert.NoError(tt, err)
+		assert.Equal(tt, []uint{rebecca}, userIDs(hits))
+
+		hits, _, err = h.Repository.SearchUsers(ctx, "zzz", first)
+		assert.NoError(tt, err)
+		assert.Empty(tt, hits)
+	})
+
+	t.Run("Search Users Most Similar First And Paginated", func(tt *testing.T) {
+		h := newHarness(tt)
+		exact := h.NewProfile(tt, "romero", "Rebecca", "Romero")
+		near := h.NewProfile(tt, "romera", "Ana", "Romera")
+		h.NewProfile(tt, "gomez", "Luis", "Gomez")
+
+		hits, info, err := h.Repository.SearchUsers(ctx, "romero", domain.Request{Limit: 1})
+		assert.NoError(tt, err)
+		assert.Equal(tt, []uint{exact}, userIDs(hits))
+
+		next, err := domain.DecodeCursor(info.NextCursor)
+		if !assert.NoError(tt, err) {
+			return
+		}
+
+		hits, info, err = h.Repository.SearchUsers(ctx, "romero", domain.Request{Limit: 1, Cursor: &next})
+		assert.NoError(tt, err)
+		assert.Equal(tt, []uint{near}, userIDs(hits))
+		assert.Equal(tt, page.Info{}, info)
+	})
+
+	t.Run("Complete Username", func(tt *testing.T) {
+		h := newHarness(tt)
+		dan := h.NewProfile(tt, "Dan", "Dan", "Brown")
+		daniel := h.NewProfile(tt, "daniel.delapava", "Daniel", "De La Pava")
+		danny := h.NewProfile(tt, "danny_b", "Danny", "Boyle")
+		h.NewProfile(tt, "aidan", "Aidan", "Gillen")
+		h.NewProfile(tt, "dan1x", "Dan", "Other")
+
+		profiles, err := h.Repository.CompleteUsername(ctx, "dan", 3)
+		assert.NoError(tt, err)
+		if assert.Len(tt, profiles, 3) {
+			assert.Equal(tt, dan, profiles[0].ID)
+			assert.Equal(tt, "Dan", profiles[0].Username)
+		}
+
+		profiles, err = h.Repository.CompleteUsername(ctx, "DANIEL.", 10)
+		assert.NoError(tt, err)
+		if assert.Len(tt, profiles, 1) {
+			assert.Equal(tt, daniel, profiles[0].ID)
+		}
+
+		profiles, err = h.Repository.CompleteUsername(ctx, "danny_", 10)
+		assert.NoError(tt, err)
+		if assert.Len(tt, profiles, 1) {
+			assert.Equal(tt, danny, profiles[0].ID)
+		}
+
+		profiles, err = h.Repository.CompleteUsername(ctx, "dan%", 10)
+		assert.NoError(tt, err)
+		assert.Empty(tt, profiles)
+	})
+}
@@ -0,0 +1,180 @@
+package domain
+
+import (
+	"microblog/domain/shared/errs"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// MaxQueryLength is the length in runes of the longest query searched.
+	MaxQueryLength = 256
+
+	// MaxTerms is the largest number of terms a query can have.
+	MaxTerms = 16
+)
+
+var (
+	// ErrEmptyQuery is returned when a query has no word to search.
+	ErrEmptyQuery = errs.Validation("q must have a word to search")
+
+	// ErrQueryTooLong is returned when a query is longer than MaxQueryLength.
+	ErrQueryTooLong = errs.Validation("q is too long")
+
+	// ErrTooManyTerms is returned when a query has more than MaxTerms terms.
+	ErrTooManyTerms = errs.Validation("q has too many terms")
+)
+
+// Term is a word a post must have or, with more words, a phrase whose words
+// it must have in order. With Prefix the last word matches the words that
+// start with it.
+type Term struct {
+	Words  []string
+	Prefix bool
+}
+
+// Query is what a search looks for: the posts having all of its terms.
+type Query struct {
+	Terms []Term
+}
+
+// ParseQuery reads a query typed by a user. Words are matched case
+// insensitively, "double quotes" make a phrase and a trailing * a prefix,
+// as in
+//
+//	"hello world" gopher*
+func ParseQuery(s string) (Query, error) {
+	if utf8.RuneCountInString(s) > MaxQueryLength {
+		return Query{}, ErrQueryTooLong
+	}
+
+	var q Query
+	for i, part := range strings.Split(s, `"`) {
+		// The odd parts are quoted, so each of them is a phrase.
+		chunks := []string{part}
+		if i%2 == 0 {
+			chunks = strings.Fields(part)
+		}
+
+		for _, chunk := range chunks {
+			words := Words(chunk)
+			if len(words) == 0 {
+				continue
+			}
+
+			q.Terms = append(q.Terms, Term{Words: words, Prefix: strings.HasSuffix(strings.TrimSpace(chunk), "*")})
+		}
+	}
+
+	if len(q.Terms) == 0 {
+		return Query{}, ErrEmptyQuery
+	}
+
+	if len(q.Terms) > MaxTerms {
+		return Query{}, ErrTooManyTerms
+	}
+
+	return q, nil
+}
+
+// Words returns the lower cased words of a text: its runs of letters,
+// digits and marks.
+func Words(s string) []string {
+	var words []string
+	for _, t := range tokens(s) {
+		words = append(words, t.word)
+	}
+
+	return words
+}
+
+// token is a word of a text along with its byte offsets, end exclusive.
+type token struct {
+	word       string
+	start, end int
+}
+
+// tokens splits a text into its words.
+func tokens(s string) []token {
+	var result []token
+	start := -1
+	for i, r := range s {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+		switch {
+		case inWord && start < 0:
+			start = i
+		case !inWord && start >= 0:
+			result = append(result, token{word: strings.ToLower(s[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+
+	if start >= 0 {
+		result = append(result, token{word: strings.ToLower(s[start:]), start: start, end: len(s)})
+	}
+
+	return result
+}
+
+// at reports whether the term matches the tokens from index i.
+func (t Term) at(tokens []token, i int) bool {
+	if i+len(t.Words) > len(tokens) {
+		return false
+	}
+
+	last := len(t.Words) - 1
+	for k, word := range t.Words {
+		got := tokens[i+k].word
+		if k == last && t.Prefix {
+			if !strings.HasPrefix(got, word) {
+				return false
+			}
+		} else if got != word {
+			return false
+		}
+	}
+
+	return true
+}
+
+// spans returns where the terms of the query occur among the tokens, as
+// pairs of token indexes, end exclusive.
+func (q Query) spans(tokens []token) [][2]int {
+	var spans [][2]int
+	for i := range tokens {
+		for _, t := range q.Terms {
+			if t.at(tokens, i) {
+				spans = append(spans, [2]int{i, i + len(t.Words)})
+			}
+		}
+	}
+
+	return spans
+}
+
+// Match reports whether the text has every term of the query.
+func (q Query) Match(text string) bool {
+	ts := tokens(text)
+	for _, t := range q.Terms {
+		found := false
+		for i := range ts {
+			if t.at(ts, i) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Rank returns how relevant the text is to the query: the number of times
+// its terms occur. Adapters with their own ranking need not use it.
+func (q Query) Rank(text string) float64 {
+	return float64(len(q.spans(tokens(text))))
+}
@@ -0,0 +1,95 @@
+package domain_test
+
+import (
+	"errors"
+	"microblog/domain/search/domain"
+	"microblog/domain/shared/errs"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Query string
+		Terms []domain.Term
+		Err   error
+	}{
+		{Name: "Words", Query: "Hello  WORLD", Terms: []domain.Term{{Words: []string{"hello"}}, {Words: []string{"world"}}}},
+		{Name: "Phrase", Query: `"quick brown" fox`, Terms: []domain.Term{{Words: []string{"quick", "brown"}}, {Words: []string{"fox"}}}},
+		{Name: "Unterminated Phrase", Query: `fox "quick brown`, Terms: []domain.Term{{Words: []string{"fox"}}, {Words: []string{"quick", "brown"}}}},
+		{Name: "Prefix", Query: "goph* go", Terms: []domain.Term{{Words: []string{"goph"}, Prefix: true}, {Words: []string{"go"}}}},
+		{Name: "Prefix Phrase", Query: `"hello wor*"`, Terms: []domain.Term{{Words: []string{"hello", "wor"}, Prefix: true}}},
+		{Name: "Punctuation Joins Words", Query: "e-mail #golang", Terms: []domain.Term{{Words: []string{"e", "mail"}}, {Words: []string{"golang"}}}},
+		{Name: "Operators Are Not Words", Query: "a & !b | 'c' :*", Terms: []domain.Term{{Words: []string{"a"}}, {Words: []string{"b"}}, {Words: []string{"c"}}}},
+		{Name: "Unicode", Query: "Canción ÑANDÚ", Terms: []domain.Term{{Words: []string{"canción"}}, {Words: []string{"ñandú"}}}},
+		{Name: "Empty", Query: ` "" * !`, Err: domain.ErrEmptyQuery},
+		{Name: "Too Long", Query: strings.Repeat("a", domain.MaxQueryLength+1), Err: domain.ErrQueryTooLong},
+		{Name: "Too Many Terms", Query: strings.Repeat("a ", domain.MaxTerms+1), Err: domain.ErrTooManyTerms},
+	}
+
+	for _, test := range tests {
+		q, err := domain.ParseQuery(test.Query)
+		if test.Err != nil {
+			assert.Equal(t, test.Err, err, test.Name)
+			assert.True(t, errors.Is(err, errs.ErrValidation), test.Name)
+			continue
+		}
+
+		assert.NoError(t, err, test.Name)
+		assert.Equal(t, test.Terms, q.Terms, test.Name)
+	}
+}
+
+func TestQuery_Match(t *testing.T) {
+	tests := []struct {
+		Query string
+		Text  string
+		Match bool
+	}{
+		{Query: "hello world", Text: "World, hello!", Match: true},
+		{Query: "hello world", Text: "hello there", Match: false},
+		{Query: `"hello world"`, Text: "Hello, world", Match: true},
+		{Query: `"hello world"`, Text: "world hello", Match: false},
+		{Query: "wor*", Text: "Hello world", Match: true},
+		{Query: "wor", Text: "Hello world", Match: false},
+		{Query: `"hello wor*"`, Text: "hello worldwide", Match: true},
+		{Query: "golang", Text: "I love #golang", Match: true},
+	}
+
+	for _, test := range tests {
+		q, err := domain.ParseQuery(test.Query)
+		assert.NoError(t, err, test.Query)
+		assert.Equal(t, test.Match, q.Match(test.Text), "%s in %s", test.Query, test.Text)
+	}
+}
+
+func TestQuery_Rank(t *testing.T) {
+	q, err := domain.ParseQuery("go")
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3.0, q.Rank("go, Go GO"))
+	assert.Equal(t, 1.0, q.Rank("let's go"))
+	assert.Zero(t, q.Rank("gopher"))
+}
+
+func TestQuery_Snippet(t *testing.T) {
+	q, err := domain.ParseQuery(`"quick brown" <b>`)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "The <mark>quick brown</mark> fox &lt;<mark>b</mark>&gt;", q.Snippet("The quick brown fox <b>"))
+	assert.Equal(t, "nothing &amp; more", q.Snippet("nothing & more"))
+
+	q, err = domain.ParseQuery("needle")
+	assert.NoError(t, err)
+
+	words := strings.Fields(strings.Repeat("hay ", 50))
+	words[20] = "Needle"
+	snippet := q.Snippet(strings.Join(words, " "))
+	assert.True(t, strings.HasPrefix(snippet, "…hay"), snippet)
+	assert.True(t, strings.HasSuffix(snippet, "hay…"), snippet)
+	assert.Contains(t, snippet, strings.Repeat("hay ", 10)+"<mark>Needle</mark>")
+	assert.Len(t, strings.Fields(snippet), domain.SnippetWords)
+}
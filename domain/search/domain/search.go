@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"context"
+	"encoding/base64"
+	postDomain "microblog/domain/post/domain"
+	"microblog/domain/shared/page"
+	userDomain "microblog/domain/user/domain"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Hit is a post found by a search along with its rank and the fragment of
+// its body that matches, highlighted by Query.Snippet.
+type Hit struct {
+	postDomain.Post
+	Rank    float64 `json:"-"`
+	Snippet string  `json:"snippet"`
+}
+
+// Cursor returns the position of the hit in a list ordered by rank.
+func (h Hit) Cursor() Cursor {
+	return Cursor{Rank: h.Rank, ID: h.ID}
+}
+
+// Cursor is a position in a list of hits ordered by rank, highest first.
+// The post id, highest first too, breaks ties between hits of equal rank.
+type Cursor struct {
+	Rank float64
+	ID   uint
+}
+
+// Encode returns the opaque representation of the cursor handed to clients.
+func (c Cursor) Encode() string {
+	raw := strconv.FormatFloat(c.Rank, 'g', -1, 64) + ":" + strconv.FormatUint(uint64(c.ID), 10)
+
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor returned by Encode.
+func DecodeCursor(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, page.ErrInvalidCursor
+	}
+
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 2 {
+		return Cursor{}, page.ErrInvalidCursor
+	}
+
+	rank, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return Cursor{}, page.ErrInvalidCursor
+	}
+
+	id, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return Cursor{}, page.ErrInvalidCursor
+	}
+
+	return Cursor{Rank: rank, ID: uint(id)}, nil
+}
+
+// Before reports whether the cursor is placed before other in a list of
+// hits.
+func (c Cursor) Before(other Cursor) bool {
+	if c.Rank != other.Rank {
+		return c.Rank > other.Rank
+	}
+
+	return c.ID > other.ID
+}
+
+// Request asks for a page of hits. Ranked lists are only walked forward,
+// so there is no previous page.
+type Request struct {
+	Limit  int
+	Cursor *Cursor
+}
+
+// FromQuery returns the Request described by the limit and cursor query
+// string parameters, with the limits of page.NewRequest.
+func FromQuery(values url.Values) (Request, error) {
+	limit := 0
+	if s := values.Get("limit"); s != "" {
+		l, err := strconv.Atoi(s)
+		if err != nil {
+			return Request{}, page.ErrInvalidLimit
+		}
+
+		limit = l
+	}
+
+	switch {
+	case limit < 0:
+		return Request{}, page.ErrInvalidLimit
+	case limit == 0:
+		limit = page.DefaultLimit
+	case limit > page.MaxLimit:
+		limit = page.MaxLimit
+	}
+
+	r := Request{Limit: limit}
+	if s := values.Get("cursor"); s != "" {
+		c, err := DecodeCursor(s)
+		if err != nil {
+			return Request{}, err
+		}
+
+		r.Cursor = &c
+	}
+
+	return r, nil
+}
+
+// Fetch is the number of hits an adapter must read to fill the page and
+// know whether another page follows.
+func (r Request) Fetch() int {
+	return r.Limit + 1
+}
+
+// Trim cuts a list of n results read with Fetch, in rank order, down to
+// the page size. It returns the number of results to keep and the Info of
+// the page; at returns the cursor of the result at index i.
+func (r Request) Trim(n int, at func(i int) Cursor) (int, page.Info) {
+	if n <= r.Limit {
+		return n, page.Info{}
+	}
+
+	return r.Limit, page.Info{NextCursor: at(r.Limit - 1).Encode()}
+}
+
+// Repository is the search port. The posts search matches the query against
+// the words of the post bodies and returns the posts having all of its
+// terms, most relevant first, each with its snippet. The posts carry their
+// mentions and the post they refer to like the ones postDomain.Repository
+// reads. The users found are public profiles only.
+type Repository interface {
+	SearchPosts(ctx context.Context, q Query, r Request) ([]Hit, page.Info, error)
+	// SearchUsers returns the users with a UserRank for the query of
+	// SimilarityThreshold at least, most similar first.
+	SearchUsers(ctx context.Context, q string, r Request) ([]UserHit, page.Info, error)
+	// CompleteUsername returns up to limit users whose username starts with
+	// the prefix, case insensitively, shortest username first.
+	CompleteUsername(ctx context.Context, prefix string, limit int) ([]userDomain.Profile, error)
+}
@@ -0,0 +1,73 @@
+package domain_test
+
+import (
+	postDomain "microblog/domain/post/domain"
+	"microblog/domain/search/domain"
+	"microblog/domain/shared/page"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor_Encode(t *testing.T) {
+	c := domain.Cursor{Rank: 0.0607927106320858, ID: 42}
+
+	got, err := domain.DecodeCursor(c.Encode())
+	assert.NoError(t, err)
+	assert.Equal(t, c, got)
+
+	for _, invalid := range []string{"%%%", "MTI", "YTo0Mg", "MC41OmI"} {
+		_, err = domain.DecodeCursor(invalid)
+		assert.Equal(t, page.ErrInvalidCursor, err, invalid)
+	}
+}
+
+func TestCursor_Before(t *testing.T) {
+	assert.True(t, domain.Cursor{Rank: 2, ID: 1}.Before(domain.Cursor{Rank: 1, ID: 9}))
+	assert.True(t, domain.Cursor{Rank: 1, ID: 9}.Before(domain.Cursor{Rank: 1, ID: 1}))
+	assert.False(t, domain.Cursor{Rank: 1, ID: 1}.Before(domain.Cursor{Rank: 1, ID: 1}))
+}
+
+func TestFromQuery(t *testing.T) {
+	r, err := domain.FromQuery(url.Values{})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Request{Limit: page.DefaultLimit}, r)
+
+	r, err = domain.FromQuery(url.Values{"limit": {"1000"}})
+	assert.NoError(t, err)
+	assert.Equal(t, page.MaxLimit, r.Limit)
+
+	c := domain.Cursor{Rank: 0.5, ID: 3}
+	r, err = domain.FromQuery(url.Values{"cursor": {c.Encode()}})
+	assert.NoError(t, err)
+	assert.Equal(t, &c, r.Cursor)
+
+	_, err = domain.FromQuery(url.Values{"limit": {"-1"}})
+	assert.Equal(t, page.ErrInvalidLimit, err)
+
+	_, err = domain.FromQuery(url.Values{"limit": {"abc"}})
+	assert.Equal(t, page.ErrInvalidLimit, err)
+
+	_, err = domain.FromQuery(url.Values{"cursor": {"abc"}})
+	assert.Equal(t, page.ErrInvalidCursor, err)
+}
+
+func TestRequest_Trim(t *testing.T) {
+	hits := []domain.Hit{
+		{Post: postDomain.Post{ID: 3}, Rank: 0.9},
+		{Post: postDomain.Post{ID: 2}, Rank: 0.5},
+		{Post: postDomain.Post{ID: 1}, Rank: 0.1},
+	}
+
+	at := func(i int) domain.Cursor { return hits[i].Cursor() }
+
+	keep, info := domain.Request{Limit: 2}.Trim(len(hits), at)
+	assert.Equal(t, 2, keep)
+	assert.Equal(t, domain.Cursor{Rank: 0.5, ID: 2}.Encode(), info.NextCursor)
+	assert.Empty(t, info.PrevCursor)
+
+	keep, info = domain.Request{Limit: 3}.Trim(len(hits), at)
+	assert.Equal(t, 3, keep)
+	assert.Equal(t, page.Info{}, info)
+}
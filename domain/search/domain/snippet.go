@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"html"
+	"strings"
+)
+
+const (
+	// SnippetWords is the number of words of a snippet.
+	SnippetWords = 30
+
+	// snippetLead is the number of words a snippet shows before the first
+	// match.
+	snippetLead = 10
+
+	// ellipsis marks the text a snippet leaves out.
+	ellipsis = "…"
+)
+
+// Snippet returns the fragment of the text around the first match of the
+// query, at most SnippetWords words, HTML escaped and with the occurrences
+// of its terms wrapped in <mark> elements.
+func (q Query) Snippet(text string) string {
+	ts := tokens(text)
+	spans := q.spans(ts)
+	if len(ts) == 0 {
+		return html.EscapeString(text)
+	}
+
+	from := 0
+	if len(spans) > 0 && spans[0][0] > snippetLead {
+		from = spans[0][0] - snippetLead
+	}
+
+	to := from + SnippetWords
+	if to > len(ts) {
+		to = len(ts)
+	}
+
+	var b strings.Builder
+	start := 0
+	if from > 0 {
+		b.WriteString(ellipsis)
+		start = ts[from].start
+	}
+
+	end := len(text)
+	if to < len(ts) {
+		end = ts[to-1].end
+	}
+
+	pos := start
+	for _, span := range merge(spans) {
+		if span[1] <= from || span[0] >= to {
+			continue
+		}
+
+		first, last := max(span[0], from), min(span[1], to)-1
+		b.WriteString(html.EscapeString(text[pos:ts[first].start]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[ts[first].start:ts[last].end]))
+		b.WriteString("</mark>")
+		pos = ts[last].end
+	}
+
+	b.WriteString(html.EscapeString(text[pos:end]))
+	if to < len(ts) {
+		b.WriteString(ellipsis)
+	}
+
+	return b.String()
+}
+
+// merge joins the overlapping spans, sorted by start, so that each word is
+// marked once. Adjacent spans are kept apart.
+func merge(spans [][2]int) [][2]int {
+	var merged [][2]int
+	for _, span := range spans {
+		n := len(merged)
+		if n > 0 && span[0] < merged[n-1][1] {
+			merged[n-1][1] = max(merged[n-1][1], span[1])
+			continue
+		}
+
+		merged = append(merged, span)
+	}
+
+	return merged
+}
+
+// max returns the larger of a and b.
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
+// min returns the smaller of a and b.
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
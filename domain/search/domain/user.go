@@ -0,0 +1,110 @@
+package domain
+
+import (
+	userDomain "microblog/domain/user/domain"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// SimilarityThreshold is the lowest Similarity of the users found by a
+	// user search, the default similarity_threshold of pg_trgm.
+	SimilarityThreshold = 0.3
+
+	// DefaultSuggestions is the number of usernames an autocomplete returns
+	// when the request does not set one.
+	DefaultSuggestions = 10
+
+	// MaxSuggestions is the largest number of usernames an autocomplete can
+	// return.
+	MaxSuggestions = 20
+)
+
+// UserHit is a user found by a search along with its rank.
+type UserHit struct {
+	userDomain.Profile
+	Rank float64 `json:"-"`
+}
+
+// Cursor returns the position of the hit in a list ordered by rank.
+func (h UserHit) Cursor() Cursor {
+	return Cursor{Rank: h.Rank, ID: h.ID}
+}
+
+// ParseUserQuery reads the name or username typed by a user looking for
+// another, with or without its leading @. It returns it trimmed and lower
+// cased.
+func ParseUserQuery(s string) (string, error) {
+	if utf8.RuneCountInString(s) > MaxQueryLength {
+		return "", ErrQueryTooLong
+	}
+
+	q := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(s), "@"))
+	if len(Words(q)) == 0 {
+		return "", ErrEmptyQuery
+	}
+
+	return q, nil
+}
+
+// UserFields are the texts a user search compares with the query: the
+// username, the first and last names and the full name of the user.
+func UserFields(p userDomain.Profile) []string {
+	return []string{p.Username, p.FirstName, p.LastName, p.FirstName + " " + p.LastName}
+}
+
+// UserRank returns the greatest Similarity between the query and the
+// UserFields of the user.
+func UserRank(q string, p userDomain.Profile) float64 {
+	best := 0.0
+	for _, field := range UserFields(p) {
+		if similarity := Similarity(q, field); similarity > best {
+			best = similarity
+		}
+	}
+
+	return best
+}
+
+// trigrams returns the trigrams of the words of s in order, the way pg_trgm
+// extracts them: each word lower cased, with two spaces before and one
+// after.
+func trigrams(s string) []string {
+	var result []string
+	for _, word := range Words(s) {
+		runes := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			result = append(result, string(runes[i:i+3]))
+		}
+	}
+
+	return result
+}
+
+// Similarity returns how alike a and b are, from 0 to 1, following the
+// similarity of pg_trgm: the share of their trigrams they have in common.
+func Similarity(a, b string) float64 {
+	set := func(s string) map[string]bool {
+		result := make(map[string]bool)
+		for _, t := range trigrams(s) {
+			result[t] = true
+		}
+
+		return result
+	}
+
+	x, y := set(a), set(b)
+	shared := 0
+	for t := range x {
+		if y[t] {
+			shared++
+		}
+	}
+
+	union := len(x) + len(y) - shared
+	if union == 0 {
+		return 0
+	}
+
+	return float64(shared) / float64(union)
+}
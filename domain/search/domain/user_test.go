@@ -0,0 +1,41 @@
+package domain_test
+
+import (
+	"errors"
+	"microblog/domain/search/domain"
+	"microblog/domain/shared/errs"
+	userDomain "microblog/domain/user/domain"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUserQuery(t *testing.T) {
+	q, err := domain.ParseUserQuery("  @Daniel.De ")
+	assert.NoError(t, err)
+	assert.Equal(t, "daniel.de", q)
+
+	_, err = domain.ParseUserQuery(" @ ")
+	assert.True(t, errors.Is(err, errs.ErrValidation), "%v", err)
+
+	_, err = domain.ParseUserQuery(strings.Repeat("a", domain.MaxQueryLength+1))
+	assert.Equal(t, domain.ErrQueryTooLong, err)
+}
+
+func TestSimilarity(t *testing.T) {
+	// Values returned by the similarity function of pg_trgm.
+	assert.Equal(t, 1.0, domain.Similarity("Word", "word"))
+	assert.InDelta(t, 0.4444, domain.Similarity("danel", "daniel"), 0.0001)
+	assert.InDelta(t, 0.5555, domain.Similarity("romero", "romera"), 0.0001)
+	assert.Zero(t, domain.Similarity("abc", "xyz"))
+	assert.Zero(t, domain.Similarity("", ""))
+}
+
+func TestUserRank(t *testing.T) {
+	p := userDomain.Profile{Username: "rebecca.romero", FirstName: "Rebecca", LastName: "Romero"}
+
+	assert.Equal(t, 1.0, domain.UserRank("romero", p))
+	assert.InDelta(t, 0.8, domain.UserRank("rebeca romero", p), 0.0001)
+	assert.Less(t, domain.UserRank("gomez", p), domain.SimilarityThreshold)
+}
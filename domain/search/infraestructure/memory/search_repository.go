@@ -0,0 +1,151 @@
+package memory
+
+import (
+	"context"
+	postDomain "microblog/domain/post/domain"
+	"microblog/domain/search/domain"
+	"microblog/domain/shared/page"
+	userDomain "microblog/domain/user/domain"
+	"sort"
+	"strings"
+)
+
+// SearchRepository searches the users of Users and the posts of Posts by
+// scanning all of them, so it suits tests and small data sets only. Posts
+// are ranked by Query.Rank and users by UserRank.
+type SearchRepository struct {
+	Users userDomain.Repository
+	Posts postDomain.Repository
+}
+
+// NewSearchRepository returns a SearchRepository over the users and posts.
+func NewSearchRepository(users userDomain.Repository, posts postDomain.Repository) *SearchRepository {
+	return &SearchRepository{Users: users, Posts: posts}
+}
+
+// walk calls read with the requests of every page of a list, one after the
+// other, until read returns an Info without next page.
+func walk(read func(p page.Request) (page.Info, error)) error {
+	p := page.Request{Limit: page.MaxLimit, Order: page.Desc}
+	for {
+		info, err := read(p)
+		if err != nil || info.NextCursor == "" {
+			return err
+		}
+
+		next, err := page.DecodeCursor(info.NextCursor)
+		if err != nil {
+			return err
+		}
+
+		p.Cursor = &next
+	}
+}
+
+// from returns the index of the first of the n results, sorted in rank
+// order, placed after the cursor of the request, and the index where its
+// page read with Fetch ends.
+func from(r domain.Request, n int, at func(i int) domain.Cursor) (int, int) {
+	start := sort.Search(n, func(i int) bool {
+		return r.Cursor == nil || r.Cursor.Before(at(i))
+	})
+
+	end := start + r.Fetch()
+	if end > n {
+		end = n
+	}
+
+	return start, end
+}
+
+// SearchPosts returns a page of the posts having all the terms of the query.
+func (sr *SearchRepository) SearchPosts(ctx context.Context, q domain.Query, r domain.Request) ([]domain.Hit, page.Info, error) {
+	var hits []domain.Hit
+	err := walk(func(p page.Request) (page.Info, error) {
+		posts, info, err := sr.Posts.GetAll(ctx, p)
+		for _, post := range posts {
+			if q.Match(post.Body) {
+				hits = append(hits, domain.Hit{Post: post, Rank: q.Rank(post.Body), Snippet: q.Snippet(post.Body)})
+			}
+		}
+
+		return info, err
+	})
+	if err != nil {
+		return nil, page.Info{}, err
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		return hits[i].Cursor().Before(hits[j].Cursor())
+	})
+
+	start, end := from(r, len(hits), func(i int) domain.Cursor { return hits[i].Cursor() })
+	hits = hits[start:end]
+	keep, info := r.Trim(len(hits), func(i int) domain.Cursor { return hits[i].Cursor() })
+
+	return hits[:keep], info, nil
+}
+
+// SearchUsers returns a page of the users that look like the query.
+func (sr *SearchRepository) SearchUsers(ctx context.Context, q string, r domain.Request) ([]domain.UserHit, page.Info, error) {
+	var hits []domain.UserHit
+	err := walk(func(p page.Request) (page.Info, error) {
+		users, info, err := sr.Users.GetAllUser(ctx, p)
+		for _, u := range users {
+			profile := u.Profile()
+			if rank := domain.UserRank(q, profile); rank >= domain.SimilarityThreshold {
+				hits = append(hits, domain.UserHit{Profile: profile, Rank: rank})
+			}
+		}
+
+		return info, err
+	})
+	if err != nil {
+		return nil, page.Info{}, err
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		return hits[i].Cursor().Before(hits[j].Cursor())
+	})
+
+	start, end := from(r, len(hits), func(i int) domain.Cursor { return hits[i].Cursor() })
+	hits = hits[start:end]
+	keep, info := r.Trim(len(hits), func(i int) domain.Cursor { return hits[i].Cursor() })
+
+	return hits[:keep], info, nil
+}
+
+// CompleteUsername returns the users whose username starts with the prefix.
+func (sr *SearchRepository) CompleteUsername(ctx context.Context, prefix string, limit int) ([]userDomain.Profile, error) {
+	prefix = strings.ToLower(prefix)
+
+	var profiles []userDomain.Profile
+	err := walk(func(p page.Request) (page.Info, error) {
+		users, info, err := sr.Users.GetAllUser(ctx, p)
+		for _, u := range users {
+			if strings.HasPrefix(strings.ToLower(u.Username), prefix) {
+				profiles = append(profiles, u.Profile())
+			}
+		}
+
+		return info, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(profiles, func(i, j int) bool {
+		a, b := profiles[i].Username, profiles[j].Username
+		if len(a) != len(b) {
+			return len(a) < len(b)
+		}
+
+		return a < b
+	})
+
+	if len(profiles) > limit {
+		profiles = profiles[:limit]
+	}
+
+	return profiles, nil
+}
@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	memoryFollow "microblog/domain/follow/infraestructure/memory"
+	postDomain "microblog/domain/post/domain"
+	memoryPost "microblog/domain/post/infraestructure/memory"
+	"microblog/domain/search/domain/contract"
+	userContract "microblog/domain/user/domain/contract"
+	memoryUser "microblog/domain/user/infraestructure/memory"
+	"testing"
+)
+
+func TestSearchRepository_Contract(t *testing.T) {
+	contract.TestRepository(t, func(t *testing.T) contract.Harness {
+		users := memoryUser.NewUserRepository()
+		posts := memoryPost.NewPostRepository(memoryFollow.NewFollowRepository(users))
+		n := 0
+
+		newProfile := func(t *testing.T, username, firstName, lastName string) uint {
+			u := userContract.NewUser(username)
+			u.FirstName, u.LastName = firstName, lastName
+			if err := users.Create(context.Background(), &u); err != nil {
+				t.Fatalf("error creating user: %v", err)
+			}
+
+			return u.ID
+		}
+
+		return contract.Harness{
+			Repository: NewSearchRepository(users, posts),
+			NewUser: func(t *testing.T) uint {
+				n++
+				return newProfile(t, fmt.Sprintf("user.%d", n), "Test", "User")
+			},
+			NewProfile: newProfile,
+			NewPost: func(t *testing.T, userID uint, body string) uint {
+				p := postDomain.Post{Body: body, UserID: userID}
+				if err := posts.Create(context.Background(), &p); err != nil {
+					t.Fatalf("error creating post: %v", err)
+				}
+
+				return p.ID
+			},
+		}
+	})
+}
@@ -0,0 +1,27 @@
+package persistence
+
+const (
+
+	// selectHits is a query that selects a page of the posts matching the text search query $1, most
+	// relevant first, after the rank $2 and post id $3 of the cursor when they are set. It must be
+	// formatted with the post columns. The rank is read as double precision so that it can be sent
+	// back as is in the cursor.
+	selectHits = "SELECT %s, h.rank FROM (" +
+		"SELECT s.id, ts_rank(s.search, q)::float8 AS rank FROM posts s, to_tsquery('simple', $1) q WHERE s.search @@ q" +
+		") h INNER JOIN posts p ON p.id = h.id WHERE ($2::float8 IS NULL OR (h.rank, h.id) < ($2::float8, $3)) " +
+		"ORDER BY h.rank DESC, h.id DESC LIMIT $4;"
+
+	// selectUserHits is a query that selects a page of the public profiles of the users whose username,
+	// first, last or full name is similar to $1, most similar first, after the rank $2 and user id $3
+	// of the cursor when they are set.
+	selectUserHits = "SELECT h.id, h.first_name, h.last_name, h.username, h.picture, h.rank FROM (" +
+		"SELECT id, first_name, last_name, username, picture, greatest(similarity(username, $1), similarity(first_name, $1), " +
+		"similarity(last_name, $1), similarity(first_name || ' ' || last_name, $1))::float8 AS rank FROM users " +
+		"WHERE username % $1 OR first_name % $1 OR last_name % $1 OR (first_name || ' ' || last_name) % $1" +
+		") h WHERE ($2::float8 IS NULL OR (h.rank, h.id) < ($2::float8, $3)) ORDER BY h.rank DESC, h.id DESC LIMIT $4;"
+
+	// selectUsernamePrefix is a query that selects the public profiles of the users whose lower cased
+	// username is LIKE $1, shortest username first, up to $2 of them.
+	selectUsernamePrefix = "SELECT id, first_name, last_name, username, picture FROM users WHERE lower(username) LIKE $1 " +
+		"ORDER BY char_length(username), username COLLATE \"C\" LIMIT $2;"
+)
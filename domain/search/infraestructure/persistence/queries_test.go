@@ -0,0 +1,18 @@
+package persistence
+
+const (
+
+	// selectHitsTest is the end of the query that selects a page of the posts matching a search.
+	selectHitsTest = "h.rank FROM \\(SELECT s.id, ts_rank\\(s.search, q\\)::float8 AS rank FROM posts s, to_tsquery\\('simple', \\$1\\) q"
+)
+
+const (
+
+	// selectUserHitsTest is the beginning of the query that selects a page of the users matching a search.
+	selectUserHitsTest = "SELECT h.id, h.first_name, h.last_name, h.username, h.picture, h.rank FROM \\(SELECT id"
+
+	// selectUsernamePrefixTest is a query that selects the users by username prefix.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	selectUsernamePrefixTest = "SELECT id, first_name, last_name, username, picture FROM users WHERE lower\\(username\\) LIKE \\$1"
+)
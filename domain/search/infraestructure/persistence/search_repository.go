@@ -0,0 +1,153 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	postDomain "microblog/domain/post/domain"
+	persistencePost "microblog/domain/post/infraestructure/persistence"
+	"microblog/domain/search/domain"
+	"microblog/domain/shared/page"
+	userDomain "microblog/domain/user/domain"
+	"strings"
+
+	conn "microblog/infrastructure/database"
+)
+
+// SearchRepository searches the posts with the full text search of the
+// database, over the tsvector of their bodies, and the users with the
+// trigram similarity of pg_trgm.
+type SearchRepository struct {
+	Data *conn.Data
+}
+
+// tsquery returns the text search query matching the posts that have all
+// the terms of q. The words are quoted, so they are never read as operators.
+func tsquery(q domain.Query) string {
+	terms := make([]string, 0, len(q.Terms))
+	for _, t := range q.Terms {
+		words := make([]string, 0, len(t.Words))
+		for _, w := range t.Words {
+			words = append(words, "'"+strings.ReplaceAll(w, "'", "''")+"'")
+		}
+
+		if t.Prefix {
+			words[len(words)-1] += ":*"
+		}
+
+		terms = append(terms, strings.Join(words, " <-> "))
+	}
+
+	return strings.Join(terms, " & ")
+}
+
+// SearchPosts returns a page of the posts having all the terms of the query,
+// ranked by ts_rank.
+func (sr *SearchRepository) SearchPosts(ctx context.Context, q domain.Query, r domain.Request) ([]domain.Hit, page.Info, error) {
+	var rank interface{}
+	var afterID uint
+	if r.Cursor != nil {
+		rank, afterID = r.Cursor.Rank, r.Cursor.ID
+	}
+
+	query := fmt.Sprintf(selectHits, persistencePost.Columns)
+	rows, err := sr.Data.DB.QueryContext(ctx, query, tsquery(q), rank, afterID, r.Fetch())
+	if err != nil {
+		return nil, page.Info{}, err
+	}
+
+	defer rows.Close()
+
+	var posts []postDomain.Post
+	var ranks []float64
+	for rows.Next() {
+		var rank float64
+		post, err := persistencePost.ScanPost(rows, &rank)
+		if err != nil {
+			return nil, page.Info{}, err
+		}
+
+		posts = append(posts, post)
+		ranks = append(ranks, rank)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, page.Info{}, err
+	}
+
+	keep, info := r.Trim(len(posts), func(i int) domain.Cursor { return domain.Cursor{Rank: ranks[i], ID: posts[i].ID} })
+	posts = posts[:keep]
+
+	if err := (&persistencePost.PostRepository{Data: sr.Data}).Complete(ctx, posts); err != nil {
+		return nil, page.Info{}, err
+	}
+
+	hits := make([]domain.Hit, 0, keep)
+	for i, post := range posts {
+		hits = append(hits, domain.Hit{Post: post, Rank: ranks[i], Snippet: q.Snippet(post.Body)})
+	}
+
+	return hits, info, nil
+}
+
+// SearchUsers returns a page of the users that look like the query.
+func (sr *SearchRepository) SearchUsers(ctx context.Context, q string, r domain.Request) ([]domain.UserHit, page.Info, error) {
+	var rank interface{}
+	var afterID uint
+	if r.Cursor != nil {
+		rank, afterID = r.Cursor.Rank, r.Cursor.ID
+	}
+
+	rows, err := sr.Data.DB.QueryContext(ctx, selectUserHits, q, rank, afterID, r.Fetch())
+	if err != nil {
+		return nil, page.Info{}, err
+	}
+
+	defer rows.Close()
+
+	var hits []domain.UserHit
+	for rows.Next() {
+		var h domain.UserHit
+		err := rows.Scan(&h.ID, &h.FirstName, &h.LastName, &h.Username, &h.Picture, &h.Rank)
+		if err != nil {
+			return nil, page.Info{}, err
+		}
+
+		hits = append(hits, h)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, page.Info{}, err
+	}
+
+	keep, info := r.Trim(len(hits), func(i int) domain.Cursor { return hits[i].Cursor() })
+
+	return hits[:keep], info, nil
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// CompleteUsername returns the users whose username starts with the prefix.
+func (sr *SearchRepository) CompleteUsername(ctx context.Context, prefix string, limit int) ([]userDomain.Profile, error) {
+	pattern := likeEscaper.Replace(strings.ToLower(prefix)) + "%"
+
+	rows, err := sr.Data.DB.QueryContext(ctx, selectUsernamePrefix, pattern, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var profiles []userDomain.Profile
+	for rows.Next() {
+		var p userDomain.Profile
+		err := rows.Scan(&p.ID, &p.FirstName, &p.LastName, &p.Username, &p.Picture)
+		if err != nil {
+			return nil, err
+		}
+
+		profiles = append(profiles, p)
+	}
+
+	return profiles, rows.Err()
+}
@@ -0,0 +1,181 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	postDomain "microblog/domain/post/domain"
+	"microblog/domain/search/domain"
+	"microblog/domain/shared/page"
+	data "microblog/infrastructure/database"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// represent the repository
+var (
+	dbMockSearch         *sql.DB
+	searchRepositoryMock *SearchRepository
+)
+
+// NewMockSearch initialize mock connection to database
+func NewMockSearch() sqlmock.Sqlmock {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	dbMockSearch = db
+	searchRepositoryMock = &SearchRepository{
+		Data: &data.Data{DB: dbMockSearch},
+	}
+
+	return mock
+}
+
+// CloseMockSearch attaches the provider and close the connection
+func CloseMockSearch() {
+	err := dbMockSearch.Close()
+	if err != nil {
+		log.Println("Error close database test")
+	}
+}
+
+// hitColumnsTest are the columns selected for every hit.
+var hitColumnsTest = []string{"id", "body", "user_id", "in_reply_to", "repost_of", "quote_of", "created_at", "updated_at", "reply_count", "rank"}
+
+func TestTsquery(t *testing.T) {
+	q, err := domain.ParseQuery(`"quick brown" goph* fox`)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "'quick' <-> 'brown' & 'goph':* & 'fox'", tsquery(q))
+}
+
+func TestSearchRepository_SearchPosts(t *testing.T) {
+	q, err := domain.ParseQuery("lorem")
+	assert.NoError(t, err)
+
+	t.Run("Error SQL", func(tt *testing.T) {
+		mock := NewMockSearch()
+		defer CloseMockSearch()
+
+		mock.ExpectQuery(selectHitsTest).WillReturnError(errors.New("error sql"))
+
+		_, _, err := searchRepositoryMock.SearchPosts(context.Background(), q, domain.Request{Limit: 2})
+		assert.Error(tt, err)
+	})
+
+	t.Run("Search Successful", func(tt *testing.T) {
+		mock := NewMockSearch()
+		defer CloseMockSearch()
+
+		now := time.Now()
+		rows := sqlmock.NewRows(hitColumnsTest).
+			AddRow(3, "Lorem lorem", 1, nil, nil, nil, now, now, 0, 0.2).
+			AddRow(5, "Lorem ipsum", 2, nil, nil, nil, now, now, 1, 0.1).
+			AddRow(4, "ipsum lorem", 2, nil, nil, nil, now, now, 0, 0.1)
+
+		mock.ExpectQuery(selectHitsTest).WithArgs("'lorem'", 0.5, 7, 3).WillReturnRows(rows)
+
+		hits, info, err := searchRepositoryMock.SearchPosts(context.Background(), q, domain.Request{Limit: 2, Cursor: &domain.Cursor{Rank: 0.5, ID: 7}})
+		assert.NoError(tt, err)
+		if assert.Len(tt, hits, 2) {
+			assert.Equal(tt, uint(3), hits[0].ID)
+			assert.Equal(tt, "<mark>Lorem</mark> <mark>lorem</mark>", hits[0].Snippet)
+			assert.Equal(tt, 1, hits[1].ReplyCount)
+		}
+
+		next, err := domain.DecodeCursor(info.NextCursor)
+		assert.NoError(tt, err)
+		assert.Equal(tt, domain.Cursor{Rank: 0.1, ID: 5}, next)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Look-Ahead Row Not Completed", func(tt *testing.T) {
+		mock := NewMockSearch()
+		defer CloseMockSearch()
+
+		now := time.Now()
+		rows := sqlmock.NewRows(hitColumnsTest).
+			AddRow(3, "Lorem @ana", 1, nil, nil, nil, now, now, 0, 0.2).
+			AddRow(4, "Lorem @bob", 2, nil, nil, 3, now, now, 0, 0.1)
+
+		mock.ExpectQuery(selectHitsTest).WithArgs("'lorem'", nil, 0, 2).WillReturnRows(rows)
+		mock.ExpectQuery("SELECT m.post_id").WithArgs("{3}").
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "user_id", "username", "start_offset", "end_offset"}).AddRow(3, 7, "ana", 6, 10))
+
+		hits, info, err := searchRepositoryMock.SearchPosts(context.Background(), q, domain.Request{Limit: 1})
+		assert.NoError(tt, err)
+		if assert.Len(tt, hits, 1) {
+			assert.Equal(tt, []postDomain.Mention{{UserID: 7, Username: "ana", Start: 6, End: 10}}, hits[0].Mentions)
+		}
+		assert.NotEmpty(tt, info.NextCursor)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("First Page", func(tt *testing.T) {
+		mock := NewMockSearch()
+		defer CloseMockSearch()
+
+		mock.ExpectQuery(selectHitsTest).WithArgs("'lorem'", nil, 0, 21).WillReturnRows(sqlmock.NewRows(hitColumnsTest))
+
+		hits, info, err := searchRepositoryMock.SearchPosts(context.Background(), q, domain.Request{Limit: page.DefaultLimit})
+		assert.NoError(tt, err)
+		assert.Empty(tt, hits)
+		assert.Equal(tt, page.Info{}, info)
+	})
+}
+
+func TestSearchRepository_SearchUsers(t *testing.T) {
+	mock := NewMockSearch()
+	defer CloseMockSearch()
+
+	rows := sqlmock.NewRows([]string{"id", "first_name", "last_name", "username", "picture", "rank"}).
+		AddRow(2, "Rebecca", "Romero", "romero", "", 1.0).
+		AddRow(5, "Ana", "Romera", "romera", "", 0.5555)
+
+	mock.ExpectQuery(selectUserHitsTest).WithArgs("romero", nil, 0, 2).WillReturnRows(rows)
+
+	hits, info, err := searchRepositoryMock.SearchUsers(context.Background(), "romero", domain.Request{Limit: 1})
+	assert.NoError(t, err)
+	if assert.Len(t, hits, 1) {
+		assert.Equal(t, "romero", hits[0].Username)
+	}
+
+	next, err := domain.DecodeCursor(info.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Cursor{Rank: 1, ID: 2}, next)
+}
+
+func TestSearchRepository_CompleteUsername(t *testing.T) {
+
+	t.Run("Error SQL", func(tt *testing.T) {
+		mock := NewMockSearch()
+		defer CloseMockSearch()
+
+		mock.ExpectQuery(selectUsernamePrefixTest).WillReturnError(errors.New("error sql"))
+
+		_, err := searchRepositoryMock.CompleteUsername(context.Background(), "dan", 10)
+		assert.Error(tt, err)
+	})
+
+	t.Run("Wildcards Escaped", func(tt *testing.T) {
+		mock := NewMockSearch()
+		defer CloseMockSearch()
+
+		rows := sqlmock.NewRows([]string{"id", "first_name", "last_name", "username", "picture"}).
+			AddRow(3, "Danny", "Boyle", "danny_b", "")
+
+		mock.ExpectQuery(selectUsernamePrefixTest).WithArgs(`danny\_\%%`, 5).WillReturnRows(rows)
+
+		profiles, err := searchRepositoryMock.CompleteUsername(context.Background(), "Danny_%", 5)
+		assert.NoError(tt, err)
+		if assert.Len(tt, profiles, 1) {
+			assert.Equal(tt, "danny_b", profiles[0].Username)
+		}
+	})
+}
@@ -0,0 +1,109 @@
+// Package errs defines the kinds of error the domain reports, so adapters
+// can describe a failure without knowing how it reaches the client.
+package errs
+
+import (
+	"errors"
+	"strings"
+)
+
+// Kinds of domain error. Match them with errors.Is.
+var (
+	// ErrNotFound means the resource does not exist.
+	ErrNotFound = errors.New("not found")
+
+	// ErrConflict means the change clashes with the stored state, such as a
+	// duplicated unique value.
+	ErrConflict = errors.New("conflict")
+
+	// ErrValidation means the input breaks a business rule.
+	ErrValidation = errors.New("validation failed")
+
+	// ErrForbidden means the actor is not allowed to perform the action.
+	ErrForbidden = errors.New("forbidden")
+)
+
+// Error is a domain error of a kind with a message safe to show to clients.
+// Validation errors may list the input fields at fault.
+type Error struct {
+	Kind    error
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+// FieldError is the reason why the value of an input field is invalid.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error returns the message of the error.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is reports whether the error is of the target kind.
+func (e *Error) Is(target error) bool {
+	return e.Kind == target
+}
+
+// Unwrap returns the underlying error, if any.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New returns an error of the kind with the message.
+func New(kind error, message string) error {
+	return &Error{Kind: kind, Message: message}
+}
+
+// Wrap returns an error of the kind with the message caused by err.
+func Wrap(kind error, message string, err error) error {
+	return &Error{Kind: kind, Message: message, Err: err}
+}
+
+// NotFound returns an ErrNotFound error with the message.
+func NotFound(message string) error {
+	return New(ErrNotFound, message)
+}
+
+// Conflict returns an ErrConflict error with the message.
+func Conflict(message string) error {
+	return New(ErrConflict, message)
+}
+
+// Validation returns an ErrValidation error with the message.
+func Validation(message string) error {
+	return New(ErrValidation, message)
+}
+
+// Forbidden returns an ErrForbidden error with the message.
+func Forbidden(message string) error {
+	return New(ErrForbidden, message)
+}
+
+// Invalid returns an ErrValidation error listing the invalid fields, or nil
+// when there are none. Its message joins the reasons of every field.
+func Invalid(fields ...FieldError) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	reasons := make([]string, 0, len(fields))
+	for _, f := range fields {
+		reasons = append(reasons, f.Message)
+	}
+
+	return &Error{Kind: ErrValidation, Message: strings.Join(reasons, "; "), Fields: fields}
+}
+
+// Fields returns the invalid fields reported by err, if any.
+func Fields(err error) []FieldError {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Fields
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package errs
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError_Is(t *testing.T) {
+	err := Wrap(ErrNotFound, "user not found", sql.ErrNoRows)
+
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.True(t, errors.Is(err, sql.ErrNoRows))
+	assert.False(t, errors.Is(err, ErrConflict))
+	assert.Equal(t, "user not found", err.Error())
+
+	wrapped := fmt.Errorf("get user: %w", Conflict("username already exists"))
+	assert.True(t, errors.Is(wrapped, ErrConflict))
+}
+
+func TestInvalid(t *testing.T) {
+	assert.NoError(t, Invalid())
+
+	err := Invalid(
+		FieldError{Field: "username", Message: "required username"},
+		FieldError{Field: "email", Message: "invalid email"},
+	)
+
+	assert.True(t, errors.Is(err, ErrValidation))
+	assert.Equal(t, "required username; invalid email", err.Error())
+	assert.Len(t, Fields(fmt.Errorf("create user: %w", err)), 2)
+	assert.Empty(t, Fields(NotFound("user not found")))
+}
@@ -0,0 +1,58 @@
+// Package event carries the domain events from the flows that produce them
+// to the subscribers that react to them, in the same process.
+package event
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// Event is something that happened in a domain that others may react to.
+type Event interface {
+	// Name identifies the kind of event, such as post.created.
+	Name() string
+}
+
+// Handler reacts to an event.
+type Handler func(ctx context.Context, e Event) error
+
+// Publisher publishes the events of a domain.
+type Publisher interface {
+	Publish(ctx context.Context, e Event)
+}
+
+// Bus is a Publisher that hands every event to the subscribed handlers. It
+// is safe for concurrent use.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+// NewBus returns a Bus without subscribers.
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe adds a handler for every event published from now on.
+func (b *Bus) Subscribe(h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, h)
+}
+
+// Publish runs the handlers one after the other before returning. The
+// errors of the handlers are logged: the flow that produced the event
+// already happened and is not undone.
+func (b *Bus) Publish(ctx context.Context, e Event) {
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		if err := h(ctx, e); err != nil {
+			log.Printf("handling %s: %v", e.Name(), err)
+		}
+	}
+}
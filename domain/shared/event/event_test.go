@@ -0,0 +1,34 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// named is an event for test.
+type named string
+
+func (n named) Name() string {
+	return string(n)
+}
+
+func TestBus_Publish(t *testing.T) {
+	bus := NewBus()
+	bus.Publish(context.Background(), named("nobody.listens"))
+
+	var got []string
+	bus.Subscribe(func(ctx context.Context, e Event) error {
+		got = append(got, "first "+e.Name())
+		return errors.New("error handler")
+	})
+	bus.Subscribe(func(ctx context.Context, e Event) error {
+		got = append(got, "second "+e.Name())
+		return nil
+	})
+
+	bus.Publish(context.Background(), named("post.created"))
+	assert.Equal(t, []string{"first post.created", "second post.created"}, got)
+}
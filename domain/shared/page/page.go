@@ -0,0 +1,285 @@
+package page
+
+import (
+	"encoding/base64"
+	"fmt"
+	"microblog/domain/shared/errs"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultLimit is the page size used when the request does not set one.
+	DefaultLimit = 20
+
+	// MaxLimit is the largest page size a request can ask for.
+	MaxLimit = 100
+)
+
+var (
+	// ErrInvalidCursor is returned when a cursor can not be decoded.
+	ErrInvalidCursor = errs.Validation("invalid cursor")
+
+	// ErrInvalidLimit is returned when the limit is not a positive number.
+	ErrInvalidLimit = errs.Validation("invalid limit")
+
+	// ErrInvalidOrder is returned when the sort direction is unknown.
+	ErrInvalidOrder = errs.Validation("invalid order, must be asc or desc")
+)
+
+// Order is the sort direction of a list by creation time.
+type Order string
+
+const (
+	// Desc lists the newest rows first. It is the default order.
+	Desc Order = "desc"
+
+	// Asc lists the oldest rows first.
+	Asc Order = "asc"
+)
+
+// Cursor is a position in a list ordered by creation time and id. The id
+// breaks ties between rows created at the same instant. A Backward cursor
+// asks for the rows placed before the position instead of after it.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uint
+	Backward  bool
+}
+
+// Encode returns the opaque representation of the cursor handed to clients.
+func (c Cursor) Encode() string {
+	raw := fmt.Sprintf("%d:%d", c.CreatedAt.UnixNano(), c.ID)
+	if c.Backward {
+		raw += ":prev"
+	}
+
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor returned by Encode.
+func DecodeCursor(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	parts := strings.Split(string(raw), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	id, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	backward := len(parts) == 3
+	if backward && parts[2] != "prev" {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	// Timestamps are stored without time zone, so the cursor keeps the wall
+	// clock of the row in UTC.
+	return Cursor{CreatedAt: time.Unix(0, nanos).UTC(), ID: uint(id), Backward: backward}, nil
+}
+
+// Request asks for a page of a list ordered by creation time.
+type Request struct {
+	Limit  int
+	Order  Order
+	Cursor *Cursor
+}
+
+// NewRequest returns a Request for the given limit, sort direction and
+// encoded cursor. A zero limit falls back to DefaultLimit, limits above
+// MaxLimit are capped and an empty order falls back to Desc.
+func NewRequest(limit int, order string, cursor string) (Request, error) {
+	if limit < 0 {
+		return Request{}, ErrInvalidLimit
+	}
+
+	if limit == 0 {
+		limit = DefaultLimit
+	}
+
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	r := Request{Limit: limit, Order: Desc}
+	switch Order(strings.ToLower(order)) {
+	case "", Desc:
+	case Asc:
+		r.Order = Asc
+	default:
+		return Request{}, ErrInvalidOrder
+	}
+
+	if cursor == "" {
+		return r, nil
+	}
+
+	c, err := DecodeCursor(cursor)
+	if err != nil {
+		return Request{}, err
+	}
+
+	r.Cursor = &c
+
+	return r, nil
+}
+
+// FromQuery returns the Request described by the limit, order and cursor
+// query string parameters.
+func FromQuery(values url.Values) (Request, error) {
+	limit := 0
+	if s := values.Get("limit"); s != "" {
+		l, err := strconv.Atoi(s)
+		if err != nil {
+			return Request{}, ErrInvalidLimit
+		}
+
+		limit = l
+	}
+
+	return NewRequest(limit, values.Get("order"), values.Get("cursor"))
+}
+
+// Backward reports whether the request walks the list towards its start.
+func (r Request) Backward() bool {
+	return r.Cursor != nil && r.Cursor.Backward
+}
+
+// Fetch is the number of rows an adapter must read to fill the page and
+// know whether another page follows.
+func (r Request) Fetch() int {
+	return r.Limit + 1
+}
+
+// Seek returns the comparison operator to apply to (created_at, id) against
+// the cursor and the direction of the ORDER BY, both as SQL keywords. Rows
+// must be read in this order, which is the reverse of the list order when
+// walking backward.
+func (r Request) Seek() (string, string) {
+	ascending := r.Order == Asc
+	if r.Backward() {
+		ascending = !ascending
+	}
+
+	if ascending {
+		return ">", "ASC"
+	}
+
+	return "<", "DESC"
+}
+
+// Position returns the creation time and id of the cursor, or nil and zero
+// for the first page.
+func (r Request) Position() (interface{}, uint) {
+	if r.Cursor == nil {
+		return nil, 0
+	}
+
+	return r.Cursor.CreatedAt, r.Cursor.ID
+}
+
+// Trim cuts a list of n rows read with Fetch down to the page size. It
+// returns the number of rows to keep and whether more rows follow in the
+// reading direction.
+func (r Request) Trim(n int) (int, bool) {
+	if n > r.Limit {
+		return r.Limit, true
+	}
+
+	return n, false
+}
+
+// Slice pages through n rows already sorted in list order, see Cursor.Before,
+// as adapters that hold their rows in memory do. at returns the cursor of the row at index i.
+// It returns the bounds of the page, from inclusive and to exclusive, and
+// its Info.
+func (r Request) Slice(n int, at func(i int) Cursor) (int, int, Info) {
+	var from, to int
+	var more bool
+	if r.Backward() {
+		// rows placed before the cursor lead the list.
+		to = sort.Search(n, func(i int) bool { return !at(i).Before(*r.Cursor, r.Order) })
+		from = to - r.Limit
+		if from < 0 {
+			from = 0
+		}
+
+		more = from > 0
+	} else {
+		// rows placed after the cursor close the list.
+		from = sort.Search(n, func(i int) bool { return r.Cursor == nil || r.Cursor.Before(at(i), r.Order) })
+		to = from + r.Limit
+		if to > n {
+			to = n
+		}
+
+		more = to < n
+	}
+
+	if from >= to {
+		return from, from, Info{}
+	}
+
+	return from, to, r.Info(at(from), at(to-1), more)
+}
+
+// Before reports whether the cursor is placed before other in a list
+// sorted by order.
+func (c Cursor) Before(other Cursor, order Order) bool {
+	if order == Asc {
+		return c.less(other)
+	}
+
+	return other.less(c)
+}
+
+// less reports whether the cursor sorts before other by creation time and id.
+func (c Cursor) less(other Cursor) bool {
+	if !c.CreatedAt.Equal(other.CreatedAt) {
+		return c.CreatedAt.Before(other.CreatedAt)
+	}
+
+	return c.ID < other.ID
+}
+
+// Info describes where a page stands within the whole list.
+type Info struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+// Info returns the Info of a non empty page whose rows, in list order, go
+// from first to last. more is the second value returned by Trim.
+func (r Request) Info(first, last Cursor, more bool) Info {
+	hasNext, hasPrev := more, r.Cursor != nil
+	if r.Backward() {
+		hasNext, hasPrev = true, more
+	}
+
+	var info Info
+	if hasNext {
+		last.Backward = false
+		info.NextCursor = last.Encode()
+	}
+
+	if hasPrev {
+		first.Backward = true
+		info.PrevCursor = first.Encode()
+	}
+
+	return info
+}
@@ -0,0 +1,188 @@
+package page
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor_Encode(t *testing.T) {
+	cursor := Cursor{CreatedAt: time.Date(2020, 9, 4, 10, 30, 0, 123000, time.UTC), ID: 42}
+
+	decoded, err := DecodeCursor(cursor.Encode())
+	assert.NoError(t, err)
+	assert.True(t, cursor.CreatedAt.Equal(decoded.CreatedAt))
+	assert.Equal(t, cursor.ID, decoded.ID)
+	assert.False(t, decoded.Backward)
+
+	cursor.Backward = true
+	decoded, err = DecodeCursor(cursor.Encode())
+	assert.NoError(t, err)
+	assert.True(t, decoded.Backward)
+}
+
+func TestDecodeCursor(t *testing.T) {
+	for _, s := range []string{"%%%", "bm9jb2xvbg", "YTox", "MTpi", "MToxOm5leHQ"} {
+		_, err := DecodeCursor(s)
+		assert.Equal(t, ErrInvalidCursor, err, s)
+	}
+}
+
+func TestFromQuery(t *testing.T) {
+
+	t.Run("Defaults", func(tt *testing.T) {
+		r, err := FromQuery(url.Values{})
+		assert.NoError(tt, err)
+		assert.Equal(tt, DefaultLimit, r.Limit)
+		assert.Equal(tt, Desc, r.Order)
+		assert.Nil(tt, r.Cursor)
+	})
+
+	t.Run("Capped Limit", func(tt *testing.T) {
+		r, err := FromQuery(url.Values{"limit": {"1000"}})
+		assert.NoError(tt, err)
+		assert.Equal(tt, MaxLimit, r.Limit)
+	})
+
+	t.Run("Error Limit", func(tt *testing.T) {
+		_, err := FromQuery(url.Values{"limit": {"-1"}})
+		assert.Equal(tt, ErrInvalidLimit, err)
+
+		_, err = FromQuery(url.Values{"limit": {"ten"}})
+		assert.Equal(tt, ErrInvalidLimit, err)
+	})
+
+	t.Run("Error Order", func(tt *testing.T) {
+		_, err := FromQuery(url.Values{"order": {"random"}})
+		assert.Equal(tt, ErrInvalidOrder, err)
+	})
+
+	t.Run("Cursor", func(tt *testing.T) {
+		cursor := Cursor{CreatedAt: time.Now().UTC(), ID: 7}
+
+		r, err := FromQuery(url.Values{"cursor": {cursor.Encode()}, "limit": {"5"}, "order": {"ASC"}})
+		assert.NoError(tt, err)
+		assert.Equal(tt, 5, r.Limit)
+		assert.Equal(tt, Asc, r.Order)
+		assert.Equal(tt, uint(7), r.Cursor.ID)
+	})
+}
+
+func TestRequest_Seek(t *testing.T) {
+	forward := &Cursor{ID: 1}
+	backward := &Cursor{ID: 1, Backward: true}
+
+	tests := []struct {
+		Name    string
+		Request Request
+		Op      string
+		Order   string
+	}{
+		{Name: "Desc", Request: Request{Order: Desc}, Op: "<", Order: "DESC"},
+		{Name: "Asc", Request: Request{Order: Asc, Cursor: forward}, Op: ">", Order: "ASC"},
+		{Name: "Desc Backward", Request: Request{Order: Desc, Cursor: backward}, Op: ">", Order: "ASC"},
+		{Name: "Asc Backward", Request: Request{Order: Asc, Cursor: backward}, Op: "<", Order: "DESC"},
+	}
+
+	for _, test := range tests {
+		op, order := test.Request.Seek()
+		assert.Equal(t, test.Op, op, test.Name)
+		assert.Equal(t, test.Order, order, test.Name)
+	}
+}
+
+func TestRequest_Info(t *testing.T) {
+	first, last := Cursor{ID: 3}, Cursor{ID: 2}
+
+	t.Run("First Page", func(tt *testing.T) {
+		r := Request{Limit: 2, Order: Desc}
+
+		keep, more := r.Trim(r.Fetch())
+		assert.Equal(tt, 2, keep)
+		assert.True(tt, more)
+
+		info := r.Info(first, last, more)
+		assert.NotEmpty(tt, info.NextCursor)
+		assert.Empty(tt, info.PrevCursor)
+
+		next, err := DecodeCursor(info.NextCursor)
+		assert.NoError(tt, err)
+		assert.Equal(tt, uint(2), next.ID)
+		assert.False(tt, next.Backward)
+	})
+
+	t.Run("Last Page", func(tt *testing.T) {
+		r := Request{Limit: 2, Order: Desc, Cursor: &Cursor{ID: 4}}
+
+		keep, more := r.Trim(1)
+		assert.Equal(tt, 1, keep)
+		assert.False(tt, more)
+
+		info := r.Info(first, last, more)
+		assert.Empty(tt, info.NextCursor)
+
+		prev, err := DecodeCursor(info.PrevCursor)
+		assert.NoError(tt, err)
+		assert.Equal(tt, uint(3), prev.ID)
+		assert.True(tt, prev.Backward)
+	})
+
+	t.Run("Backward To First Page", func(tt *testing.T) {
+		r := Request{Limit: 2, Order: Desc, Cursor: &Cursor{ID: 1, Backward: true}}
+
+		info := r.Info(first, last, false)
+		assert.NotEmpty(tt, info.NextCursor)
+		assert.Empty(tt, info.PrevCursor)
+	})
+}
+
+func TestRequest_Slice(t *testing.T) {
+	now := time.Now().UTC()
+
+	// ids 5 to 1, newest first.
+	var cursors []Cursor
+	for id := 5; id >= 1; id-- {
+		cursors = append(cursors, Cursor{CreatedAt: now.Add(time.Duration(id) * time.Second), ID: uint(id)})
+	}
+
+	at := func(i int) Cursor { return cursors[i] }
+
+	t.Run("First Page", func(tt *testing.T) {
+		r := Request{Limit: 2, Order: Desc}
+
+		from, to, info := r.Slice(len(cursors), at)
+		assert.Equal(tt, 0, from)
+		assert.Equal(tt, 2, to)
+		assert.NotEmpty(tt, info.NextCursor)
+		assert.Empty(tt, info.PrevCursor)
+	})
+
+	t.Run("Walk Forward And Back", func(tt *testing.T) {
+		r := Request{Limit: 2, Order: Desc, Cursor: &cursors[1]}
+
+		from, to, info := r.Slice(len(cursors), at)
+		assert.Equal(tt, 2, from)
+		assert.Equal(tt, 4, to)
+		assert.NotEmpty(tt, info.NextCursor)
+
+		prev, err := DecodeCursor(info.PrevCursor)
+		assert.NoError(tt, err)
+
+		r.Cursor = &prev
+		from, to, info = r.Slice(len(cursors), at)
+		assert.Equal(tt, 0, from)
+		assert.Equal(tt, 2, to)
+		assert.Empty(tt, info.PrevCursor)
+		assert.NotEmpty(tt, info.NextCursor)
+	})
+
+	t.Run("Past The End", func(tt *testing.T) {
+		r := Request{Limit: 2, Order: Desc, Cursor: &cursors[4]}
+
+		from, to, info := r.Slice(len(cursors), at)
+		assert.Equal(tt, from, to)
+		assert.Equal(tt, Info{}, info)
+	})
+}
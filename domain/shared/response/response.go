@@ -0,0 +1,114 @@
+// Package response writes the HTTP responses of every domain: JSON bodies
+// and RFC 7807 problem details for the errors.
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"microblog/domain/shared/errs"
+	"net/http"
+
+	"github.com/go-chi/chi/middleware"
+)
+
+// ProblemType is the type of every problem. The status code and the title
+// are enough to tell problems apart, so no custom types are defined.
+const ProblemType = "about:blank"
+
+// Problem standardized error response, as described by RFC 7807.
+type Problem struct {
+	Type     string            `json:"type"`
+	Title    string            `json:"title"`
+	Status   int               `json:"status"`
+	Detail   string            `json:"detail,omitempty"`
+	Instance string            `json:"instance,omitempty"`
+	Errors   []errs.FieldError `json:"errors,omitempty"`
+}
+
+// Map is a convenient way to create objects of unknown types.
+type Map map[string]interface{}
+
+// JSON standardized JSON response.
+func JSON(w http.ResponseWriter, r *http.Request, statusCode int, data interface{}) error {
+	return write(w, "application/json; charset=utf-8", statusCode, data)
+}
+
+// HTTPError standardized error response in problem+json format. The
+// instance is the ID chi's RequestID middleware gave to the request.
+func HTTPError(w http.ResponseWriter, r *http.Request, statusCode int, detail string) error {
+	return problem(w, r, statusCode, detail, nil)
+}
+
+// StatusCode returns the HTTP status code matching the kind of the domain error.
+func StatusCode(err error) int {
+	switch {
+	case errors.Is(err, errs.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, errs.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, errs.ErrValidation):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, errs.ErrForbidden):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Error responds the error with the status code of its kind, along with the
+// invalid fields it reports. Errors of no known kind are logged and hidden
+// behind a generic detail.
+func Error(w http.ResponseWriter, r *http.Request, err error) error {
+	statusCode := StatusCode(err)
+	if statusCode == http.StatusInternalServerError {
+		log.Printf("%s %s [%s]: %v", r.Method, r.URL.Path, middleware.GetReqID(r.Context()), err)
+		return problem(w, r, statusCode, http.StatusText(statusCode), nil)
+	}
+
+	return problem(w, r, statusCode, err.Error(), errs.Fields(err))
+}
+
+// NotFound responds a problem for the routes that do not exist.
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	_ = HTTPError(w, r, http.StatusNotFound, "no route matches "+r.URL.Path)
+}
+
+// MethodNotAllowed responds a problem for the routes that do not accept the
+// method of the request.
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	_ = HTTPError(w, r, http.StatusMethodNotAllowed, r.Method+" is not allowed on "+r.URL.Path)
+}
+
+// problem writes the problem details of the status code.
+func problem(w http.ResponseWriter, r *http.Request, statusCode int, detail string, fields []errs.FieldError) error {
+	p := Problem{
+		Type:     ProblemType,
+		Title:    http.StatusText(statusCode),
+		Status:   statusCode,
+		Detail:   detail,
+		Instance: middleware.GetReqID(r.Context()),
+		Errors:   fields,
+	}
+
+	return write(w, "application/problem+json", statusCode, p)
+}
+
+// write writes the data as the JSON body of the response.
+func write(w http.ResponseWriter, contentType string, statusCode int, data interface{}) error {
+	if data == nil {
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(statusCode)
+		return nil
+	}
+
+	j, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(j)
+	return nil
+}
@@ -0,0 +1,73 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"microblog/domain/shared/errs"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+// serve runs handler behind chi's RequestID middleware and decodes the
+// problem it responds.
+func serve(t *testing.T, handler http.HandlerFunc) (*httptest.ResponseRecorder, Problem) {
+	t.Helper()
+
+	response := httptest.NewRecorder()
+	middleware.RequestID(handler).ServeHTTP(response, httptest.NewRequest(http.MethodPost, "/api/v1/users/", nil))
+
+	var p Problem
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&p))
+
+	return response, p
+}
+
+func TestHTTPError(t *testing.T) {
+	response, p := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = HTTPError(w, r, http.StatusBadRequest, "unexpected EOF")
+	})
+
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Equal(t, "application/problem+json", response.Header().Get("Content-Type"))
+	assert.Equal(t, ProblemType, p.Type)
+	assert.Equal(t, "Bad Request", p.Title)
+	assert.Equal(t, http.StatusBadRequest, p.Status)
+	assert.Equal(t, "unexpected EOF", p.Detail)
+	assert.NotEmpty(t, p.Instance)
+	assert.Empty(t, p.Errors)
+}
+
+func TestError(t *testing.T) {
+	t.Run("Validation Fields", func(tt *testing.T) {
+		err := errs.Invalid(errs.FieldError{Field: "email", Message: "invalid email"})
+
+		response, p := serve(tt, func(w http.ResponseWriter, r *http.Request) {
+			_ = Error(w, r, err)
+		})
+
+		assert.Equal(tt, http.StatusUnprocessableEntity, response.Code)
+		assert.Equal(tt, "invalid email", p.Detail)
+		assert.Equal(tt, []errs.FieldError{{Field: "email", Message: "invalid email"}}, p.Errors)
+	})
+
+	t.Run("Unknown Kind Hidden", func(tt *testing.T) {
+		response, p := serve(tt, func(w http.ResponseWriter, r *http.Request) {
+			_ = Error(w, r, errors.New("pq: connection refused"))
+		})
+
+		assert.Equal(tt, http.StatusInternalServerError, response.Code)
+		assert.Equal(tt, "Internal Server Error", p.Detail)
+	})
+}
+
+func TestStatusCode(t *testing.T) {
+	assert.Equal(t, http.StatusNotFound, StatusCode(errs.NotFound("user not found")))
+	assert.Equal(t, http.StatusConflict, StatusCode(errs.Conflict("email already exists")))
+	assert.Equal(t, http.StatusUnprocessableEntity, StatusCode(errs.Validation("invalid email")))
+	assert.Equal(t, http.StatusForbidden, StatusCode(errs.Forbidden("not the author")))
+	assert.Equal(t, http.StatusInternalServerError, StatusCode(errors.New("error sql")))
+}
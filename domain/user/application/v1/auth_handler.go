@@ -0,0 +1,187 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"microblog/domain/shared/errs"
+	"microblog/domain/shared/response"
+	"microblog/domain/user/domain"
+	"microblog/infrastructure/auth"
+	"net/http"
+)
+
+// errInvalidCredentials is the message returned when a login attempt fails.
+const errInvalidCredentials = "invalid email or password"
+
+// AuthRouter is the router of the authentication.
+type AuthRouter struct {
+	Repository domain.Repository
+	Tokens     *auth.TokenManager
+	Sessions   *auth.RefreshManager
+}
+
+// TokenResponse is the body returned when a pair of tokens is issued.
+type TokenResponse struct {
+	auth.AccessToken
+	auth.RefreshToken
+	User *domain.User `json:"user,omitempty"`
+}
+
+// refreshRequest is the body expected by the refresh and logout endpoints.
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// decodeRefreshRequest reads the refresh token sent in the request body.
+func decodeRefreshRequest(r *http.Request) (string, error) {
+	var body refreshRequest
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		return "", err
+	}
+
+	defer r.Body.Close()
+
+	if body.RefreshToken == "" {
+		return "", errors.New("required refresh_token")
+	}
+
+	return body.RefreshToken, nil
+}
+
+// LoginHandler authenticates a user by email and password and responds
+// with a signed access token and the refresh token of a new session.
+func (ar *AuthRouter) LoginHandler(w http.ResponseWriter, r *http.Request) {
+	var credentials domain.User
+	err := json.NewDecoder(r.Body).Decode(&credentials)
+	if err != nil {
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	defer r.Body.Close()
+
+	err = credentials.Validate("login")
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	ctx := r.Context()
+	user, err := ar.Repository.GetByEmail(ctx, credentials.Email)
+	if err != nil && !errors.Is(err, errs.ErrNotFound) {
+		response.Error(w, r, err)
+		return
+	}
+
+	if err != nil || !user.PasswordMatch(credentials.Password) {
+		response.HTTPError(w, r, http.StatusUnauthorized, errInvalidCredentials)
+		return
+	}
+
+	token, err := ar.Tokens.Generate(user)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	refresh, err := ar.Sessions.Issue(ctx, user.ID)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	user.PasswordHash = ""
+	response.JSON(w, r, http.StatusOK, TokenResponse{AccessToken: token, RefreshToken: refresh, User: &user})
+}
+
+// RefreshHandler exchanges a refresh token for a new access token and a new
+// refresh token. The presented refresh token can not be used again.
+func (ar *AuthRouter) RefreshHandler(w http.ResponseWriter, r *http.Request) {
+	raw, err := decodeRefreshRequest(r)
+	if err != nil {
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	userID, refresh, err := ar.Sessions.Rotate(ctx, raw)
+	if errors.Is(err, auth.ErrInvalidRefreshToken) || errors.Is(err, auth.ErrRefreshTokenReused) {
+		response.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	user, err := ar.Repository.GetOne(ctx, userID)
+	if err != nil {
+		response.HTTPError(w, r, http.StatusUnauthorized, auth.ErrInvalidRefreshToken.Error())
+		return
+	}
+
+	token, err := ar.Tokens.Generate(user)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, r, http.StatusOK, TokenResponse{AccessToken: token, RefreshToken: refresh})
+}
+
+// LogoutHandler ends the session of the given refresh token.
+func (ar *AuthRouter) LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	raw, err := decodeRefreshRequest(r)
+	if err != nil {
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	err = ar.Sessions.Revoke(ctx, raw)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, r, http.StatusNoContent, nil)
+}
+
+// LogoutAllHandler ends every session of the authenticated user. Access
+// tokens already issued remain valid until they expire.
+func (ar *AuthRouter) LogoutAllHandler(w http.ResponseWriter, r *http.Request) {
+	principal, ok := auth.FromContext(r.Context())
+	if !ok {
+		response.HTTPError(w, r, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	ctx := r.Context()
+	err := ar.Sessions.RevokeAll(ctx, principal.UserID)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, r, http.StatusNoContent, nil)
+}
+
+// MeHandler response the authenticated user.
+func (ar *AuthRouter) MeHandler(w http.ResponseWriter, r *http.Request) {
+	principal, ok := auth.FromContext(r.Context())
+	if !ok {
+		response.HTTPError(w, r, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	ctx := r.Context()
+	user, err := ar.Repository.GetOne(ctx, principal.UserID)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, r, http.StatusOK, user)
+}
@@ -0,0 +1,240 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"microblog/domain/shared/errs"
+	"microblog/domain/user/domain"
+	mockLocal "microblog/domain/user/domain/mocks"
+	"microblog/infrastructure/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// dataMockLogin is a stored user whose password is "123456".
+func dataMockLogin(tt *testing.T) domain.User {
+	user := *dataMockCreate()
+	assert.NoError(tt, user.HashPassword())
+	user.Password = ""
+
+	return user
+}
+
+func TestAuthRouter_LoginHandler(t *testing.T) {
+	tokens := auth.NewTokenManager("secret", time.Minute)
+
+	t.Run("Error Body Login Handler", func(tt *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewReader(nil))
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testAuthHandler := &AuthRouter{Repository: mockRepository, Tokens: tokens}
+
+		testAuthHandler.LoginHandler(response, request)
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Validate Login Handler", func(tt *testing.T) {
+		marshal, err := json.Marshal(domain.User{Email: "daniel.delapava@jikkosoft.com"})
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewReader(marshal))
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testAuthHandler := &AuthRouter{Repository: mockRepository, Tokens: tokens}
+
+		testAuthHandler.LoginHandler(response, request)
+		assert.Equal(tt, http.StatusUnprocessableEntity, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Error Unknown Email Login Handler", func(tt *testing.T) {
+		marshal, err := json.Marshal(dataMockCreate())
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewReader(marshal))
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testAuthHandler := &AuthRouter{Repository: mockRepository, Tokens: tokens}
+		mockRepository.On("GetByEmail", mock.Anything, mock.Anything).Return(domain.User{}, errs.NotFound("user not found")).Once()
+
+		testAuthHandler.LoginHandler(response, request)
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Error Repository Login Handler", func(tt *testing.T) {
+		marshal, err := json.Marshal(dataMockCreate())
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewReader(marshal))
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testAuthHandler := &AuthRouter{Repository: mockRepository, Tokens: tokens}
+		mockRepository.On("GetByEmail", mock.Anything, mock.Anything).Return(domain.User{}, errors.New("error sql")).Once()
+
+		testAuthHandler.LoginHandler(response, request)
+		assert.Equal(tt, http.StatusInternalServerError, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Error Wrong Password Login Handler", func(tt *testing.T) {
+		credentials := dataMockCreate()
+		credentials.Password = "wrong"
+
+		marshal, err := json.Marshal(credentials)
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewReader(marshal))
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testAuthHandler := &AuthRouter{Repository: mockRepository, Tokens: tokens}
+		mockRepository.On("GetByEmail", mock.Anything, credentials.Email).Return(dataMockLogin(tt), nil).Once()
+
+		testAuthHandler.LoginHandler(response, request)
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Login Handler", func(tt *testing.T) {
+		marshal, err := json.Marshal(dataMockCreate())
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewReader(marshal))
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+		mockSessions := &mockLocal.RefreshTokenRepository{}
+
+		testAuthHandler := &AuthRouter{Repository: mockRepository, Tokens: tokens, Sessions: auth.NewRefreshManager(mockSessions, 0)}
+		mockRepository.On("GetByEmail", mock.Anything, mock.Anything).Return(dataMockLogin(tt), nil).Once()
+		mockSessions.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+
+		testAuthHandler.LoginHandler(response, request)
+		assert.Equal(tt, http.StatusOK, response.Code)
+		mockRepository.AssertExpectations(tt)
+		mockSessions.AssertExpectations(tt)
+
+		var body TokenResponse
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&body))
+
+		claims, err := tokens.Parse(body.AccessToken.Token)
+		assert.NoError(tt, err)
+		assert.Equal(tt, uint(1), claims.UserID)
+		assert.NotEmpty(tt, body.RefreshToken.Token)
+		assert.Equal(tt, "daniel.delapava", body.User.Username)
+	})
+}
+
+func TestAuthRouter_RefreshHandler(t *testing.T) {
+	tokens := auth.NewTokenManager("secret", time.Minute)
+
+	t.Run("Error Body Refresh Handler", func(tt *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/auth/refresh", bytes.NewReader([]byte(`{}`)))
+		response := httptest.NewRecorder()
+		mockSessions := &mockLocal.RefreshTokenRepository{}
+
+		testAuthHandler := &AuthRouter{Tokens: tokens, Sessions: auth.NewRefreshManager(mockSessions, 0)}
+
+		testAuthHandler.RefreshHandler(response, request)
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+		mockSessions.AssertExpectations(tt)
+	})
+
+	t.Run("Error Reused Refresh Handler", func(tt *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/auth/refresh", bytes.NewReader([]byte(`{"refresh_token":"reused"}`)))
+		response := httptest.NewRecorder()
+		mockSessions := &mockLocal.RefreshTokenRepository{}
+
+		testAuthHandler := &AuthRouter{Tokens: tokens, Sessions: auth.NewRefreshManager(mockSessions, 0)}
+
+		revokedAt := time.Now()
+		mockSessions.On("GetByHash", mock.Anything, mock.Anything).
+			Return(domain.RefreshToken{ID: 1, UserID: 1, FamilyID: "family", RevokedAt: &revokedAt}, nil).Once()
+		mockSessions.On("RevokeFamily", mock.Anything, "family").Return(nil).Once()
+
+		testAuthHandler.RefreshHandler(response, request)
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+		mockSessions.AssertExpectations(tt)
+	})
+
+	t.Run("Refresh Handler", func(tt *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/auth/refresh", bytes.NewReader([]byte(`{"refresh_token":"valid"}`)))
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+		mockSessions := &mockLocal.RefreshTokenRepository{}
+
+		testAuthHandler := &AuthRouter{Repository: mockRepository, Tokens: tokens, Sessions: auth.NewRefreshManager(mockSessions, 0)}
+
+		mockSessions.On("GetByHash", mock.Anything, mock.Anything).
+			Return(domain.RefreshToken{ID: 1, UserID: 1, FamilyID: "family", ExpiresAt: time.Now().Add(time.Hour)}, nil).Once()
+		mockSessions.On("Revoke", mock.Anything, uint(1)).Return(true, nil).Once()
+		mockSessions.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+		mockRepository.On("GetOne", mock.Anything, uint(1)).Return(*dataMockCreate(), nil).Once()
+
+		testAuthHandler.RefreshHandler(response, request)
+		assert.Equal(tt, http.StatusOK, response.Code)
+		mockRepository.AssertExpectations(tt)
+		mockSessions.AssertExpectations(tt)
+
+		var body TokenResponse
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&body))
+		assert.NotEmpty(tt, body.AccessToken.Token)
+		assert.NotEmpty(tt, body.RefreshToken.Token)
+		assert.Nil(tt, body.User)
+	})
+}
+
+func TestAuthRouter_LogoutHandler(t *testing.T) {
+	request := httptest.NewRequest(http.MethodPost, "/api/v1/auth/logout", bytes.NewReader([]byte(`{"refresh_token":"valid"}`)))
+	response := httptest.NewRecorder()
+	mockSessions := &mockLocal.RefreshTokenRepository{}
+
+	testAuthHandler := &AuthRouter{Sessions: auth.NewRefreshManager(mockSessions, 0)}
+
+	mockSessions.On("GetByHash", mock.Anything, mock.Anything).Return(domain.RefreshToken{ID: 1, FamilyID: "family"}, nil).Once()
+	mockSessions.On("RevokeFamily", mock.Anything, "family").Return(nil).Once()
+
+	testAuthHandler.LogoutHandler(response, request)
+	assert.Equal(t, http.StatusNoContent, response.Code)
+	mockSessions.AssertExpectations(t)
+}
+
+func TestAuthRouter_LogoutAllHandler(t *testing.T) {
+
+	t.Run("Error Unauthenticated Logout All Handler", func(tt *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/auth/logout-all", nil)
+		response := httptest.NewRecorder()
+		mockSessions := &mockLocal.RefreshTokenRepository{}
+
+		testAuthHandler := &AuthRouter{Sessions: auth.NewRefreshManager(mockSessions, 0)}
+
+		testAuthHandler.LogoutAllHandler(response, request)
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+		mockSessions.AssertExpectations(tt)
+	})
+
+	t.Run("Logout All Handler", func(tt *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/auth/logout-all", nil)
+		request = request.WithContext(auth.NewContext(request.Context(), auth.Principal{UserID: 1}))
+		response := httptest.NewRecorder()
+		mockSessions := &mockLocal.RefreshTokenRepository{}
+
+		testAuthHandler := &AuthRouter{Sessions: auth.NewRefreshManager(mockSessions, 0)}
+		mockSessions.On("RevokeAllByUser", mock.Anything, uint(1)).Return(nil).Once()
+
+		testAuthHandler.LogoutAllHandler(response, request)
+		assert.Equal(tt, http.StatusNoContent, response.Code)
+		mockSessions.AssertExpectations(tt)
+	})
+}
@@ -3,7 +3,9 @@ package v1
 import (
 	"encoding/json"
 	"fmt"
-	"microblog/domain/user/application"
+	followDomain "microblog/domain/follow/domain"
+	"microblog/domain/shared/page"
+	"microblog/domain/shared/response"
 	"microblog/domain/user/domain"
 	"net/http"
 	"strconv"
@@ -14,6 +16,19 @@ import (
 // UserRouter is the router of the users.
 type UserRouter struct {
 	Repository domain.Repository
+	Follows    followDomain.Repository
+}
+
+// UserProfile is a user along with its follow graph counters.
+type UserProfile struct {
+	domain.User
+	followDomain.Counts
+}
+
+// UserPage is a page of users along with the cursors of its neighbours.
+type UserPage struct {
+	Items []domain.User `json:"items"`
+	page.Info
 }
 
 // CreateHandler Create a new user.
@@ -21,45 +36,56 @@ func (ur *UserRouter) CreateHandler(w http.ResponseWriter, r *http.Request) {
 	var user domain.User
 	err := json.NewDecoder(r.Body).Decode(&user)
 	if err != nil {
-		server.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	defer r.Body.Close()
 	if err := user.HashPassword(); err != nil {
-		server.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	err = user.Validate("")
 	if err != nil {
-		server.HTTPError(w, r, http.StatusUnprocessableEntity, err.Error())
+		response.Error(w, r, err)
 		return
 	}
 
+	user.Role = domain.RoleUser
+
 	ctx := r.Context()
 	err = ur.Repository.Create(ctx, &user)
 	if err != nil {
-		server.HTTPError(w, r, http.StatusConflict, err.Error())
+		response.Error(w, r, err)
 		return
 	}
 
 	user.Password = ""
 	w.Header().Add("Location", fmt.Sprintf("%s%d", r.URL.String(), user.ID))
-	server.JSON(w, r, http.StatusCreated, user)
+	response.JSON(w, r, http.StatusCreated, user)
 }
 
-// GetAllUser response all the users.
+// GetAllUser response a page of the users.
 func (ur *UserRouter) GetAllUser(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	p, err := page.FromQuery(r.URL.Query())
+	if err != nil {
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	users, err := ur.Repository.GetAllUser(ctx)
+	ctx := r.Context()
+	users, info, err := ur.Repository.GetAllUser(ctx, p)
 	if err != nil {
-		server.HTTPError(w, r, http.StatusNotFound, err.Error())
+		response.Error(w, r, err)
 		return
 	}
 
-	server.JSON(w, r, http.StatusOK, users)
+	if users == nil {
+		users = []domain.User{}
+	}
+
+	response.JSON(w, r, http.StatusOK, UserPage{Items: users, Info: info})
 }
 
 // GetOneHandler response one user by id.
@@ -68,18 +94,24 @@ func (ur *UserRouter) GetOneHandler(w http.ResponseWriter, r *http.Request) {
 
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		server.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	ctx := r.Context()
 	userResult, err := ur.Repository.GetOne(ctx, uint(id))
 	if err != nil {
-		server.HTTPError(w, r, http.StatusNotFound, err.Error())
+		response.Error(w, r, err)
+		return
+	}
+
+	counts, err := ur.Follows.Counts(ctx, userResult.ID)
+	if err != nil {
+		response.Error(w, r, err)
 		return
 	}
 
-	server.JSON(w, r, http.StatusOK, userResult)
+	response.JSON(w, r, http.StatusOK, UserProfile{User: userResult, Counts: counts})
 }
 
 // UpdateHandler update a stored user by id.
@@ -88,14 +120,14 @@ func (ur *UserRouter) UpdateHandler(w http.ResponseWriter, r *http.Request) {
 
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		server.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	var userUpdate domain.User
 	err = json.NewDecoder(r.Body).Decode(&userUpdate)
 	if err != nil {
-		server.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -103,18 +135,18 @@ func (ur *UserRouter) UpdateHandler(w http.ResponseWriter, r *http.Request) {
 
 	err = userUpdate.Validate("")
 	if err != nil {
-		server.HTTPError(w, r, http.StatusUnprocessableEntity, err.Error())
+		response.Error(w, r, err)
 		return
 	}
 
 	ctx := r.Context()
 	err = ur.Repository.Update(ctx, uint(id), userUpdate)
 	if err != nil {
-		server.HTTPError(w, r, http.StatusConflict, err.Error())
+		response.Error(w, r, err)
 		return
 	}
 
-	server.JSON(w, r, http.StatusOK, nil)
+	response.JSON(w, r, http.StatusOK, nil)
 }
 
 // DeleteHandler Remove a user by ID.
@@ -123,16 +155,16 @@ func (ur *UserRouter) DeleteHandler(w http.ResponseWriter, r *http.Request) {
 
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		server.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	ctx := r.Context()
 	err = ur.Repository.Delete(ctx, uint(id))
 	if err != nil {
-		server.HTTPError(w, r, http.StatusNotFound, err.Error())
+		response.Error(w, r, err)
 		return
 	}
 
-	server.JSON(w, r, http.StatusNoContent, server.Map{})
+	response.JSON(w, r, http.StatusNoContent, response.Map{})
 }
@@ -8,6 +8,9 @@ import (
 	"github.com/go-chi/chi"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	followDomain "microblog/domain/follow/domain"
+	mockFollow "microblog/domain/follow/domain/mocks"
+	"microblog/domain/shared/page"
 	"microblog/domain/user/domain"
 	mockLocal "microblog/domain/user/domain/mocks"
 	"net/http"
@@ -137,7 +140,7 @@ func TestUserRouter_GetAllUser(t *testing.T) {
 		mockRepository := &mockLocal.Repository{}
 
 		testUserHandler := &UserRouter{Repository: mockRepository}
-		mockRepository.On("GetAllUser", mock.Anything, mock.Anything).Return(nil, errors.New("error trace test"))
+		mockRepository.On("GetAllUser", mock.Anything, mock.Anything).Return(nil, page.Info{}, errors.New("error trace test"))
 
 		testUserHandler.GetAllUser(response, request)
 		mockRepository.AssertExpectations(tt)
@@ -151,11 +154,28 @@ func TestUserRouter_GetAllUser(t *testing.T) {
 		mockRepository := &mockLocal.Repository{}
 
 		testUserHandler := &UserRouter{Repository: mockRepository}
-		mockRepository.On("GetAllUser", mock.Anything).Return(dataUSer(), nil)
+		mockRepository.On("GetAllUser", mock.Anything, mock.Anything).Return(dataUSer(), page.Info{}, nil)
 
 		testUserHandler.GetAllUser(response, request)
 		mockRepository.AssertExpectations(tt)
 
+		var body UserPage
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&body))
+		assert.Len(tt, body.Items, len(dataUSer()))
+	})
+
+	t.Run("Error Limit Get All User Handler", func(tt *testing.T) {
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/users/?limit=-1", nil)
+		response := httptest.NewRecorder()
+		mockRepository := &mockLocal.Repository{}
+
+		testUserHandler := &UserRouter{Repository: mockRepository}
+
+		testUserHandler.GetAllUser(response, request)
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+		mockRepository.AssertExpectations(tt)
+
 	})
 
 }
@@ -202,12 +222,20 @@ func TestUserRouter_GetOneHandler(t *testing.T) {
 
 		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
 		mockRepository := &mockLocal.Repository{}
+		mockFollows := &mockFollow.Repository{}
 
-		testUserHandler := &UserRouter{Repository: mockRepository}
-		mockRepository.On("GetOne", mock.Anything, mock.Anything).Return(domain.User{}, nil).Once()
+		testUserHandler := &UserRouter{Repository: mockRepository, Follows: mockFollows}
+		mockRepository.On("GetOne", mock.Anything, mock.Anything).Return(domain.User{ID: 1}, nil).Once()
+		mockFollows.On("Counts", mock.Anything, uint(1)).Return(followDomain.Counts{Followers: 2, Following: 3}, nil).Once()
 
 		testUserHandler.GetOneHandler(response, request)
 		mockRepository.AssertExpectations(tt)
+		mockFollows.AssertExpectations(tt)
+
+		var profile UserProfile
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&profile))
+		assert.Equal(tt, 2, profile.Followers)
+		assert.Equal(tt, 3, profile.Following)
 	})
 }
 
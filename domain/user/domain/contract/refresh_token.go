@@ -0,0 +1,143 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"microblog/domain/shared/errs"
+	"microblog/domain/user/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// RefreshTokenHarness is a refresh token repository under test along with
+// a way to create the users its tokens belong to.
+type RefreshTokenHarness struct {
+	Repository domain.RefreshTokenRepository
+	NewUser    func(t *testing.T) uint
+}
+
+// createToken stores a token of the user in the family or stops the test.
+func createToken(t *testing.T, repository domain.RefreshTokenRepository, userID uint, family, hash string) domain.RefreshToken {
+	t.Helper()
+
+	token := domain.RefreshToken{
+		UserID:    userID,
+		FamilyID:  family,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(time.Hour).UTC().Truncate(time.Second),
+	}
+
+	if err := repository.Create(context.Background(), &token); err != nil {
+		t.Fatalf("error creating refresh token %s: %v", hash, err)
+	}
+
+	return token
+}
+
+// revoked reports whether the token stored with hash is revoked.
+func revoked(t *testing.T, repository domain.RefreshTokenRepository, hash string) bool {
+	t.Helper()
+
+	token, err := repository.GetByHash(context.Background(), hash)
+	if err != nil {
+		t.Fatalf("error reading refresh token %s: %v", hash, err)
+	}
+
+	return token.Revoked()
+}
+
+// TestRefreshTokenRepository runs the domain.RefreshTokenRepository suite.
+// newHarness must return an empty repository each time it is called.
+func TestRefreshTokenRepository(t *testing.T, newHarness func(t *testing.T) RefreshTokenHarness) {
+	ctx := context.Background()
+
+	t.Run("Create And Get By Hash", func(tt *testing.T) {
+		h := newHarness(tt)
+		userID := h.NewUser(tt)
+
+		token := createToken(tt, h.Repository, userID, "family", "hash")
+		assert.NotZero(tt, token.ID)
+
+		stored, err := h.Repository.GetByHash(ctx, "hash")
+		assert.NoError(tt, err)
+		assert.Equal(tt, token.ID, stored.ID)
+		assert.Equal(tt, userID, stored.UserID)
+		assert.Equal(tt, "family", stored.FamilyID)
+		assert.True(tt, token.ExpiresAt.Equal(stored.ExpiresAt))
+		assert.False(tt, stored.Revoked())
+	})
+
+	t.Run("Expiry Kept Across Time Zones", func(tt *testing.T) {
+		h := newHarness(tt)
+		userID := h.NewUser(tt)
+
+		zone := time.FixedZone("UTC-5", -5*60*60)
+		token := domain.RefreshToken{UserID: userID, FamilyID: "family", TokenHash: "hash", ExpiresAt: time.Now().Add(time.Hour).In(zone).Truncate(time.Second)}
+		assert.NoError(tt, h.Repository.Create(ctx, &token))
+
+		stored, err := h.Repository.GetByHash(ctx, "hash")
+		assert.NoError(tt, err)
+		assert.True(tt, token.ExpiresAt.Equal(stored.ExpiresAt), "%s != %s", stored.ExpiresAt, token.ExpiresAt)
+		assert.False(tt, stored.Expired(time.Now().In(zone)))
+		assert.True(tt, stored.Expired(time.Now().Add(2*time.Hour).In(zone)))
+	})
+
+	t.Run("Error Not Found", func(tt *testing.T) {
+		h := newHarness(tt)
+
+		_, err := h.Repository.GetByHash(ctx, "unknown")
+		assert.True(tt, errors.Is(err, errs.ErrNotFound), "%v", err)
+	})
+
+	t.Run("Error Duplicate Hash", func(tt *testing.T) {
+		h := newHarness(tt)
+		userID := h.NewUser(tt)
+		createToken(tt, h.Repository, userID, "family", "hash")
+
+		duplicate := domain.RefreshToken{UserID: userID, FamilyID: "other", TokenHash: "hash", ExpiresAt: time.Now()}
+		err := h.Repository.Create(ctx, &duplicate)
+		assert.True(tt, errors.Is(err, errs.ErrConflict), "%v", err)
+	})
+
+	t.Run("Revoke Once", func(tt *testing.T) {
+		h := newHarness(tt)
+		token := createToken(tt, h.Repository, h.NewUser(tt), "family", "hash")
+
+		ok, err := h.Repository.Revoke(ctx, token.ID)
+		assert.NoError(tt, err)
+		assert.True(tt, ok)
+		assert.True(tt, revoked(tt, h.Repository, "hash"))
+
+		ok, err = h.Repository.Revoke(ctx, token.ID)
+		assert.NoError(tt, err)
+		assert.False(tt, ok)
+	})
+
+	t.Run("Revoke Family", func(tt *testing.T) {
+		h := newHarness(tt)
+		userID := h.NewUser(tt)
+		createToken(tt, h.Repository, userID, "family", "first")
+		createToken(tt, h.Repository, userID, "family", "second")
+		createToken(tt, h.Repository, userID, "other", "third")
+
+		assert.NoError(tt, h.Repository.RevokeFamily(ctx, "family"))
+		assert.True(tt, revoked(tt, h.Repository, "first"))
+		assert.True(tt, revoked(tt, h.Repository, "second"))
+		assert.False(tt, revoked(tt, h.Repository, "third"))
+	})
+
+	t.Run("Revoke All By User", func(tt *testing.T) {
+		h := newHarness(tt)
+		userID, otherID := h.NewUser(tt), h.NewUser(tt)
+		createToken(tt, h.Repository, userID, "family", "first")
+		createToken(tt, h.Repository, userID, "other", "second")
+		createToken(tt, h.Repository, otherID, "third", "third")
+
+		assert.NoError(tt, h.Repository.RevokeAllByUser(ctx, userID))
+		assert.True(tt, revoked(tt, h.Repository, "first"))
+		assert.True(tt, revoked(tt, h.Repository, "second"))
+		assert.False(tt, revoked(tt, h.Repository, "third"))
+	})
+}
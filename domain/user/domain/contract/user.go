@@ -0,0 +1,237 @@
+// Package contract holds the conformance suites every adapter of the user
+// ports must pass, whatever its storage.
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"microblog/domain/shared/errs"
+	"microblog/domain/shared/page"
+	"microblog/domain/user/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// NewUser returns a valid user whose username and email derive from name.
+func NewUser(name string) domain.User {
+	return domain.User{
+		FirstName:    "Daniel",
+		LastName:     "De La Pava Suarez",
+		Username:     name,
+		Email:        name + "@jikkosoft.com",
+		PasswordHash: "$2a$10$hash",
+		Role:         domain.RoleUser,
+	}
+}
+
+// create stores the user or stops the test.
+func create(t *testing.T, repository domain.Repository, user domain.User) domain.User {
+	t.Helper()
+
+	if err := repository.Create(context.Background(), &user); err != nil {
+		t.Fatalf("error creating user %s: %v", user.Username, err)
+	}
+
+	return user
+}
+
+// ids returns the ids of the users in order.
+func ids(users []domain.User) []uint {
+	result := make([]uint, 0, len(users))
+	for _, u := range users {
+		result = append(result, u.ID)
+	}
+
+	return result
+}
+
+// TestRepository runs the domain.Repository suite. newRepository must
+// return an empty repository each time it is called.
+func TestRepository(t *testing.T, newRepository func(t *testing.T) domain.Repository) {
+	ctx := context.Background()
+
+	t.Run("Create Assigns ID", func(tt *testing.T) {
+		repository := newRepository(tt)
+
+		first := create(tt, repository, NewUser("daniel.delapava"))
+		second := create(tt, repository, NewUser("rebecca.romero"))
+
+		assert.NotZero(tt, first.ID)
+		assert.Greater(tt, second.ID, first.ID)
+	})
+
+	t.Run("Get One", func(tt *testing.T) {
+		repository := newRepository(tt)
+		user := create(tt, repository, NewUser("daniel.delapava"))
+
+		stored, err := repository.GetOne(ctx, user.ID)
+		assert.NoError(tt, err)
+		assert.Equal(tt, user.ID, stored.ID)
+		assert.Equal(tt, user.Username, stored.Username)
+		assert.Equal(tt, user.Email, stored.Email)
+		assert.Equal(tt, domain.RoleUser, stored.Role)
+		assert.NotEmpty(tt, stored.Picture)
+		assert.Empty(tt, stored.PasswordHash)
+		assert.False(tt, stored.CreatedAt.IsZero())
+		assert.False(tt, stored.UpdatedAt.IsZero())
+	})
+
+	t.Run("Get By Username And Email", func(tt *testing.T) {
+		repository := newRepository(tt)
+		user := create(tt, repository, NewUser("daniel.delapava"))
+
+		byUsername, err := repository.GetByUsername(ctx, user.Username)
+		assert.NoError(tt, err)
+		assert.Equal(tt, user.ID, byUsername.ID)
+		assert.Equal(tt, user.PasswordHash, byUsername.PasswordHash)
+
+		byEmail, err := repository.GetByEmail(ctx, user.Email)
+		assert.NoError(tt, err)
+		assert.Equal(tt, user.ID, byEmail.ID)
+		assert.Equal(tt, user.PasswordHash, byEmail.PasswordHash)
+		assert.Equal(tt, domain.RoleUser, byEmail.Role)
+	})
+
+	t.Run("Get By Username Case Sensitive", func(tt *testing.T) {
+		repository := newRepository(tt)
+		lower := create(tt, repository, NewUser("alice"))
+		upper := create(tt, repository, NewUser("Alice"))
+
+		byUsername, err := repository.GetByUsername(ctx, "alice")
+		assert.NoError(tt, err)
+		assert.Equal(tt, lower.ID, byUsername.ID)
+
+		byUsername, err = repository.GetByUsername(ctx, "Alice")
+		assert.NoError(tt, err)
+		assert.Equal(tt, upper.ID, byUsername.ID)
+
+		_, err = repository.GetByUsername(ctx, "ALICE")
+		assert.True(tt, errors.Is(err, errs.ErrNotFound), "%v", err)
+	})
+
+	t.Run("Error Not Found", func(tt *testing.T) {
+		repository := newRepository(tt)
+		create(tt, repository, NewUser("daniel.delapava"))
+
+		_, err := repository.GetOne(ctx, 9999)
+		assert.True(tt, errors.Is(err, errs.ErrNotFound), "%v", err)
+
+		_, err = repository.GetByUsername(ctx, "nobody")
+		assert.True(tt, errors.Is(err, errs.ErrNotFound), "%v", err)
+
+		_, err = repository.GetByEmail(ctx, "nobody@jikkosoft.com")
+		assert.True(tt, errors.Is(err, errs.ErrNotFound), "%v", err)
+
+		err = repository.Update(ctx, 9999, NewUser("nobody"))
+		assert.True(tt, errors.Is(err, errs.ErrNotFound), "%v", err)
+
+		err = repository.Delete(ctx, 9999)
+		assert.True(tt, errors.Is(err, errs.ErrNotFound), "%v", err)
+	})
+
+	t.Run("Error Duplicate Username", func(tt *testing.T) {
+		repository := newRepository(tt)
+		create(tt, repository, NewUser("daniel.delapava"))
+
+		duplicate := NewUser("daniel.delapava")
+		duplicate.Email = "other@jikkosoft.com"
+		err := repository.Create(ctx, &duplicate)
+		assert.True(tt, errors.Is(err, errs.ErrConflict), "%v", err)
+	})
+
+	t.Run("Error Duplicate Email", func(tt *testing.T) {
+		repository := newRepository(tt)
+		first := create(tt, repository, NewUser("daniel.delapava"))
+		second := create(tt, repository, NewUser("rebecca.romero"))
+
+		duplicate := NewUser("other")
+		duplicate.Email = first.Email
+		err := repository.Create(ctx, &duplicate)
+		assert.True(tt, errors.Is(err, errs.ErrConflict), "%v", err)
+
+		second.Email = first.Email
+		err = repository.Update(ctx, second.ID, second)
+		assert.True(tt, errors.Is(err, errs.ErrConflict), "%v", err)
+	})
+
+	t.Run("Update", func(tt *testing.T) {
+		repository := newRepository(tt)
+		user := create(tt, repository, NewUser("daniel.delapava"))
+
+		user.FirstName = "Rebecca"
+		user.LastName = "Romero"
+		user.Email = "rebecca.romero@jikkosoft.com"
+		user.Picture = "https://placekitten.com/g/200/200"
+		assert.NoError(tt, repository.Update(ctx, user.ID, user))
+
+		stored, err := repository.GetOne(ctx, user.ID)
+		assert.NoError(tt, err)
+		assert.Equal(tt, "Rebecca", stored.FirstName)
+		assert.Equal(tt, "Romero", stored.LastName)
+		assert.Equal(tt, user.Email, stored.Email)
+		assert.Equal(tt, user.Picture, stored.Picture)
+		assert.Equal(tt, "daniel.delapava", stored.Username)
+	})
+
+	t.Run("Delete", func(tt *testing.T) {
+		repository := newRepository(tt)
+		user := create(tt, repository, NewUser("daniel.delapava"))
+
+		assert.NoError(tt, repository.Delete(ctx, user.ID))
+
+		_, err := repository.GetOne(ctx, user.ID)
+		assert.True(tt, errors.Is(err, errs.ErrNotFound), "%v", err)
+	})
+
+	t.Run("Get All Ordered And Paginated", func(tt *testing.T) {
+		repository := newRepository(tt)
+
+		var created []uint
+		for i := 0; i < 3; i++ {
+			created = append(created, create(tt, repository, NewUser(fmt.Sprintf("user.%d", i))).ID)
+		}
+
+		first, info, err := repository.GetAllUser(ctx, page.Request{Limit: 2, Order: page.Desc})
+		assert.NoError(tt, err)
+		assert.Equal(tt, []uint{created[2], created[1]}, ids(first))
+		assert.Empty(tt, info.PrevCursor)
+		for _, u := range first {
+			assert.Empty(tt, u.PasswordHash)
+		}
+
+		next, err := page.DecodeCursor(info.NextCursor)
+		if !assert.NoError(tt, err) {
+			return
+		}
+
+		second, info, err := repository.GetAllUser(ctx, page.Request{Limit: 2, Order: page.Desc, Cursor: &next})
+		assert.NoError(tt, err)
+		assert.Equal(tt, []uint{created[0]}, ids(second))
+		assert.Empty(tt, info.NextCursor)
+
+		prev, err := page.DecodeCursor(info.PrevCursor)
+		if !assert.NoError(tt, err) {
+			return
+		}
+
+		back, info, err := repository.GetAllUser(ctx, page.Request{Limit: 2, Order: page.Desc, Cursor: &prev})
+		assert.NoError(tt, err)
+		assert.Equal(tt, []uint{created[2], created[1]}, ids(back))
+		assert.Empty(tt, info.PrevCursor)
+
+		ascending, _, err := repository.GetAllUser(ctx, page.Request{Limit: 10, Order: page.Asc})
+		assert.NoError(tt, err)
+		assert.Equal(tt, created, ids(ascending))
+	})
+
+	t.Run("Get All Empty", func(tt *testing.T) {
+		repository := newRepository(tt)
+
+		users, info, err := repository.GetAllUser(ctx, page.Request{Limit: 2, Order: page.Desc})
+		assert.NoError(tt, err)
+		assert.Empty(tt, users)
+		assert.Equal(tt, page.Info{}, info)
+	})
+}
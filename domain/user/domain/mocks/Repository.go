@@ -4,6 +4,7 @@ package mocks
 
 import (
 	context "context"
+	"microblog/domain/shared/page"
 	"microblog/domain/user/domain"
 	"time"
 
@@ -70,22 +71,50 @@ func (_m *Repository) Delete(ctx context.Context, id uint) error {
 	return r0
 }
 
-// GetAll provides a mock function with given fields: ctx
-func (_m *Repository) GetAllUser(ctx context.Context) ([]domain.User, error) {
-	ret := _m.Called(ctx)
+// GetAllUser provides a mock function with given fields: ctx, p
+func (_m *Repository) GetAllUser(ctx context.Context, p page.Request) ([]domain.User, page.Info, error) {
+	ret := _m.Called(ctx, p)
 
 	var r0 = dataMockUSer()
-	if rf, ok := ret.Get(0).(func(context.Context) []domain.User); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, page.Request) []domain.User); ok {
+		r0 = rf(ctx, p)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.User)
 		}
 	}
 
+	var r1 page.Info
+	if rf, ok := ret.Get(1).(func(context.Context, page.Request) page.Info); ok {
+		r1 = rf(ctx, p)
+	} else {
+		r1 = ret.Get(1).(page.Info)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, page.Request) error); ok {
+		r2 = rf(ctx, p)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetByEmail provides a mock function with given fields: ctx, email
+func (_m *Repository) GetByEmail(ctx context.Context, email string) (domain.User, error) {
+	ret := _m.Called(ctx, email)
+
+	var r0 domain.User
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.User); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// RefreshToken is a long-lived credential used to obtain new access tokens.
+// Tokens issued from the same login share a FamilyID, so a rotated token
+// that is presented again can revoke the whole session.
+type RefreshToken struct {
+	ID        uint       `json:"id,omitempty"`
+	UserID    uint       `json:"user_id,omitempty"`
+	FamilyID  string     `json:"family_id,omitempty"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at,omitempty"`
+}
+
+// Revoked reports whether the token has been revoked.
+func (t RefreshToken) Revoked() bool {
+	return t.RevokedAt != nil
+}
+
+// Expired reports whether the token is expired at the given time. Both
+// times are compared in UTC, the zone they are stored in.
+func (t RefreshToken) Expired(now time.Time) bool {
+	return !now.UTC().Before(t.ExpiresAt.UTC())
+}
@@ -1,13 +1,28 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"microblog/domain/shared/page"
+)
 
 // Repository handle the CRUD operations with Users.
 type Repository interface {
-	GetAllUser(ctx context.Context) ([]User, error)
+	GetAllUser(ctx context.Context, p page.Request) ([]User, page.Info, error)
 	GetOne(ctx context.Context, id uint) (User, error)
 	GetByUsername(ctx context.Context, username string) (User, error)
+	GetByEmail(ctx context.Context, email string) (User, error)
 	Create(ctx context.Context, user *User) error
 	Update(ctx context.Context, id uint, user User) error
 	Delete(ctx context.Context, id uint) error
 }
+
+// RefreshTokenRepository handle the persistence of refresh tokens.
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *RefreshToken) error
+	GetByHash(ctx context.Context, hash string) (RefreshToken, error)
+	// Revoke revokes a token by id and reports whether this call revoked it,
+	// which is false when the token was already revoked.
+	Revoke(ctx context.Context, id uint) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeAllByUser(ctx context.Context, userID uint) error
+}
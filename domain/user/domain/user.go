@@ -1,14 +1,21 @@
 package domain
 
 import (
-	"errors"
 	"github.com/badoux/checkmail"
+	"microblog/domain/shared/errs"
+	"microblog/domain/shared/page"
 	"strings"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
+// Roles a user can have in the system.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 // User of the system.
 type User struct {
 	ID           uint      `json:"id,omitempty"`
@@ -19,10 +26,26 @@ type User struct {
 	Picture      string    `json:"picture,omitempty"`
 	Password     string    `json:"password,omitempty"`
 	PasswordHash string    `json:"-"`
+	Role         string    `json:"role,omitempty"`
 	CreatedAt    time.Time `json:"created_at,omitempty"`
 	UpdatedAt    time.Time `json:"updated_at,omitempty"`
 }
 
+// Profile is the public projection of a user: what other users see of it,
+// without its email, role or password.
+type Profile struct {
+	ID        uint   `json:"id"`
+	FirstName string `json:"first_name,omitempty"`
+	LastName  string `json:"last_name,omitempty"`
+	Username  string `json:"username"`
+	Picture   string `json:"picture,omitempty"`
+}
+
+// Profile returns the public projection of the user.
+func (u User) Profile() Profile {
+	return Profile{ID: u.ID, FirstName: u.FirstName, LastName: u.LastName, Username: u.Username, Picture: u.Picture}
+}
+
 // HashPassword generates a hash of the password and places the result in PasswordHash.
 func (u *User) HashPassword() error {
 	passwordHash, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.DefaultCost)
@@ -42,48 +65,39 @@ func (u User) PasswordMatch(password string) bool {
 	return err == nil
 }
 
-// Validate is the validation method for mandatory fields
+// IsAdmin reports whether the user has the administrator role.
+func (u User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
+// Cursor returns the position of the user in a list ordered by creation.
+func (u User) Cursor() page.Cursor {
+	return page.Cursor{CreatedAt: u.CreatedAt, ID: u.ID}
+}
+
+// Validate is the validation method for mandatory fields. It reports every
+// invalid field at once.
 func (u *User) Validate(action string) error {
 
 	const msgErrorPass = "required password"
 	const msgErrorEmail = "required Email"
 	const msgErrorEmailRequired = "invalid email"
 
-	switch strings.ToLower(action) {
-
-	case "login":
-		if u.Password == "" {
-			return errors.New(msgErrorPass)
-		}
-
-		if u.Email == "" {
-			return errors.New(msgErrorEmail)
-		}
-
-		if err := checkmail.ValidateFormat(u.Email); err != nil {
-			return errors.New(msgErrorEmailRequired)
-		}
-
-		return nil
+	var fields []errs.FieldError
 
-	default:
-		if u.Username == "" {
-			return errors.New("required nickname")
-		}
-
-		if u.Password == "" {
-			return errors.New(msgErrorPass)
-		}
-
-		if u.Email == "" {
-			return errors.New(msgErrorEmail)
-		}
+	if strings.ToLower(action) != "login" && u.Username == "" {
+		fields = append(fields, errs.FieldError{Field: "username", Message: "required nickname"})
+	}
 
-		if err := checkmail.ValidateFormat(u.Email); err != nil {
-			return errors.New(msgErrorEmailRequired)
-		}
+	if u.Password == "" {
+		fields = append(fields, errs.FieldError{Field: "password", Message: msgErrorPass})
+	}
 
-		return nil
+	if u.Email == "" {
+		fields = append(fields, errs.FieldError{Field: "email", Message: msgErrorEmail})
+	} else if err := checkmail.ValidateFormat(u.Email); err != nil {
+		fields = append(fields, errs.FieldError{Field: "email", Message: msgErrorEmailRequired})
 	}
 
+	return errs.Invalid(fields...)
 }
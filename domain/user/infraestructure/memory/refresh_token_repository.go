@@ -0,0 +1,116 @@
+package memory
+
+import (
+	"context"
+	"microblog/domain/shared/errs"
+	"microblog/domain/user/domain"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrTokenHashTaken is returned when a refresh token with the same hash exists.
+	ErrTokenHashTaken = errs.Conflict("refresh token already exists")
+
+	// errTokenNotFound is returned when no refresh token matches.
+	errTokenNotFound = errs.NotFound("refresh token not found")
+)
+
+// RefreshTokenRepository keeps the refresh tokens in memory. It is safe for
+// concurrent use.
+type RefreshTokenRepository struct {
+	mu     sync.Mutex
+	lastID uint
+	tokens map[uint]domain.RefreshToken
+}
+
+// NewRefreshTokenRepository returns an empty RefreshTokenRepository.
+func NewRefreshTokenRepository() *RefreshTokenRepository {
+	return &RefreshTokenRepository{tokens: make(map[uint]domain.RefreshToken)}
+}
+
+// Create adds a new refresh token.
+func (rr *RefreshTokenRepository) Create(ctx context.Context, token *domain.RefreshToken) error {
+	now := time.Now().Truncate(time.Microsecond)
+
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	for _, t := range rr.tokens {
+		if t.TokenHash == token.TokenHash {
+			return ErrTokenHashTaken
+		}
+	}
+
+	rr.lastID++
+	token.ID = rr.lastID
+	token.CreatedAt = now
+
+	stored := *token
+	stored.RevokedAt = nil
+	rr.tokens[stored.ID] = stored
+
+	return nil
+}
+
+// GetByHash returns one refresh token by its hash.
+func (rr *RefreshTokenRepository) GetByHash(ctx context.Context, hash string) (domain.RefreshToken, error) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	for _, t := range rr.tokens {
+		if t.TokenHash == hash {
+			return t, nil
+		}
+	}
+
+	return domain.RefreshToken{}, errTokenNotFound
+}
+
+// Revoke revokes a refresh token by id and reports whether this call revoked it.
+func (rr *RefreshTokenRepository) Revoke(ctx context.Context, id uint) (bool, error) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	t, ok := rr.tokens[id]
+	if !ok || t.Revoked() {
+		return false, nil
+	}
+
+	rr.revoke(t)
+
+	return true, nil
+}
+
+// RevokeFamily revokes every active refresh token of a family.
+func (rr *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	rr.revokeWhere(func(t domain.RefreshToken) bool { return t.FamilyID == familyID })
+
+	return nil
+}
+
+// RevokeAllByUser revokes every active refresh token of a user.
+func (rr *RefreshTokenRepository) RevokeAllByUser(ctx context.Context, userID uint) error {
+	rr.revokeWhere(func(t domain.RefreshToken) bool { return t.UserID == userID })
+
+	return nil
+}
+
+// revokeWhere revokes every active refresh token that matches.
+func (rr *RefreshTokenRepository) revokeWhere(match func(t domain.RefreshToken) bool) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	for _, t := range rr.tokens {
+		if !t.Revoked() && match(t) {
+			rr.revoke(t)
+		}
+	}
+}
+
+// revoke stores the token as revoked now. The caller must hold the lock.
+func (rr *RefreshTokenRepository) revoke(t domain.RefreshToken) {
+	now := time.Now()
+	t.RevokedAt = &now
+	rr.tokens[t.ID] = t
+}
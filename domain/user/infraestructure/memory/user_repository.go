@@ -0,0 +1,193 @@
+package memory
+
+import (
+	"context"
+	"microblog/domain/shared/errs"
+	"microblog/domain/shared/page"
+	"microblog/domain/user/domain"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrUsernameTaken is returned when another user already has the username.
+	ErrUsernameTaken = errs.Conflict("username already exists")
+
+	// ErrEmailTaken is returned when another user already has the email.
+	ErrEmailTaken = errs.Conflict("email already exists")
+
+	// errUserNotFound is returned when no user matches.
+	errUserNotFound = errs.NotFound("user not found")
+)
+
+// UserRepository keeps the users in memory. It is safe for concurrent use.
+type UserRepository struct {
+	mu     sync.RWMutex
+	lastID uint
+	users  map[uint]domain.User
+}
+
+// NewUserRepository returns an empty UserRepository.
+func NewUserRepository() *UserRepository {
+	return &UserRepository{users: make(map[uint]domain.User)}
+}
+
+// GetAllUser returns a page of users.
+func (ur *UserRepository) GetAllUser(ctx context.Context, p page.Request) ([]domain.User, page.Info, error) {
+	ur.mu.RLock()
+	defer ur.mu.RUnlock()
+
+	users := make([]domain.User, 0, len(ur.users))
+	for _, u := range ur.users {
+		users = append(users, public(u))
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Cursor().Before(users[j].Cursor(), p.Order)
+	})
+
+	from, to, info := p.Slice(len(users), func(i int) page.Cursor {
+		return users[i].Cursor()
+	})
+
+	return users[from:to], info, nil
+}
+
+// GetOne returns one user by id.
+func (ur *UserRepository) GetOne(ctx context.Context, id uint) (domain.User, error) {
+	ur.mu.RLock()
+	defer ur.mu.RUnlock()
+
+	u, ok := ur.users[id]
+	if !ok {
+		return domain.User{}, errUserNotFound
+	}
+
+	u.PasswordHash = ""
+
+	return u, nil
+}
+
+// GetByUsername returns one user by username.
+func (ur *UserRepository) GetByUsername(ctx context.Context, username string) (domain.User, error) {
+	return ur.find(func(u domain.User) bool { return u.Username == username })
+}
+
+// GetByEmail returns one user by email.
+func (ur *UserRepository) GetByEmail(ctx context.Context, email string) (domain.User, error) {
+	return ur.find(func(u domain.User) bool { return u.Email == email })
+}
+
+// Create adds a new user.
+func (ur *UserRepository) Create(ctx context.Context, user *domain.User) error {
+	now := time.Now().Truncate(time.Microsecond)
+
+	if user.Picture == "" {
+		user.Picture = "https://placekitten.com/g/300/300"
+	}
+
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	if err := ur.unique(0, user.Username, user.Email); err != nil {
+		return err
+	}
+
+	ur.lastID++
+
+	stored := *user
+	stored.ID = ur.lastID
+	stored.Password = ""
+	stored.CreatedAt = now
+	stored.UpdatedAt = now
+	if stored.Role == "" {
+		stored.Role = domain.RoleUser
+	}
+
+	ur.users[stored.ID] = stored
+	user.ID = stored.ID
+
+	return nil
+}
+
+// Update updates a user by id.
+func (ur *UserRepository) Update(ctx context.Context, id uint, u domain.User) error {
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	stored, ok := ur.users[id]
+	if !ok {
+		return errUserNotFound
+	}
+
+	if err := ur.unique(id, "", u.Email); err != nil {
+		return err
+	}
+
+	stored.FirstName = u.FirstName
+	stored.LastName = u.LastName
+	stored.Email = u.Email
+	stored.Picture = u.Picture
+	stored.UpdatedAt = time.Now().Truncate(time.Microsecond)
+	ur.users[id] = stored
+
+	return nil
+}
+
+// Delete removes a user by id.
+func (ur *UserRepository) Delete(ctx context.Context, id uint) error {
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	if _, ok := ur.users[id]; !ok {
+		return errUserNotFound
+	}
+
+	delete(ur.users, id)
+
+	return nil
+}
+
+// find returns the first user that matches, along with its password hash
+// and role as the lookups used to log in do.
+func (ur *UserRepository) find(match func(u domain.User) bool) (domain.User, error) {
+	ur.mu.RLock()
+	defer ur.mu.RUnlock()
+
+	for _, u := range ur.users {
+		if match(u) {
+			return u, nil
+		}
+	}
+
+	return domain.User{}, errUserNotFound
+}
+
+// unique checks that no user other than id already has the username or the
+// email. Empty values are not checked.
+func (ur *UserRepository) unique(id uint, username, email string) error {
+	for _, u := range ur.users {
+		if u.ID == id {
+			continue
+		}
+
+		if username != "" && u.Username == username {
+			return ErrUsernameTaken
+		}
+
+		if email != "" && u.Email == email {
+			return ErrEmailTaken
+		}
+	}
+
+	return nil
+}
+
+// public returns the user without the fields the listing does not expose.
+func public(u domain.User) domain.User {
+	u.PasswordHash = ""
+	u.Role = ""
+
+	return u
+}
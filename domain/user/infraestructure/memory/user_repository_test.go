@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"microblog/domain/shared/page"
+	"microblog/domain/user/domain"
+	"microblog/domain/user/domain/contract"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// dataUser is data for test
+func dataUser(n int) domain.User {
+	return domain.User{
+		FirstName:    "Daniel",
+		LastName:     "De La Pava Suarez",
+		Username:     fmt.Sprintf("daniel.delapava.%d", n),
+		Email:        fmt.Sprintf("daniel.delapava.%d@jikkosoft.com", n),
+		PasswordHash: "hash",
+	}
+}
+
+func TestUserRepository_Create(t *testing.T) {
+
+	t.Run("Concurrent Creates Get Unique IDs", func(tt *testing.T) {
+		repository := NewUserRepository()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func(n int) {
+				defer wg.Done()
+
+				u := dataUser(n)
+				assert.NoError(tt, repository.Create(context.Background(), &u))
+			}(i)
+		}
+		wg.Wait()
+
+		users, _, err := repository.GetAllUser(context.Background(), page.Request{Limit: page.MaxLimit})
+		assert.NoError(tt, err)
+		assert.Len(tt, users, 50)
+
+		ids := make(map[uint]bool)
+		for _, u := range users {
+			ids[u.ID] = true
+			assert.Empty(tt, u.PasswordHash)
+		}
+		assert.Len(tt, ids, 50)
+	})
+
+	t.Run("Error Duplicate Username And Email", func(tt *testing.T) {
+		repository := NewUserRepository()
+
+		u := dataUser(1)
+		assert.NoError(tt, repository.Create(context.Background(), &u))
+		assert.Equal(tt, uint(1), u.ID)
+
+		sameUsername := dataUser(1)
+		sameUsername.Email = "other@jikkosoft.com"
+		assert.Equal(tt, ErrUsernameTaken, repository.Create(context.Background(), &sameUsername))
+
+		sameEmail := dataUser(1)
+		sameEmail.Username = "other"
+		assert.Equal(tt, ErrEmailTaken, repository.Create(context.Background(), &sameEmail))
+
+		other := dataUser(2)
+		assert.NoError(tt, repository.Create(context.Background(), &other))
+		other.Email = u.Email
+		assert.Equal(tt, ErrEmailTaken, repository.Update(context.Background(), other.ID, other))
+	})
+}
+
+func TestUserRepository_Contract(t *testing.T) {
+	contract.TestRepository(t, func(t *testing.T) domain.Repository {
+		return NewUserRepository()
+	})
+}
+
+func TestRefreshTokenRepository_Contract(t *testing.T) {
+	contract.TestRefreshTokenRepository(t, func(t *testing.T) contract.RefreshTokenHarness {
+		users := NewUserRepository()
+
+		return contract.RefreshTokenHarness{
+			Repository: NewRefreshTokenRepository(),
+			NewUser:    newUser(users),
+		}
+	})
+}
+
+// newUser returns a function that stores a new user in users and returns its id.
+func newUser(users *UserRepository) func(t *testing.T) uint {
+	n := 0
+
+	return func(t *testing.T) uint {
+		n++
+		u := contract.NewUser(fmt.Sprintf("user.%d", n))
+		if err := users.Create(context.Background(), &u); err != nil {
+			t.Fatalf("error creating user: %v", err)
+		}
+
+		return u.ID
+	}
+}
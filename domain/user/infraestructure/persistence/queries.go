@@ -2,15 +2,20 @@ package persistence
 
 const(
 
-	// selectAllUser is a query that selects all rows in the user table
-	selectAllUser = "SELECT id, first_name, last_name, username, email, picture, created_at, updated_at FROM users;"
+	// selectAllUser is a query that selects a page of rows in the user table. It must be
+	// formatted with the comparison operator and the sort direction returned by page.Request.Seek.
+	selectAllUser = "SELECT id, first_name, last_name, username, email, picture, created_at, updated_at FROM users " +
+		"WHERE ($1::timestamp IS NULL OR (created_at, id) %s ($1::timestamp, $2)) ORDER BY created_at %s, id %s LIMIT $3;"
 
 	// selectUserById is a query that selects a row from the users table based off of the given id.
-	selectUserById = "SELECT id, first_name, last_name, username, email, picture, created_at, updated_at FROM users WHERE id = $1;"
+	selectUserById = "SELECT id, first_name, last_name, username, email, picture, role, created_at, updated_at FROM users WHERE id = $1;"
 
 	// selectUSerByUsername is a query that selects a row from the users table based off of the given username
 	selectUSerByUsername = "SELECT id, first_name, last_name, username, email, picture, password, created_at, updated_at FROM users WHERE username = $1;"
 
+	// selectUserByEmail is a query that selects a row from the users table based off of the given email
+	selectUserByEmail = "SELECT id, first_name, last_name, username, email, picture, password, role, created_at, updated_at FROM users WHERE email = $1;"
+
 	// insertUser is a query that inserts a new row in the user table using the values
 	// given in order for first_name, last_name, username, email, picture, password, created_at and updated_at.
 	insertUser = "INSERT INTO users (first_name, last_name, username, email, picture, password, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id;"
@@ -22,3 +27,22 @@ const(
 	// deleteUser is a query that deletes a row in the users table given a id.
 	deleteUser = "DELETE FROM users WHERE id=$1;"
 )
+
+const (
+
+	// insertRefreshToken is a query that inserts a new row in the refresh_tokens table using the values
+	// given in order for user_id, family_id, token_hash, expires_at and created_at.
+	insertRefreshToken = "INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at, created_at) VALUES ($1, $2, $3, $4, $5) RETURNING id;"
+
+	// selectRefreshTokenByHash is a query that selects a row from the refresh_tokens table based off of the given token hash.
+	selectRefreshTokenByHash = "SELECT id, user_id, family_id, token_hash, expires_at, revoked_at, created_at FROM refresh_tokens WHERE token_hash = $1;"
+
+	// revokeRefreshToken is a query that revokes a row in the refresh_tokens table given a id, only if it was not revoked yet.
+	revokeRefreshToken = "UPDATE refresh_tokens SET revoked_at=$1 WHERE id=$2 AND revoked_at IS NULL;"
+
+	// revokeRefreshTokenFamily is a query that revokes every active row of a token family.
+	revokeRefreshTokenFamily = "UPDATE refresh_tokens SET revoked_at=$1 WHERE family_id=$2 AND revoked_at IS NULL;"
+
+	// revokeRefreshTokenByUser is a query that revokes every active row of a user.
+	revokeRefreshTokenByUser = "UPDATE refresh_tokens SET revoked_at=$1 WHERE user_id=$2 AND revoked_at IS NULL;"
+)
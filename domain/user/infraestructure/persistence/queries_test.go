@@ -2,19 +2,27 @@ package persistence
 
 const(
 
-	// selectAllUsertest is a query that selects all rows in the user table
-	selectAllUsertest = "SELECT id, first_name, last_name, username, email, picture, created_at, updated_at FROM users;"
+	// selectAllUsertest is a query that selects the first page of rows in the user table, newest first.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	selectAllUsertest = "SELECT id, first_name, last_name, username, email, picture, created_at, updated_at FROM users " +
+		"WHERE \\(\\$1::timestamp IS NULL OR \\(created_at, id\\) < \\(\\$1::timestamp, \\$2\\)\\) ORDER BY created_at DESC, id DESC LIMIT \\$3;"
 
 	// selectUserByIdTest is a query that selects a row from the users table based off of the given id.
 	// You must escape the code and to escape the code use
 	// https://regex-escape.com/preg_quote-online.php
-	selectUserByIdTest = "SELECT id, first_name, last_name, username, email, picture, created_at, updated_at FROM users WHERE id \\= \\$1;"
+	selectUserByIdTest = "SELECT id, first_name, last_name, username, email, picture, role, created_at, updated_at FROM users WHERE id \\= \\$1;"
 
 	// selectUSerByUsernameTest is a query that selects a row from the users table based off of the given username.
 	// You must escape the code and to escape the code use
 	// https://regex-escape.com/preg_quote-online.php
 	selectUSerByUsernameTest = "SELECT id, first_name, last_name, username, email, picture, password, created_at, updated_at FROM users WHERE username \\= \\$1;"
 
+	// selectUserByEmailTest is a query that selects a row from the users table based off of the given email.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	selectUserByEmailTest = "SELECT id, first_name, last_name, username, email, picture, password, role, created_at, updated_at FROM users WHERE email \\= \\$1;"
+
 	// insertUserTest is a query test that inserts a new row in the user table using the values
 	// for insert queries. You must escape the code and to escape the code use
 	// https://regex-escape.com/preg_quote-online.php
@@ -30,3 +38,31 @@ const(
 	// https://regex-escape.com/preg_quote-online.php
 	deleteUserTest = "DELETE FROM users WHERE id\\=\\$1;"
 )
+
+const (
+
+	// insertRefreshTokenTest is a query test that inserts a new row in the refresh_tokens table.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	insertRefreshTokenTest = "INSERT INTO refresh_tokens \\(user_id, family_id, token_hash, expires_at, created_at\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5\\) RETURNING id;"
+
+	// selectRefreshTokenByHashTest is a query that selects a row from the refresh_tokens table based off of the given token hash.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	selectRefreshTokenByHashTest = "SELECT id, user_id, family_id, token_hash, expires_at, revoked_at, created_at FROM refresh_tokens WHERE token_hash \\= \\$1;"
+
+	// revokeRefreshTokenTest is a query that revokes a row in the refresh_tokens table given a id.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	revokeRefreshTokenTest = "UPDATE refresh_tokens SET revoked_at\\=\\$1 WHERE id\\=\\$2 AND revoked_at IS NULL;"
+
+	// revokeRefreshTokenFamilyTest is a query that revokes every active row of a token family.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	revokeRefreshTokenFamilyTest = "UPDATE refresh_tokens SET revoked_at\\=\\$1 WHERE family_id\\=\\$2 AND revoked_at IS NULL;"
+
+	// revokeRefreshTokenByUserTest is a query that revokes every active row of a user.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	revokeRefreshTokenByUserTest = "UPDATE refresh_tokens SET revoked_at\\=\\$1 WHERE user_id\\=\\$2 AND revoked_at IS NULL;"
+)
@@ -0,0 +1,107 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"microblog/domain/user/domain"
+	"time"
+
+	conn "microblog/infrastructure/database"
+)
+
+// RefreshTokenRepository manages the operations with the database that
+// correspond to the refresh token model. The times are written in UTC, as
+// the columns keep no time zone and are read back as UTC.
+type RefreshTokenRepository struct {
+	Data *conn.Data
+}
+
+// Create adds a new refresh token.
+func (rr *RefreshTokenRepository) Create(ctx context.Context, token *domain.RefreshToken) error {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	stmt, err := rr.Data.DB.PrepareContext(ctx, insertRefreshToken)
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+
+	row := stmt.QueryRowContext(ctx, token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt.UTC(), now)
+
+	err = row.Scan(&token.ID)
+	if err != nil {
+		return conn.Error(err, errUserNotFound)
+	}
+
+	token.CreatedAt = now
+
+	return nil
+}
+
+// GetByHash returns one refresh token by its hash.
+func (rr *RefreshTokenRepository) GetByHash(ctx context.Context, hash string) (domain.RefreshToken, error) {
+	row := rr.Data.DB.QueryRowContext(ctx, selectRefreshTokenByHash, hash)
+
+	var token domain.RefreshToken
+	var revokedAt sql.NullTime
+	err := row.Scan(&token.ID, &token.UserID, &token.FamilyID, &token.TokenHash, &token.ExpiresAt, &revokedAt, &token.CreatedAt)
+	if err != nil {
+		return domain.RefreshToken{}, conn.Error(err, "refresh token not found")
+	}
+
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+
+	return token, nil
+}
+
+// Revoke revokes a refresh token by id and reports whether this call revoked it.
+func (rr *RefreshTokenRepository) Revoke(ctx context.Context, id uint) (bool, error) {
+	stmt, err := rr.Data.DB.PrepareContext(ctx, revokeRefreshToken)
+	if err != nil {
+		return false, err
+	}
+
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, time.Now().UTC(), id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// RevokeFamily revokes every active refresh token of a family.
+func (rr *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	return rr.exec(ctx, revokeRefreshTokenFamily, time.Now().UTC(), familyID)
+}
+
+// RevokeAllByUser revokes every active refresh token of a user.
+func (rr *RefreshTokenRepository) RevokeAllByUser(ctx context.Context, userID uint) error {
+	return rr.exec(ctx, revokeRefreshTokenByUser, time.Now().UTC(), userID)
+}
+
+// exec prepares and executes a statement that does not return rows.
+func (rr *RefreshTokenRepository) exec(ctx context.Context, query string, args ...interface{}) error {
+	stmt, err := rr.Data.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package persistence
+
+import (
+	"context"
+	"database/sql/driver"
+	"microblog/domain/user/domain"
+	dataDB "microblog/infrastructure/database"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// utcTime matches a time argument in UTC, the zone the columns keep.
+type utcTime struct{}
+
+// Match reports whether v is a time in UTC.
+func (utcTime) Match(v driver.Value) bool {
+	t, ok := v.(time.Time)
+	return ok && t.Location() == time.UTC
+}
+
+// newRefreshTokenRepository returns a RefreshTokenRepository over the mock connection.
+func newRefreshTokenRepository() (*RefreshTokenRepository, sqlmock.Sqlmock) {
+	mock := NewMockUser()
+	return &RefreshTokenRepository{Data: &dataDB.Data{DB: dbMockUsers}}, mock
+}
+
+func TestRefreshTokenRepository_Create(t *testing.T) {
+	repository, mock := newRefreshTokenRepository()
+	defer CloseMockUser()
+
+	expiresAt := time.Now().In(time.FixedZone("UTC-5", -5*60*60))
+	token := &domain.RefreshToken{UserID: 1, FamilyID: "family", TokenHash: "hash", ExpiresAt: expiresAt}
+
+	prep := mock.ExpectPrepare(insertRefreshTokenTest)
+	prep.ExpectQuery().
+		WithArgs(token.UserID, token.FamilyID, token.TokenHash, expiresAt.UTC(), utcTime{}).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := repository.Create(ctx, token)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), token.ID)
+}
+
+func TestRefreshTokenRepository_GetByHash(t *testing.T) {
+	repository, mock := newRefreshTokenRepository()
+	defer CloseMockUser()
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "user_id", "family_id", "token_hash", "expires_at", "revoked_at", "created_at"}).
+		AddRow(1, 1, "family", "hash", now, now, now)
+
+	mock.ExpectQuery(selectRefreshTokenByHashTest).WithArgs("hash").WillReturnRows(rows)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	token, err := repository.GetByHash(ctx, "hash")
+	assert.NoError(t, err)
+	assert.Equal(t, "family", token.FamilyID)
+	assert.True(t, token.Revoked())
+}
+
+func TestRefreshTokenRepository_Revoke(t *testing.T) {
+
+	t.Run("Already Revoked", func(tt *testing.T) {
+		repository, mock := newRefreshTokenRepository()
+		defer CloseMockUser()
+
+		prep := mock.ExpectPrepare(revokeRefreshTokenTest)
+		prep.ExpectExec().WithArgs(utcTime{}, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		revoked, err := repository.Revoke(context.Background(), 1)
+		assert.NoError(tt, err)
+		assert.False(tt, revoked)
+	})
+
+	t.Run("Revoke Successful", func(tt *testing.T) {
+		repository, mock := newRefreshTokenRepository()
+		defer CloseMockUser()
+
+		prep := mock.ExpectPrepare(revokeRefreshTokenTest)
+		prep.ExpectExec().WithArgs(utcTime{}, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		revoked, err := repository.Revoke(context.Background(), 1)
+		assert.NoError(tt, err)
+		assert.True(tt, revoked)
+	})
+}
+
+func TestRefreshTokenRepository_RevokeFamily(t *testing.T) {
+	repository, mock := newRefreshTokenRepository()
+	defer CloseMockUser()
+
+	prep := mock.ExpectPrepare(revokeRefreshTokenFamilyTest)
+	prep.ExpectExec().WithArgs(utcTime{}, "family").WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err := repository.RevokeFamily(context.Background(), "family")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshTokenRepository_RevokeAllByUser(t *testing.T) {
+	repository, mock := newRefreshTokenRepository()
+	defer CloseMockUser()
+
+	prep := mock.ExpectPrepare(revokeRefreshTokenByUserTest)
+	prep.ExpectExec().WithArgs(utcTime{}, 1).WillReturnResult(sqlmock.NewResult(0, 3))
+
+	err := repository.RevokeAllByUser(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
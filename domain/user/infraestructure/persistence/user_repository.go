@@ -2,65 +2,130 @@ package persistence
 
 import (
 	"context"
+	"fmt"
+	"microblog/domain/shared/page"
 	"microblog/domain/user/domain"
 	"time"
 
 	conn "microblog/infrastructure/database"
+	"microblog/infrastructure/tracing"
 )
 
+// errUserNotFound is the message of the error returned when no user matches.
+const errUserNotFound = "user not found"
+
 // UserRepository manages the operations with the database that correspond to the user model.
 type UserRepository struct {
 	Data *conn.Data
 }
 
-// GetAll returns all users.
-func (ur *UserRepository) GetAllUser(ctx context.Context) ([]domain.User, error) {
-	rows, err := ur.Data.DB.QueryContext(ctx, selectAllUser)
+// GetAllUser returns a page of users.
+func (ur *UserRepository) GetAllUser(ctx context.Context, p page.Request) (users []domain.User, info page.Info, err error) {
+	ctx, span := tracing.Query(ctx, "UserRepository.GetAllUser", "selectAllUser")
+	defer span.End(&err)
+
+	op, order := p.Seek()
+	after, afterID := p.Position()
+
+	rows, err := ur.Data.DB.QueryContext(ctx, fmt.Sprintf(selectAllUser, op, order, order), after, afterID, p.Fetch())
 	if err != nil {
-		return nil, err
+		return nil, page.Info{}, err
 	}
 
 	defer rows.Close()
 
-	var users []domain.User
 	for rows.Next() {
 		var userRow domain.User
-		_ = rows.Scan(&userRow.ID, &userRow.FirstName, &userRow.LastName, &userRow.Username, &userRow.Email, &userRow.Picture, &userRow.CreatedAt, &userRow.UpdatedAt)
+		err = rows.Scan(&userRow.ID, &userRow.FirstName, &userRow.LastName, &userRow.Username, &userRow.Email, &userRow.Picture, &userRow.CreatedAt, &userRow.UpdatedAt)
+		if err != nil {
+			return nil, page.Info{}, err
+		}
+
 		users = append(users, userRow)
 	}
 
-	return users, nil
+	if err = rows.Err(); err != nil {
+		return nil, page.Info{}, err
+	}
+
+	span.Rows(len(users))
+
+	keep, more := p.Trim(len(users))
+	users = users[:keep]
+	if keep == 0 {
+		return users, page.Info{}, nil
+	}
+
+	if p.Backward() {
+		for i, j := 0, keep-1; i < j; i, j = i+1, j-1 {
+			users[i], users[j] = users[j], users[i]
+		}
+	}
+
+	return users, p.Info(users[0].Cursor(), users[keep-1].Cursor(), more), nil
 }
 
 // GetOne returns one user by id.
-func (ur *UserRepository) GetOne(ctx context.Context, id uint) (domain.User, error) {
+func (ur *UserRepository) GetOne(ctx context.Context, id uint) (user domain.User, err error) {
+	ctx, span := tracing.Query(ctx, "UserRepository.GetOne", "selectUserById")
+	defer span.End(&err)
+
 	row := ur.Data.DB.QueryRowContext(ctx, selectUserById, id)
 
 	var userScan domain.User
-	err := row.Scan(&userScan.ID, &userScan.FirstName, &userScan.LastName, &userScan.Username, &userScan.Email, &userScan.Picture, &userScan.CreatedAt, &userScan.UpdatedAt)
+	err = row.Scan(&userScan.ID, &userScan.FirstName, &userScan.LastName, &userScan.Username, &userScan.Email, &userScan.Picture, &userScan.Role, &userScan.CreatedAt, &userScan.UpdatedAt)
 	if err != nil {
-		return domain.User{}, err
+		return domain.User{}, conn.Error(err, errUserNotFound)
 	}
 
+	span.Rows(1)
+
 	return userScan, nil
 }
 
 // GetByUsername returns one user by username.
-func (ur *UserRepository) GetByUsername(ctx context.Context, username string) (domain.User, error) {
+func (ur *UserRepository) GetByUsername(ctx context.Context, username string) (user domain.User, err error) {
+	ctx, span := tracing.Query(ctx, "UserRepository.GetByUsername", "selectUSerByUsername")
+	defer span.End(&err)
+
 	row := ur.Data.DB.QueryRowContext(ctx, selectUSerByUsername, username)
 
 	var userScan domain.User
-	err := row.Scan(&userScan.ID, &userScan.FirstName, &userScan.LastName, &userScan.Username,
+	err = row.Scan(&userScan.ID, &userScan.FirstName, &userScan.LastName, &userScan.Username,
 		&userScan.Email, &userScan.Picture, &userScan.PasswordHash, &userScan.CreatedAt, &userScan.UpdatedAt)
 	if err != nil {
-		return domain.User{}, err
+		return domain.User{}, conn.Error(err, errUserNotFound)
 	}
 
+	span.Rows(1)
+
+	return userScan, nil
+}
+
+// GetByEmail returns one user by email.
+func (ur *UserRepository) GetByEmail(ctx context.Context, email string) (user domain.User, err error) {
+	ctx, span := tracing.Query(ctx, "UserRepository.GetByEmail", "selectUserByEmail")
+	defer span.End(&err)
+
+	row := ur.Data.DB.QueryRowContext(ctx, selectUserByEmail, email)
+
+	var userScan domain.User
+	err = row.Scan(&userScan.ID, &userScan.FirstName, &userScan.LastName, &userScan.Username,
+		&userScan.Email, &userScan.Picture, &userScan.PasswordHash, &userScan.Role, &userScan.CreatedAt, &userScan.UpdatedAt)
+	if err != nil {
+		return domain.User{}, conn.Error(err, errUserNotFound)
+	}
+
+	span.Rows(1)
+
 	return userScan, nil
 }
 
 // Create adds a new user.
-func (ur *UserRepository) Create(ctx context.Context, user *domain.User) error {
+func (ur *UserRepository) Create(ctx context.Context, user *domain.User) (err error) {
+	ctx, span := tracing.Query(ctx, "UserRepository.Create", "insertUser")
+	defer span.End(&err)
+
 	now := time.Now().Truncate(time.Second).Truncate(time.Millisecond).Truncate(time.Microsecond)
 
 	if user.Picture == "" {
@@ -80,14 +145,19 @@ func (ur *UserRepository) Create(ctx context.Context, user *domain.User) error {
 
 	err = row.Scan(&user.ID)
 	if err != nil {
-		return err
+		return conn.Error(err, errUserNotFound)
 	}
 
+	span.Rows(1)
+
 	return nil
 }
 
 // Update updates a user by id.
-func (ur *UserRepository) Update(ctx context.Context, id uint, u domain.User) error {
+func (ur *UserRepository) Update(ctx context.Context, id uint, u domain.User) (err error) {
+	ctx, span := tracing.Query(ctx, "UserRepository.Update", "updateUser")
+	defer span.End(&err)
+
 	now := time.Now().Truncate(time.Second).Truncate(time.Millisecond).Truncate(time.Microsecond)
 
 	stmt, err := ur.Data.DB.PrepareContext(ctx, updateUser)
@@ -97,16 +167,20 @@ func (ur *UserRepository) Update(ctx context.Context, id uint, u domain.User) er
 
 	defer stmt.Close()
 
-	_, err = stmt.ExecContext(ctx, u.FirstName, u.LastName, u.Email, u.Picture, now, id)
+	result, err := stmt.ExecContext(ctx, u.FirstName, u.LastName, u.Email, u.Picture, now, id)
 	if err != nil {
-		return err
+		return conn.Error(err, errUserNotFound)
 	}
 
-	return nil
+	span.Affected(result)
+
+	return conn.Affected(result, errUserNotFound)
 }
 
 // Delete removes a user by id.
-func (ur *UserRepository) Delete(ctx context.Context, id uint) error {
+func (ur *UserRepository) Delete(ctx context.Context, id uint) (err error) {
+	ctx, span := tracing.Query(ctx, "UserRepository.Delete", "deleteUser")
+	defer span.End(&err)
 
 	stmt, err := ur.Data.DB.PrepareContext(ctx, deleteUser)
 	if err != nil {
@@ -115,10 +189,12 @@ func (ur *UserRepository) Delete(ctx context.Context, id uint) error {
 
 	defer stmt.Close()
 
-	_, err = stmt.ExecContext(ctx, id)
+	result, err := stmt.ExecContext(ctx, id)
 	if err != nil {
-		return err
+		return conn.Error(err, errUserNotFound)
 	}
 
-	return nil
+	span.Affected(result)
+
+	return conn.Affected(result, errUserNotFound)
 }
@@ -3,10 +3,12 @@ package persistence
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 	"log"
+	"microblog/domain/shared/page"
 	"microblog/domain/user/domain"
 	dataDB "microblog/infrastructure/database"
 	"testing"
@@ -87,7 +89,43 @@ func TestUserRepository_GetAll(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		users, err := userRepositoryMock.GetAllUser(ctx)
+		users, _, err := userRepositoryMock.GetAllUser(ctx, page.Request{Limit: page.DefaultLimit})
+		assert.Error(tt, err)
+		assert.Nil(tt, users)
+	})
+
+	t.Run("Error Scan", func(tt *testing.T) {
+		mock := NewMockUser()
+		defer func() {
+			CloseMockUser()
+		}()
+
+		usersData := dataUSer()
+		rows := sqlmock.NewRows([]string{"id", "first_name", "last_name", "username", "email", "picture", "created_at", "updated_at"}).
+			AddRow("one", usersData[0].FirstName, usersData[0].LastName, usersData[0].Username, usersData[0].Email, usersData[0].Picture, usersData[0].CreatedAt, usersData[0].UpdatedAt)
+
+		mock.ExpectQuery(selectAllUsertest).WithArgs(nil, 0, 2).WillReturnRows(rows)
+
+		users, info, err := userRepositoryMock.GetAllUser(context.Background(), page.Request{Limit: 1})
+		assert.Error(tt, err)
+		assert.Nil(tt, users)
+		assert.Equal(tt, page.Info{}, info)
+	})
+
+	t.Run("Error Rows", func(tt *testing.T) {
+		mock := NewMockUser()
+		defer func() {
+			CloseMockUser()
+		}()
+
+		usersData := dataUSer()
+		rows := sqlmock.NewRows([]string{"id", "first_name", "last_name", "username", "email", "picture", "created_at", "updated_at"}).
+			AddRow(usersData[0].ID, usersData[0].FirstName, usersData[0].LastName, usersData[0].Username, usersData[0].Email, usersData[0].Picture, usersData[0].CreatedAt, usersData[0].UpdatedAt).
+			RowError(0, errors.New("connection reset"))
+
+		mock.ExpectQuery(selectAllUsertest).WithArgs(nil, 0, 2).WillReturnRows(rows)
+
+		users, _, err := userRepositoryMock.GetAllUser(context.Background(), page.Request{Limit: 1})
 		assert.Error(tt, err)
 		assert.Nil(tt, users)
 	})
@@ -103,15 +141,17 @@ func TestUserRepository_GetAll(t *testing.T) {
 			AddRow(usersData[0].ID, usersData[0].FirstName, usersData[0].LastName, usersData[0].Username, usersData[0].Email, usersData[0].Picture, usersData[0].CreatedAt, usersData[0].UpdatedAt).
 			AddRow(usersData[1].ID, usersData[1].FirstName, usersData[1].LastName, usersData[1].Username, usersData[1].Email, usersData[1].Picture, usersData[1].CreatedAt, usersData[1].UpdatedAt)
 
-		mock.ExpectQuery(selectAllUsertest).WillReturnRows(rows)
+		mock.ExpectQuery(selectAllUsertest).WithArgs(nil, 0, 2).WillReturnRows(rows)
 
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		users, err := userRepositoryMock.GetAllUser(ctx)
+		users, info, err := userRepositoryMock.GetAllUser(ctx, page.Request{Limit: 1})
 		assert.NotEmpty(tt, users)
 		assert.NoError(tt, err)
-		assert.Len(tt, users, 2)
+		assert.Len(tt, users, 1)
+		assert.NotEmpty(tt, info.NextCursor)
+		assert.Empty(tt, info.PrevCursor)
 	})
 }
 
@@ -136,8 +176,8 @@ func TestUserRepository_GetOne(t *testing.T) {
 			CloseMockUser()
 		}()
 
-		row := sqlmock.NewRows([]string{"id", "first_name", "last_name", "username", "email", "picture", "created_at", "updated_at"}).
-			AddRow(userTest.ID, userTest.FirstName, userTest.LastName, userTest.Username, userTest.Email, userTest.Picture, userTest.CreatedAt, userTest.UpdatedAt)
+		row := sqlmock.NewRows([]string{"id", "first_name", "last_name", "username", "email", "picture", "role", "created_at", "updated_at"}).
+			AddRow(userTest.ID, userTest.FirstName, userTest.LastName, userTest.Username, userTest.Email, userTest.Picture, "user", userTest.CreatedAt, userTest.UpdatedAt)
 
 		mock.ExpectQuery(selectUserByIdTest).WithArgs(nil).WillReturnRows(row)
 
@@ -155,8 +195,8 @@ func TestUserRepository_GetOne(t *testing.T) {
 			CloseMockUser()
 		}()
 
-		row := sqlmock.NewRows([]string{"id", "first_name", "last_name", "username", "email", "picture", "created_at", "updated_at"}).
-			AddRow(userTest.ID, userTest.FirstName, userTest.LastName, userTest.Username, userTest.Email, userTest.Picture, userTest.CreatedAt, userTest.UpdatedAt)
+		row := sqlmock.NewRows([]string{"id", "first_name", "last_name", "username", "email", "picture", "role", "created_at", "updated_at"}).
+			AddRow(userTest.ID, userTest.FirstName, userTest.LastName, userTest.Username, userTest.Email, userTest.Picture, "user", userTest.CreatedAt, userTest.UpdatedAt)
 
 		mock.ExpectQuery(selectUserByIdTest).WithArgs(userTest.ID).WillReturnRows(row)
 
@@ -225,6 +265,52 @@ func TestUserRepository_GetByUsername(t *testing.T) {
 
 }
 
+func TestUserRepository_GetByEmail(t *testing.T) {
+
+	usersData := dataUSer()
+	userTest := usersData[0]
+
+	t.Run("Error Scan Row", func(tt *testing.T) {
+		mock := NewMockUser()
+		defer func() {
+			CloseMockUser()
+		}()
+
+		row := sqlmock.NewRows([]string{"idt", "first_name", "last_name", "username", "email", "picture", "password", "role", "created_at", "updated_at"}).
+			AddRow(userTest.FirstName, userTest.FirstName, userTest.LastName, userTest.Username, userTest.Email, userTest.Picture, userTest.PasswordHash, "user", userTest.CreatedAt, userTest.UpdatedAt)
+
+		mock.ExpectQuery(selectUserByEmailTest).WithArgs(userTest.Email).WillReturnRows(row)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		userResult, err := userRepositoryMock.GetByEmail(ctx, userTest.Email)
+		assert.Error(tt, err)
+		assert.Empty(tt, userResult)
+	})
+
+	t.Run("Get User By Email Successful", func(tt *testing.T) {
+		mock := NewMockUser()
+		defer func() {
+			CloseMockUser()
+		}()
+
+		row := sqlmock.NewRows([]string{"id", "first_name", "last_name", "username", "email", "picture", "password", "role", "created_at", "updated_at"}).
+			AddRow(userTest.ID, userTest.FirstName, userTest.LastName, userTest.Username, userTest.Email, userTest.Picture, "hash", "admin", userTest.CreatedAt, userTest.UpdatedAt)
+
+		mock.ExpectQuery(selectUserByEmailTest).WithArgs(userTest.Email).WillReturnRows(row)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		userResult, err := userRepositoryMock.GetByEmail(ctx, userTest.Email)
+		assert.NoError(tt, err)
+		assert.Equal(tt, userTest.Email, userResult.Email)
+		assert.Equal(tt, "hash", userResult.PasswordHash)
+		assert.True(tt, userResult.IsAdmin())
+	})
+}
+
 func TestUserRepository_Create(t *testing.T) {
 
 	usersData := dataUSer()
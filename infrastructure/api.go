@@ -1,33 +1,102 @@
 package infrastructure
 
 import (
+	v1follow "microblog/domain/follow/application/v1"
+	domainFollow "microblog/domain/follow/domain"
+	v1like "microblog/domain/like/application/v1"
+	domainLike "microblog/domain/like/domain"
+	v1notification "microblog/domain/notification/application/v1"
+	domainNotification "microblog/domain/notification/domain"
 	v1post "microblog/domain/post/application/v1"
-	persistencePost "microblog/domain/post/infraestructure/persistence"
+	domainPost "microblog/domain/post/domain"
+	v1search "microblog/domain/search/application/v1"
+	"microblog/domain/shared/event"
+	"microblog/domain/shared/response"
 	v1user "microblog/domain/user/application/v1"
-	persistenceUser "microblog/domain/user/infraestructure/persistence"
+	"microblog/infrastructure/auth"
+	"microblog/infrastructure/config"
 	"net/http"
 
 	"github.com/go-chi/chi"
-	data "microblog/infrastructure/database"
 )
 
-// New returns the API V1 Handler with configuration.
-func New(conn *data.Data) http.Handler {
+// New returns the API V1 Handler with configuration. The search and the
+// notifications are only served when their feature is on.
+func New(repos Repositories, cfg config.Config) http.Handler {
 	r := chi.NewRouter()
+	r.NotFound(response.NotFound)
+	r.MethodNotAllowed(response.MethodNotAllowed)
+
+	tm := auth.NewTokenManager(cfg.Auth.Secret, auth.DefaultAccessTTL)
+
+	bus := event.NewBus()
+	if cfg.Features.Notifications {
+		notifier := &domainNotification.Notifier{
+			Repository: repos.Notifications,
+			Posts:      repos.Posts,
+		}
+		bus.Subscribe(notifier.Handle)
+	}
 
 	ur := &v1user.UserRouter{
-		Repository: &persistenceUser.UserRepository{
-			Data: conn,
-		},
+		Repository: repos.Users,
+		Follows:    repos.Follows,
+	}
+	follows := domainFollow.NewService(repos.Follows)
+	follows.Users = repos.Users
+	follows.Events = bus
+
+	likes := domainLike.NewService(repos.Likes)
+	likes.Events = bus
+
+	fr := &v1follow.FollowRouter{
+		Repository: repos.Follows,
+		Service:    follows,
+	}
+	lr := &v1like.LikeRouter{
+		Repository: repos.Likes,
+		Service:    likes,
+	}
+	var sr *v1search.SearchRouter
+	if cfg.Features.Search {
+		sr = &v1search.SearchRouter{
+			Repository: repos.Search,
+			Likes:      repos.Likes,
+		}
 	}
-	r.Mount("/users", RoutesUser(ur))
+	r.Mount("/users", RoutesUser(ur, fr, lr, sr, tm))
+
+	ar := &v1user.AuthRouter{
+		Repository: repos.Users,
+		Tokens:     tm,
+		Sessions:   auth.NewRefreshManager(repos.RefreshTokens, auth.DefaultRefreshTTL),
+	}
+	r.Mount("/auth", RoutesAuth(ar))
+
+	posts := domainPost.NewService(repos.Posts)
+	posts.Users = repos.Users
+	posts.Limits = cfg.Posts.Limits()
+	posts.Events = bus
 
 	pr := &v1post.PostRouter{
-		Repository: &persistencePost.PostRepository{
-			Data: conn,
-		},
+		Repository: repos.Posts,
+		Service:    posts,
+		Likes:      repos.Likes,
+	}
+	r.Mount("/posts", RoutesPost(pr, lr, tm))
+	r.Mount("/timeline", RoutesTimeline(pr, tm))
+	r.Mount("/tags", RoutesTag(pr, tm))
+
+	if sr != nil {
+		r.Mount("/search", RoutesSearch(sr, tm))
+	}
+
+	if cfg.Features.Notifications {
+		nr := &v1notification.NotificationRouter{
+			Repository: repos.Notifications,
+		}
+		r.Mount("/notifications", RoutesNotification(nr, tm))
 	}
-	r.Mount("/posts", RoutesPost(pr))
 
 	return r
 }
@@ -0,0 +1,290 @@
+package infrastructure
+
+import (
+	"bytes"
+	"encoding/json"
+	"microblog/infrastructure/config"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testConfig returns a valid configuration of the memory storage.
+func testConfig() config.Config {
+	cfg := config.Default()
+	cfg.Storage = config.StorageMemory
+	cfg.Auth.Secret = "test-secret"
+
+	return cfg
+}
+
+// call serves a request with a JSON body and returns the recorded response.
+func call(s *Server, method, target, token string, body interface{}) *httptest.ResponseRecorder {
+	var reader bytes.Buffer
+	if body != nil {
+		_ = json.NewEncoder(&reader).Encode(body)
+	}
+
+	request := httptest.NewRequest(method, target, &reader)
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	response := httptest.NewRecorder()
+	s.ServeHTTP(response, request)
+
+	return response
+}
+
+func TestNewApplication_MemoryStorage(t *testing.T) {
+	s := NewApplication(testConfig(), NewMemoryRepositories())
+
+	tokens := make([]string, 2)
+	for i, username := range []string{"daniel.delapava", "rebecca.romero"} {
+		email := username + "@jikkosoft.com"
+		user := map[string]string{"first_name": "Test", "last_name": "User", "username": username, "email": email, "password": "123456"}
+
+		response := call(s, http.MethodPost, "/api/v1/users/", "", user)
+		assert.Equal(t, http.StatusCreated, response.Code)
+
+		response = call(s, http.MethodPost, "/api/v1/auth/login", "", map[string]string{"email": email, "password": "123456"})
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		var login struct {
+			AccessToken string `json:"access_token"`
+		}
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&login))
+		tokens[i] = login.AccessToken
+	}
+
+	response := call(s, http.MethodPut, "/api/v1/users/2/follow", tokens[0], nil)
+	assert.Equal(t, http.StatusNoContent, response.Code)
+
+	response = call(s, http.MethodPost, "/api/v1/posts/", tokens[1], map[string]string{"body": "Lorem ipsum dolor sit amet"})
+	assert.Equal(t, http.StatusCreated, response.Code)
+
+	response = call(s, http.MethodGet, "/api/v1/timeline/", tokens[0], nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	var timeline struct {
+		Items []struct {
+			UserID uint `json:"user_id"`
+		} `json:"items"`
+	}
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&timeline))
+	if assert.Len(t, timeline.Items, 1) {
+		assert.Equal(t, uint(2), timeline.Items[0].UserID)
+	}
+
+	response = call(s, http.MethodPut, "/api/v1/posts/1/like", tokens[0], nil)
+	assert.Equal(t, http.StatusNoContent, response.Code)
+
+	var post struct {
+		LikeCount int  `json:"like_count"`
+		LikedByMe bool `json:"liked_by_me"`
+	}
+	response = call(s, http.MethodGet, "/api/v1/posts/1", tokens[0], nil)
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&post))
+	assert.Equal(t, 1, post.LikeCount)
+	assert.True(t, post.LikedByMe)
+
+	response = call(s, http.MethodGet, "/api/v1/posts/1", "", nil)
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&post))
+	assert.Equal(t, 1, post.LikeCount)
+	assert.False(t, post.LikedByMe)
+
+	response = call(s, http.MethodPost, "/api/v1/posts/1/repost", tokens[0], nil)
+	assert.Equal(t, http.StatusCreated, response.Code)
+
+	response = call(s, http.MethodPost, "/api/v1/posts/1/repost", tokens[0], nil)
+	assert.Equal(t, http.StatusConflict, response.Code)
+
+	var reposts struct {
+		Items []struct {
+			RepostOf uint `json:"repost_of"`
+			Original struct {
+				ID        uint `json:"id"`
+				LikeCount int  `json:"like_count"`
+			} `json:"original"`
+		} `json:"items"`
+	}
+	response = call(s, http.MethodGet, "/api/v1/posts/user/1", "", nil)
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&reposts))
+	if assert.Len(t, reposts.Items, 1) {
+		assert.Equal(t, uint(1), reposts.Items[0].RepostOf)
+		assert.Equal(t, uint(1), reposts.Items[0].Original.ID)
+		assert.Equal(t, 1, reposts.Items[0].Original.LikeCount)
+	}
+
+	response = call(s, http.MethodPost, "/api/v1/posts/", tokens[1], map[string]string{"body": "Hello #Gophers"})
+	assert.Equal(t, http.StatusCreated, response.Code)
+
+	var tagged struct {
+		Items []struct {
+			Body string `json:"body"`
+		} `json:"items"`
+	}
+	response = call(s, http.MethodGet, "/api/v1/tags/gophers/posts", "", nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&tagged))
+	if assert.Len(t, tagged.Items, 1) {
+		assert.Equal(t, "Hello #Gophers", tagged.Items[0].Body)
+	}
+
+	response = call(s, http.MethodPost, "/api/v1/posts/", tokens[1], map[string]string{"body": "Hola @daniel.delapava y @nobody"})
+	assert.Equal(t, http.StatusCreated, response.Code)
+
+	var mentioning struct {
+		Mentions []struct {
+			UserID uint `json:"user_id"`
+			Start  int  `json:"start"`
+			End    int  `json:"end"`
+		} `json:"mentions"`
+	}
+	response = call(s, http.MethodGet, response.Header().Get("Location"), "", nil)
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&mentioning))
+	if assert.Len(t, mentioning.Mentions, 1) {
+		assert.Equal(t, uint(1), mentioning.Mentions[0].UserID)
+		assert.Equal(t, 5, mentioning.Mentions[0].Start)
+		assert.Equal(t, 21, mentioning.Mentions[0].End)
+	}
+
+	var found struct {
+		Items []struct {
+			ID      uint   `json:"id"`
+			Snippet string `json:"snippet"`
+		} `json:"items"`
+	}
+	response = call(s, http.MethodGet, "/api/v1/search/posts?q=gopher*", "", nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&found))
+	if assert.Len(t, found.Items, 1) {
+		assert.Equal(t, "Hello #<mark>Gophers</mark>", found.Items[0].Snippet)
+	}
+
+	response = call(s, http.MethodGet, "/api/v1/search/posts", "", nil)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	var people struct {
+		Items []map[string]interface{} `json:"items"`
+	}
+	response = call(s, http.MethodGet, "/api/v1/users/search?q=rebeca", "", nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&people))
+	if assert.Len(t, people.Items, 1) {
+		assert.Equal(t, "rebecca.romero", people.Items[0]["username"])
+		assert.NotContains(t, people.Items[0], "email")
+		assert.NotContains(t, people.Items[0], "password")
+	}
+
+	response = call(s, http.MethodGet, "/api/v1/users/autocomplete?q=@dan", "", nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&people))
+	if assert.Len(t, people.Items, 1) {
+		assert.Equal(t, "daniel.delapava", people.Items[0]["username"])
+	}
+
+	var unread struct {
+		UnreadCount int `json:"unread_count"`
+	}
+	response = call(s, http.MethodGet, "/api/v1/notifications/unread-count", tokens[1], nil)
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&unread))
+	assert.Equal(t, 3, unread.UnreadCount)
+
+	var notifications struct {
+		Items []struct {
+			ID      uint   `json:"id"`
+			ActorID uint   `json:"actor_id"`
+			Kind    string `json:"kind"`
+		} `json:"items"`
+	}
+	response = call(s, http.MethodGet, "/api/v1/notifications/?unread=true", tokens[0], nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&notifications))
+	if assert.Len(t, notifications.Items, 1) {
+		assert.Equal(t, "mention", notifications.Items[0].Kind)
+		assert.Equal(t, uint(2), notifications.Items[0].ActorID)
+	}
+
+	response = call(s, http.MethodPost, "/api/v1/notifications/read", tokens[1], nil)
+	assert.Equal(t, http.StatusNoContent, response.Code)
+
+	response = call(s, http.MethodGet, "/api/v1/notifications/unread-count", tokens[1], nil)
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&unread))
+	assert.Zero(t, unread.UnreadCount)
+
+	response = call(s, http.MethodDelete, "/api/v1/posts/1", tokens[0], nil)
+	assert.Equal(t, http.StatusForbidden, response.Code)
+	assert.Equal(t, "application/problem+json", response.Header().Get("Content-Type"))
+
+	var problem struct {
+		Status   int    `json:"status"`
+		Instance string `json:"instance"`
+	}
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&problem))
+	assert.Equal(t, http.StatusForbidden, problem.Status)
+	assert.NotEmpty(t, problem.Instance)
+
+	response = call(s, http.MethodPost, "/api/v1/users/", "", map[string]string{"username": "nobody"})
+	assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+
+	var invalid struct {
+		Errors []struct {
+			Field string `json:"field"`
+		} `json:"errors"`
+	}
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&invalid))
+	assert.Len(t, invalid.Errors, 2)
+
+	response = call(s, http.MethodGet, "/api/v1/unknown", "", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	assert.Equal(t, "application/problem+json", response.Header().Get("Content-Type"))
+}
+
+func TestNewApplication_Health(t *testing.T) {
+	s := NewApplication(testConfig(), NewMemoryRepositories())
+
+	response := call(s, http.MethodGet, "/healthz", "", nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	// not serving yet, the memory storage has no database to check.
+	response = call(s, http.MethodGet, "/readyz", "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+	assert.JSONEq(t, `{"status":"not ready","checks":{"server":{"status":"down","error":"server is not serving"}}}`, response.Body.String())
+
+	atomic.StoreInt32(&s.ready, 1)
+	response = call(s, http.MethodGet, "/readyz", "", nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"status":"ready","checks":{"server":{"status":"up"}}}`, response.Body.String())
+}
+
+func TestNewApplication_Metrics(t *testing.T) {
+	s := NewApplication(testConfig(), NewMemoryRepositories())
+
+	response := call(s, http.MethodGet, "/api/v1/posts/7", "", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	response = call(s, http.MethodGet, "/metrics", "", nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	body := response.Body.String()
+	assert.Contains(t, body, `microblog_http_requests_total{method="GET",route="/api/v1/posts/{id}",status="404"} 1`)
+	assert.Contains(t, body, `microblog_repository_call_duration_seconds_count{method="GetOne",outcome="rejected",repository="posts"} 1`)
+}
+
+func TestNewApplication_FeaturesOff(t *testing.T) {
+	cfg := testConfig()
+	cfg.Features = config.Features{}
+	s := NewApplication(cfg, NewMemoryRepositories())
+
+	for _, target := range []string{"/api/v1/search/posts?q=go", "/api/v1/notifications/", "/metrics"} {
+		response := call(s, http.MethodGet, target, "", nil)
+		assert.Equal(t, http.StatusNotFound, response.Code, target)
+	}
+
+	response := call(s, http.MethodGet, "/api/v1/posts/", "", nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+}
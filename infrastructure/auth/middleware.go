@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"context"
+	"microblog/domain/shared/response"
+	"microblog/domain/user/domain"
+	"net/http"
+	"strings"
+)
+
+// contextKey is the key under which the Principal is stored in a context.
+type contextKey struct{}
+
+// Principal is the authenticated user of a request.
+type Principal struct {
+	UserID   uint
+	Username string
+	Email    string
+	Role     string
+}
+
+// IsAdmin reports whether the principal has the administrator role.
+func (p Principal) IsAdmin() bool {
+	return p.Role == domain.RoleAdmin
+}
+
+// NewContext returns a copy of ctx that carries the given principal.
+func NewContext(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext returns the principal stored in ctx, if any.
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(contextKey{}).(Principal)
+	return p, ok
+}
+
+// Authenticator is a middleware that verifies the bearer token of the request
+// and stores the authenticated user in the request context.
+func Authenticator(tm *TokenManager) func(http.Handler) http.Handler {
+	return authenticator(tm, true)
+}
+
+// OptionalAuthenticator is a middleware like Authenticator that lets the
+// requests without a bearer token through anonymously. A token that is
+// present must still be valid.
+func OptionalAuthenticator(tm *TokenManager) func(http.Handler) http.Handler {
+	return authenticator(tm, false)
+}
+
+// authenticator returns the authentication middleware, which rejects the
+// requests without a bearer token when required.
+func authenticator(tm *TokenManager, required bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			token, ok := bearerToken(r)
+			if !ok && !required {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !ok {
+				unauthorized(w, r, "missing bearer token")
+				return
+			}
+
+			claims, err := tm.Parse(token)
+			if err != nil {
+				unauthorized(w, r, err.Error())
+				return
+			}
+
+			ctx := NewContext(r.Context(), Principal{
+				UserID:   claims.UserID,
+				Username: claims.Username,
+				Email:    claims.Email,
+				Role:     claims.Role,
+			})
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// bearerToken extracts the token of an "Authorization: Bearer <token>" header.
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+
+	const prefix = "bearer "
+	if len(header) <= len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return "", false
+	}
+
+	token := strings.TrimSpace(header[len(prefix):])
+	return token, token != ""
+}
+
+// unauthorized writes a 401 response asking for a bearer token.
+func unauthorized(w http.ResponseWriter, r *http.Request, message string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="microblog"`)
+	_ = response.HTTPError(w, r, http.StatusUnauthorized, message)
+}
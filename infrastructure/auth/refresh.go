@@ -0,0 +1,174 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"microblog/domain/shared/errs"
+	"microblog/domain/user/domain"
+	"time"
+)
+
+// DefaultRefreshTTL is the lifetime of a refresh token when none is configured.
+const DefaultRefreshTTL = 30 * 24 * time.Hour
+
+var (
+	// ErrInvalidRefreshToken is returned when a refresh token is unknown or expired.
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+
+	// ErrRefreshTokenReused is returned when an already rotated refresh token is
+	// presented again. The whole token family is revoked when this happens.
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected, session revoked")
+)
+
+// RefreshToken is a raw refresh token handed to a client.
+type RefreshToken struct {
+	Token     string    `json:"refresh_token"`
+	ExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// RefreshManager issues, rotates and revokes refresh tokens. Only the hash of
+// a token is stored, the raw value is known by the client alone.
+type RefreshManager struct {
+	Repository domain.RefreshTokenRepository
+	ttl        time.Duration
+	now        func() time.Time
+}
+
+// NewRefreshManager returns a RefreshManager that stores tokens in the given
+// repository. A non positive ttl falls back to DefaultRefreshTTL.
+func NewRefreshManager(repository domain.RefreshTokenRepository, ttl time.Duration) *RefreshManager {
+	if ttl <= 0 {
+		ttl = DefaultRefreshTTL
+	}
+
+	return &RefreshManager{
+		Repository: repository,
+		ttl:        ttl,
+		now:        utcNow,
+	}
+}
+
+// utcNow returns the current time in UTC, the zone refresh tokens are
+// stored in.
+func utcNow() time.Time {
+	return time.Now().UTC()
+}
+
+// Issue starts a new session for the user and returns its first refresh token.
+func (rm *RefreshManager) Issue(ctx context.Context, userID uint) (RefreshToken, error) {
+	family, err := randomToken(16)
+	if err != nil {
+		return RefreshToken{}, err
+	}
+
+	return rm.create(ctx, userID, family)
+}
+
+// Rotate exchanges a refresh token for a new one of the same session and
+// returns the owner of the session. A token can be rotated only once;
+// presenting it again revokes the whole session.
+func (rm *RefreshManager) Rotate(ctx context.Context, raw string) (uint, RefreshToken, error) {
+	current, err := rm.Repository.GetByHash(ctx, hashToken(raw))
+	if errors.Is(err, errs.ErrNotFound) {
+		return 0, RefreshToken{}, ErrInvalidRefreshToken
+	}
+
+	if err != nil {
+		return 0, RefreshToken{}, err
+	}
+
+	if current.Revoked() {
+		return 0, RefreshToken{}, rm.reused(ctx, current)
+	}
+
+	if current.Expired(rm.now()) {
+		return 0, RefreshToken{}, ErrInvalidRefreshToken
+	}
+
+	revoked, err := rm.Repository.Revoke(ctx, current.ID)
+	if err != nil {
+		return 0, RefreshToken{}, err
+	}
+
+	// Somebody else rotated the token between the read and the revocation.
+	if !revoked {
+		return 0, RefreshToken{}, rm.reused(ctx, current)
+	}
+
+	next, err := rm.create(ctx, current.UserID, current.FamilyID)
+	if err != nil {
+		return 0, RefreshToken{}, err
+	}
+
+	return current.UserID, next, nil
+}
+
+// Revoke ends the session the refresh token belongs to. Unknown tokens are
+// ignored so that logging out is idempotent.
+func (rm *RefreshManager) Revoke(ctx context.Context, raw string) error {
+	current, err := rm.Repository.GetByHash(ctx, hashToken(raw))
+	if errors.Is(err, errs.ErrNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return rm.Repository.RevokeFamily(ctx, current.FamilyID)
+}
+
+// RevokeAll ends every session of the user.
+func (rm *RefreshManager) RevokeAll(ctx context.Context, userID uint) error {
+	return rm.Repository.RevokeAllByUser(ctx, userID)
+}
+
+// create stores a new refresh token of the given family.
+func (rm *RefreshManager) create(ctx context.Context, userID uint, family string) (RefreshToken, error) {
+	raw, err := randomToken(32)
+	if err != nil {
+		return RefreshToken{}, err
+	}
+
+	token := domain.RefreshToken{
+		UserID:    userID,
+		FamilyID:  family,
+		TokenHash: hashToken(raw),
+		ExpiresAt: rm.now().Add(rm.ttl),
+	}
+
+	if err := rm.Repository.Create(ctx, &token); err != nil {
+		return RefreshToken{}, err
+	}
+
+	return RefreshToken{Token: raw, ExpiresAt: token.ExpiresAt}, nil
+}
+
+// reused revokes the family of a token that was presented after rotation.
+func (rm *RefreshManager) reused(ctx context.Context, token domain.RefreshToken) error {
+	if err := rm.Repository.RevokeFamily(ctx, token.FamilyID); err != nil {
+		return err
+	}
+
+	return ErrRefreshTokenReused
+}
+
+// randomToken returns n random bytes encoded as URL safe base64.
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken returns the hex encoded SHA-256 of a raw token.
+func hashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
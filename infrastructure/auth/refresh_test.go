@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"context"
+	"microblog/domain/shared/errs"
+	"microblog/domain/user/domain"
+	mockLocal "microblog/domain/user/domain/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRefreshManager_Issue(t *testing.T) {
+	mockRepository := &mockLocal.RefreshTokenRepository{}
+	rm := NewRefreshManager(mockRepository, time.Hour)
+
+	var stored *domain.RefreshToken
+	mockRepository.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*domain.RefreshToken)
+	}).Return(nil).Once()
+
+	token, err := rm.Issue(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token.Token)
+	assert.Equal(t, hashToken(token.Token), stored.TokenHash)
+	assert.NotEqual(t, token.Token, stored.TokenHash)
+	assert.NotEmpty(t, stored.FamilyID)
+	mockRepository.AssertExpectations(t)
+}
+
+func TestRefreshManager_Rotate(t *testing.T) {
+
+	t.Run("Error Unknown Token", func(tt *testing.T) {
+		mockRepository := &mockLocal.RefreshTokenRepository{}
+		rm := NewRefreshManager(mockRepository, time.Hour)
+
+		mockRepository.On("GetByHash", mock.Anything, hashToken("unknown")).Return(domain.RefreshToken{}, errs.NotFound("refresh token not found")).Once()
+
+		_, _, err := rm.Rotate(context.Background(), "unknown")
+		assert.Equal(tt, ErrInvalidRefreshToken, err)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Error Expired Token", func(tt *testing.T) {
+		mockRepository := &mockLocal.RefreshTokenRepository{}
+		rm := NewRefreshManager(mockRepository, time.Hour)
+
+		mockRepository.On("GetByHash", mock.Anything, mock.Anything).
+			Return(domain.RefreshToken{ID: 1, UserID: 1, FamilyID: "family", ExpiresAt: time.Now().Add(-time.Minute)}, nil).Once()
+
+		_, _, err := rm.Rotate(context.Background(), "expired")
+		assert.Equal(tt, ErrInvalidRefreshToken, err)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Error Concurrent Rotation Revokes Family", func(tt *testing.T) {
+		mockRepository := &mockLocal.RefreshTokenRepository{}
+		rm := NewRefreshManager(mockRepository, time.Hour)
+
+		mockRepository.On("GetByHash", mock.Anything, mock.Anything).
+			Return(domain.RefreshToken{ID: 1, UserID: 1, FamilyID: "family", ExpiresAt: time.Now().Add(time.Hour)}, nil).Once()
+		mockRepository.On("Revoke", mock.Anything, uint(1)).Return(false, nil).Once()
+		mockRepository.On("RevokeFamily", mock.Anything, "family").Return(nil).Once()
+
+		_, _, err := rm.Rotate(context.Background(), "raced")
+		assert.Equal(tt, ErrRefreshTokenReused, err)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Rotate Keeps Family", func(tt *testing.T) {
+		mockRepository := &mockLocal.RefreshTokenRepository{}
+		rm := NewRefreshManager(mockRepository, time.Hour)
+
+		mockRepository.On("GetByHash", mock.Anything, mock.Anything).
+			Return(domain.RefreshToken{ID: 1, UserID: 7, FamilyID: "family", ExpiresAt: time.Now().Add(time.Hour)}, nil).Once()
+		mockRepository.On("Revoke", mock.Anything, uint(1)).Return(true, nil).Once()
+		mockRepository.On("Create", mock.Anything, mock.MatchedBy(func(token *domain.RefreshToken) bool {
+			return token.FamilyID == "family" && token.UserID == 7
+		})).Return(nil).Once()
+
+		userID, token, err := rm.Rotate(context.Background(), "valid")
+		assert.NoError(tt, err)
+		assert.Equal(tt, uint(7), userID)
+		assert.NotEqual(tt, "valid", token.Token)
+		mockRepository.AssertExpectations(tt)
+	})
+}
+
+func TestRefreshManager_Revoke(t *testing.T) {
+	mockRepository := &mockLocal.RefreshTokenRepository{}
+	rm := NewRefreshManager(mockRepository, time.Hour)
+
+	mockRepository.On("GetByHash", mock.Anything, mock.Anything).Return(domain.RefreshToken{}, errs.NotFound("refresh token not found")).Once()
+
+	err := rm.Revoke(context.Background(), "unknown")
+	assert.NoError(t, err)
+	mockRepository.AssertExpectations(t)
+}
@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"microblog/domain/user/domain"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// DefaultAccessTTL is the lifetime of an access token when none is configured.
+const DefaultAccessTTL = 15 * time.Minute
+
+// issuer identifies the tokens signed by this service.
+const issuer = "microblog"
+
+// ErrInvalidToken is returned when a token cannot be verified.
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// Claims are the JWT claims carried by an access token.
+type Claims struct {
+	UserID   uint   `json:"uid"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Role     string `json:"role,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// AccessToken is a signed access token issued to a user.
+type AccessToken struct {
+	Token     string    `json:"access_token"`
+	TokenType string    `json:"token_type"`
+	ExpiresIn int64     `json:"expires_in"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// TokenManager signs and verifies access tokens with a shared secret.
+type TokenManager struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// NewTokenManager returns a TokenManager that signs tokens with the given
+// secret and lifetime. A non positive ttl falls back to DefaultAccessTTL.
+func NewTokenManager(secret string, ttl time.Duration) *TokenManager {
+	if ttl <= 0 {
+		ttl = DefaultAccessTTL
+	}
+
+	return &TokenManager{
+		secret: []byte(secret),
+		ttl:    ttl,
+		now:    time.Now,
+	}
+}
+
+// Generate returns a signed access token for the given user.
+func (tm *TokenManager) Generate(user domain.User) (AccessToken, error) {
+	if len(tm.secret) == 0 {
+		return AccessToken{}, errors.New("token secret is not configured")
+	}
+
+	now := tm.now()
+	expiresAt := now.Add(tm.ttl)
+
+	claims := Claims{
+		UserID:   user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+		Role:     user.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   strconv.FormatUint(uint64(user.ID), 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(tm.secret)
+	if err != nil {
+		return AccessToken{}, err
+	}
+
+	return AccessToken{
+		Token:     signed,
+		TokenType: "Bearer",
+		ExpiresIn: int64(tm.ttl.Seconds()),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// Parse verifies the signature and expiry of a token and returns its claims.
+func (tm *TokenManager) Parse(token string) (*Claims, error) {
+	parser := jwt.Parser{ValidMethods: []string{jwt.SigningMethodHS256.Alg()}}
+
+	claims := &Claims{}
+	_, err := parser.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", t.Header["alg"])
+		}
+
+		return tm.secret, nil
+	})
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if claims.Issuer != issuer || claims.UserID == 0 {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
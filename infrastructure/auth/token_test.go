@@ -0,0 +1,160 @@
+package auth
+
+import (
+	"microblog/domain/user/domain"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// dataUser is data for test
+func dataUser() domain.User {
+	return domain.User{
+		ID:       uint(1),
+		Username: "daniel.delapava",
+		Email:    "daniel.delapava@jikkosoft.com",
+	}
+}
+
+func TestTokenManager_Generate(t *testing.T) {
+
+	t.Run("Error Secret Not Configured", func(tt *testing.T) {
+		tm := NewTokenManager("", time.Minute)
+
+		_, err := tm.Generate(dataUser())
+		assert.Error(tt, err)
+	})
+
+	t.Run("Generate Token Successful", func(tt *testing.T) {
+		tm := NewTokenManager("secret", time.Minute)
+
+		token, err := tm.Generate(dataUser())
+		assert.NoError(tt, err)
+		assert.NotEmpty(tt, token.Token)
+		assert.Equal(tt, "Bearer", token.TokenType)
+		assert.Equal(tt, int64(60), token.ExpiresIn)
+
+		claims, err := tm.Parse(token.Token)
+		assert.NoError(tt, err)
+		assert.Equal(tt, uint(1), claims.UserID)
+		assert.Equal(tt, "daniel.delapava", claims.Username)
+		assert.Equal(tt, "1", claims.Subject)
+	})
+}
+
+func TestTokenManager_Parse(t *testing.T) {
+
+	t.Run("Error Wrong Secret", func(tt *testing.T) {
+		token, err := NewTokenManager("secret", time.Minute).Generate(dataUser())
+		assert.NoError(tt, err)
+
+		_, err = NewTokenManager("other", time.Minute).Parse(token.Token)
+		assert.Equal(tt, ErrInvalidToken, err)
+	})
+
+	t.Run("Error Expired Token", func(tt *testing.T) {
+		tm := NewTokenManager("secret", time.Minute)
+		tm.now = func() time.Time { return time.Now().Add(-time.Hour) }
+
+		token, err := tm.Generate(dataUser())
+		assert.NoError(tt, err)
+
+		_, err = tm.Parse(token.Token)
+		assert.Equal(tt, ErrInvalidToken, err)
+	})
+
+	t.Run("Error Unexpected Signing Method", func(tt *testing.T) {
+		claims := Claims{UserID: 1, RegisteredClaims: jwt.RegisteredClaims{Issuer: issuer}}
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+		assert.NoError(tt, err)
+
+		_, err = NewTokenManager("secret", time.Minute).Parse(signed)
+		assert.Equal(tt, ErrInvalidToken, err)
+	})
+}
+
+func TestAuthenticator(t *testing.T) {
+	tm := NewTokenManager("secret", time.Minute)
+
+	var principal Principal
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, _ = FromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	t.Run("Error Missing Token", func(tt *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/auth/me", nil)
+		response := httptest.NewRecorder()
+
+		Authenticator(tm)(next).ServeHTTP(response, request)
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+		assert.NotEmpty(tt, response.Header().Get("WWW-Authenticate"))
+	})
+
+	t.Run("Error Invalid Token", func(tt *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/auth/me", nil)
+		request.Header.Set("Authorization", "Bearer invalid")
+		response := httptest.NewRecorder()
+
+		Authenticator(tm)(next).ServeHTTP(response, request)
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+	})
+
+	t.Run("Authenticated Request", func(tt *testing.T) {
+		token, err := tm.Generate(dataUser())
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/auth/me", nil)
+		request.Header.Set("Authorization", "Bearer "+token.Token)
+		response := httptest.NewRecorder()
+
+		Authenticator(tm)(next).ServeHTTP(response, request)
+		assert.Equal(tt, http.StatusOK, response.Code)
+		assert.Equal(tt, uint(1), principal.UserID)
+	})
+}
+
+func TestOptionalAuthenticator(t *testing.T) {
+	tm := NewTokenManager("secret", time.Minute)
+
+	var authenticated bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, authenticated = FromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	t.Run("Anonymous Request", func(tt *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/posts/", nil)
+		response := httptest.NewRecorder()
+
+		OptionalAuthenticator(tm)(next).ServeHTTP(response, request)
+		assert.Equal(tt, http.StatusOK, response.Code)
+		assert.False(tt, authenticated)
+	})
+
+	t.Run("Error Invalid Token", func(tt *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/posts/", nil)
+		request.Header.Set("Authorization", "Bearer invalid")
+		response := httptest.NewRecorder()
+
+		OptionalAuthenticator(tm)(next).ServeHTTP(response, request)
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+	})
+
+	t.Run("Authenticated Request", func(tt *testing.T) {
+		token, err := tm.Generate(dataUser())
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/posts/", nil)
+		request.Header.Set("Authorization", "Bearer "+token.Token)
+		response := httptest.NewRecorder()
+
+		OptionalAuthenticator(tm)(next).ServeHTTP(response, request)
+		assert.Equal(tt, http.StatusOK, response.Code)
+		assert.True(tt, authenticated)
+	})
+}
@@ -0,0 +1,309 @@
+// Package config holds the configuration of the microblog, read once at
+// startup from defaults, a YAML file, the environment and the command line
+// flags, each overriding the ones before, and validated as a whole.
+package config
+
+import (
+	"fmt"
+	domainPost "microblog/domain/post/domain"
+	"microblog/infrastructure/tracing"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Storage backends the repositories can be built on.
+const (
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+)
+
+// sslModes are the SSL modes PostgreSQL accepts.
+var sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// Config is the configuration of the microblog.
+type Config struct {
+	// Storage is the backend of the repositories, postgres or memory.
+	Storage  string   `yaml:"storage"`
+	Server   Server   `yaml:"server"`
+	Database Database `yaml:"database"`
+	Auth     Auth     `yaml:"auth"`
+	Posts    Posts    `yaml:"posts"`
+	Tracing  Tracing  `yaml:"tracing"`
+	Features Features `yaml:"features"`
+}
+
+// Server is the configuration of the HTTP server.
+type Server struct {
+	Port         int           `yaml:"port"`
+	ReadTimeout  time.Duration `yaml:"read_timeout"`
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout"`
+	// ShutdownDelay is how long the server keeps serving once it reports
+	// not ready on shutdown, so the load balancers stop routing to it.
+	ShutdownDelay time.Duration `yaml:"shutdown_delay"`
+	// ShutdownTimeout is how long the active requests are drained on
+	// shutdown before they are cut.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
+
+// Addr returns the address the server listens on.
+func (s Server) Addr() string {
+	return ":" + strconv.Itoa(s.Port)
+}
+
+// Database is the configuration of the PostgreSQL connection pool.
+type Database struct {
+	Driver   string `yaml:"driver"`
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Name     string `yaml:"name"`
+	SSLMode  string `yaml:"sslmode"`
+
+	// MaxOpenConns caps the connections of the pool, 0 leaves them
+	// unlimited. MaxIdleConns caps the ones kept idle.
+	MaxOpenConns int `yaml:"max_open_conns"`
+	MaxIdleConns int `yaml:"max_idle_conns"`
+	// ConnMaxLifetime and ConnMaxIdleTime close the connections used or idle
+	// for longer, 0 keeps them.
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time"`
+}
+
+// DSN returns the connection string of the database, its values quoted as
+// libpq expects.
+func (d Database) DSN() string {
+	params := []struct{ key, value string }{
+		{"host", d.Host},
+		{"port", strconv.Itoa(d.Port)},
+		{"user", d.User},
+		{"password", d.Password},
+		{"dbname", d.Name},
+		{"sslmode", d.SSLMode},
+	}
+
+	pairs := make([]string, 0, len(params))
+	for _, p := range params {
+		if p.value == "" {
+			continue
+		}
+
+		pairs = append(pairs, p.key+"="+quote(p.value))
+	}
+
+	return strings.Join(pairs, " ")
+}
+
+// quote quotes a value of a connection string when it is needed.
+func quote(value string) string {
+	if !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+// Auth is the configuration of the authentication.
+type Auth struct {
+	// Secret signs the access tokens.
+	Secret string `yaml:"secret"`
+}
+
+// Posts are the limits of the post bodies, 0 disables one.
+type Posts struct {
+	MaxLength   int `yaml:"max_length"`
+	MaxLinks    int `yaml:"max_links"`
+	MaxMentions int `yaml:"max_mentions"`
+}
+
+// Limits returns the limits of the post bodies.
+func (p Posts) Limits() domainPost.Limits {
+	return domainPost.Limits{MaxLength: p.MaxLength, MaxLinks: p.MaxLinks, MaxMentions: p.MaxMentions}
+}
+
+// Tracing is the configuration of the traces.
+type Tracing struct {
+	// Exporter sends the spans: otlp, stdout or none.
+	Exporter string `yaml:"exporter"`
+}
+
+// Features switch parts of the API on and off.
+type Features struct {
+	Search        bool `yaml:"search"`
+	Notifications bool `yaml:"notifications"`
+	Metrics       bool `yaml:"metrics"`
+}
+
+// Default returns the configuration used unless told otherwise. It is not
+// valid as is: the database and the auth secret have no default.
+func Default() Config {
+	limits := domainPost.DefaultLimits
+
+	return Config{
+		Storage: StoragePostgres,
+		Server: Server{
+			Port:            9000,
+			ReadTimeout:     10 * time.Second,
+			WriteTimeout:    10 * time.Second,
+			IdleTimeout:     60 * time.Second,
+			ShutdownDelay:   5 * time.Second,
+			ShutdownTimeout: 15 * time.Second,
+		},
+		Database: Database{
+			Driver:          "postgres",
+			Port:            5432,
+			SSLMode:         "disable",
+			MaxOpenConns:    25,
+			MaxIdleConns:    25,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+		},
+		Posts: Posts{
+			MaxLength:   limits.MaxLength,
+			MaxLinks:    limits.MaxLinks,
+			MaxMentions: limits.MaxMentions,
+		},
+		Tracing: Tracing{Exporter: tracing.ExporterNone},
+		Features: Features{
+			Search:        true,
+			Notifications: true,
+			Metrics:       true,
+		},
+	}
+}
+
+// Error lists the invalid values of a configuration.
+type Error struct {
+	Problems []string
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return "invalid configuration: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate returns an *Error listing every invalid value, nil when there is
+// none. The database is only checked when the storage is postgres.
+func (c Config) Validate() error {
+	v := &Error{}
+
+	if c.Storage != StoragePostgres && c.Storage != StorageMemory {
+		v.add("storage must be %s or %s, got %q", StoragePostgres, StorageMemory, c.Storage)
+	}
+
+	v.port("server.port", c.Server.Port, 0)
+	v.positive("server.read_timeout", c.Server.ReadTimeout)
+	v.positive("server.write_timeout", c.Server.WriteTimeout)
+	v.positive("server.idle_timeout", c.Server.IdleTimeout)
+	v.positive("server.shutdown_timeout", c.Server.ShutdownTimeout)
+	if c.Server.ShutdownDelay < 0 {
+		v.add("server.shutdown_delay must not be negative, got %s", c.Server.ShutdownDelay)
+	}
+
+	if c.Storage == StoragePostgres {
+		c.Database.validate(v)
+	}
+
+	if c.Auth.Secret == "" {
+		v.add("auth.secret is required")
+	}
+
+	v.nonNegative("posts.max_length", c.Posts.MaxLength)
+	v.nonNegative("posts.max_links", c.Posts.MaxLinks)
+	v.nonNegative("posts.max_mentions", c.Posts.MaxMentions)
+
+	switch c.Tracing.Exporter {
+	case tracing.ExporterNone, tracing.ExporterOTLP, tracing.ExporterStdout:
+	default:
+		v.add("tracing.exporter must be one of %s, %s or %s, got %q", tracing.ExporterNone, tracing.ExporterOTLP, tracing.ExporterStdout, c.Tracing.Exporter)
+	}
+
+	if len(v.Problems) > 0 {
+		return v
+	}
+
+	return nil
+}
+
+// ValidateDatabase returns an *Error listing every invalid value of the
+// database configuration alone, nil when there is none.
+func ValidateDatabase(d Database) error {
+	v := &Error{}
+	d.validate(v)
+
+	if len(v.Problems) > 0 {
+		return v
+	}
+
+	return nil
+}
+
+// validate adds the invalid values of the database configuration to v.
+func (d Database) validate(v *Error) {
+	if d.Driver != "postgres" {
+		v.add("database.driver must be postgres, got %q", d.Driver)
+	}
+
+	for _, required := range []struct{ name, value string }{
+		{"database.host", d.Host},
+		{"database.user", d.User},
+		{"database.name", d.Name},
+	} {
+		if required.value == "" {
+			v.add("%s is required", required.name)
+		}
+	}
+
+	v.port("database.port", d.Port, 1)
+
+	known := false
+	for _, mode := range sslModes {
+		known = known || d.SSLMode == mode
+	}
+
+	if !known {
+		v.add("database.sslmode must be one of %s, got %q", strings.Join(sslModes, ", "), d.SSLMode)
+	}
+
+	v.nonNegative("database.max_open_conns", d.MaxOpenConns)
+	v.nonNegative("database.max_idle_conns", d.MaxIdleConns)
+	if d.MaxOpenConns > 0 && d.MaxIdleConns > d.MaxOpenConns {
+		v.add("database.max_idle_conns must not exceed database.max_open_conns, got %d > %d", d.MaxIdleConns, d.MaxOpenConns)
+	}
+
+	if d.ConnMaxLifetime < 0 {
+		v.add("database.conn_max_lifetime must not be negative, got %s", d.ConnMaxLifetime)
+	}
+
+	if d.ConnMaxIdleTime < 0 {
+		v.add("database.conn_max_idle_time must not be negative, got %s", d.ConnMaxIdleTime)
+	}
+}
+
+// add adds a problem to the list.
+func (e *Error) add(format string, args ...interface{}) {
+	e.Problems = append(e.Problems, fmt.Sprintf(format, args...))
+}
+
+// port checks that a port is at least min and at most 65535.
+func (e *Error) port(name string, port, min int) {
+	if port < min || port > 65535 {
+		e.add("%s must be between %d and 65535, got %d", name, min, port)
+	}
+}
+
+// positive checks that a duration is positive.
+func (e *Error) positive(name string, d time.Duration) {
+	if d <= 0 {
+		e.add("%s must be positive, got %s", name, d)
+	}
+}
+
+// nonNegative checks that a number is not negative.
+func (e *Error) nonNegative(name string, n int) {
+	if n < 0 {
+		e.add("%s must not be negative, got %d", name, n)
+	}
+}
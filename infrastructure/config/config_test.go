@@ -0,0 +1,191 @@
+package config
+
+import (
+	"errors"
+	domainPost "microblog/domain/post/domain"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// valid returns a valid configuration of the postgres storage.
+func valid() Config {
+	c := Default()
+	c.Database.Host = "127.0.0.1"
+	c.Database.User = "postgres"
+	c.Database.Name = "microblog"
+	c.Auth.Secret = "secret"
+
+	return c
+}
+
+// writeFile writes a configuration file in a temporary directory and
+// returns its path.
+func writeFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestConfig_Validate(t *testing.T) {
+	t.Run("Valid", func(tt *testing.T) {
+		assert.NoError(tt, valid().Validate())
+	})
+
+	t.Run("Memory Storage Needs No Database", func(tt *testing.T) {
+		c := Default()
+		c.Storage = StorageMemory
+		c.Auth.Secret = "secret"
+
+		assert.NoError(tt, c.Validate())
+	})
+
+	t.Run("Error Lists Every Problem", func(tt *testing.T) {
+		c := Default()
+		c.Server.Port = 70000
+		c.Server.ShutdownDelay = -time.Second
+		c.Server.ShutdownTimeout = 0
+		c.Database.SSLMode = "on"
+		c.Database.MaxOpenConns = 5
+		c.Database.MaxIdleConns = 10
+		c.Posts.MaxLinks = -1
+		c.Tracing.Exporter = "zipkin"
+
+		err := c.Validate()
+
+		var invalid *Error
+		if assert.True(tt, errors.As(err, &invalid), "%v", err) {
+			assert.Equal(tt, []string{
+				"server.port must be between 0 and 65535, got 70000",
+				"server.shutdown_timeout must be positive, got 0s",
+				"server.shutdown_delay must not be negative, got -1s",
+				"database.host is required",
+				"database.user is required",
+				"database.name is required",
+				`database.sslmode must be one of disable, allow, prefer, require, verify-ca, verify-full, got "on"`,
+				"database.max_idle_conns must not exceed database.max_open_conns, got 10 > 5",
+				"auth.secret is required",
+				"posts.max_links must not be negative, got -1",
+				`tracing.exporter must be one of none, otlp or stdout, got "zipkin"`,
+			}, invalid.Problems)
+		}
+	})
+
+	t.Run("Error Unknown Storage", func(tt *testing.T) {
+		c := valid()
+		c.Storage = "redis"
+
+		assert.EqualError(tt, c.Validate(), `invalid configuration: storage must be postgres or memory, got "redis"`)
+	})
+}
+
+func TestDatabase_DSN(t *testing.T) {
+	d := valid().Database
+	d.Password = "it's a secret"
+
+	assert.Equal(t, `host=127.0.0.1 port=5432 user=postgres password='it\'s a secret' dbname=microblog sslmode=disable`, d.DSN())
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("Defaults", func(tt *testing.T) {
+		c, args, err := Load(nil)
+		assert.NoError(tt, err)
+		assert.Empty(tt, args)
+		assert.Equal(tt, Default(), c)
+		assert.Equal(tt, domainPost.DefaultLimits, c.Posts.Limits())
+	})
+
+	t.Run("Precedence", func(tt *testing.T) {
+		path := writeFile(tt, `
+storage: memory
+server:
+  port: 8000
+  read_timeout: 5s
+  shutdown_delay: 0s
+  shutdown_timeout: 1m
+database:
+  host: db.internal
+  sslmode: require
+features:
+  search: false
+`)
+		tt.Setenv(FileEnv, path)
+		tt.Setenv("DAEMON_PORT", "8080")
+		tt.Setenv("DB_SSLMODE", "verify-full")
+		tt.Setenv("POST_MAX_LINKS", "0")
+
+		c, args, err := Load([]string{"-port", "8081", "-feature-search", "-db-max-open-conns=10", "migrate", "up"})
+		assert.NoError(tt, err)
+		assert.Equal(tt, []string{"migrate", "up"}, args)
+
+		assert.Equal(tt, StorageMemory, c.Storage)
+		assert.Equal(tt, 8081, c.Server.Port)
+		assert.Equal(tt, 5*time.Second, c.Server.ReadTimeout)
+		assert.Equal(tt, Default().Server.WriteTimeout, c.Server.WriteTimeout)
+		assert.Equal(tt, time.Duration(0), c.Server.ShutdownDelay)
+		assert.Equal(tt, time.Minute, c.Server.ShutdownTimeout)
+		assert.Equal(tt, "db.internal", c.Database.Host)
+		assert.Equal(tt, "verify-full", c.Database.SSLMode)
+		assert.Equal(tt, 10, c.Database.MaxOpenConns)
+		assert.Equal(tt, 0, c.Posts.MaxLinks)
+		assert.True(tt, c.Features.Search)
+		assert.True(tt, c.Features.Notifications)
+	})
+
+	t.Run("Config Flag Over Environment", func(tt *testing.T) {
+		tt.Setenv(FileEnv, writeFile(tt, "storage: memory\n"))
+
+		c, _, err := Load([]string{"-config", writeFile(tt, "storage: postgres\n")})
+		assert.NoError(tt, err)
+		assert.Equal(tt, StoragePostgres, c.Storage)
+	})
+
+	t.Run("Error Unknown Key", func(tt *testing.T) {
+		_, _, err := Load([]string{"-config", writeFile(tt, "server:\n  prot: 8000\n")})
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "field prot not found")
+	})
+
+	t.Run("Error Missing File", func(tt *testing.T) {
+		_, _, err := Load([]string{"-config", filepath.Join(tt.TempDir(), "missing.yaml")})
+		assert.True(tt, errors.Is(err, os.ErrNotExist), "%v", err)
+	})
+
+	t.Run("Error Invalid Environment Value", func(tt *testing.T) {
+		tt.Setenv("SHUTDOWN_TIMEOUT", "15")
+
+		_, _, err := Load(nil)
+		assert.EqualError(tt, err, `SHUTDOWN_TIMEOUT must be a duration such as 10s, got "15"`)
+	})
+
+	t.Run("Error Invalid Flag Value", func(tt *testing.T) {
+		_, _, err := Load([]string{"-db-port", "five"})
+		assert.EqualError(tt, err, `flag -db-port must be an integer, got "five"`)
+	})
+
+	t.Run("Error Secret Flag", func(tt *testing.T) {
+		_, _, err := Load([]string{"-api-secret", "secret"})
+		assert.Error(tt, err)
+	})
+}
+
+func TestLoadTest(t *testing.T) {
+	t.Setenv("DB_HOST", "127.0.0.1")
+	t.Setenv("DB_USER", "postgres")
+	t.Setenv("DB_NAME", "microblog")
+	t.Setenv("TEST_DB_NAME", "microblog_test")
+	t.Setenv("API_SECRET", "secret")
+
+	d, err := LoadTest()
+	assert.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", d.Host)
+	assert.Equal(t, "postgres", d.User)
+	assert.Equal(t, "microblog_test", d.Name)
+	assert.NoError(t, ValidateDatabase(d))
+}
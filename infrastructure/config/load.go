@@ -0,0 +1,255 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv is the environment variable naming the YAML configuration file,
+// unless the -config flag does.
+const FileEnv = "CONFIG_FILE"
+
+// TestPrefix prefixes the environment variables overriding the database
+// settings for the integration tests, as in TEST_DB_NAME.
+const TestPrefix = "TEST_"
+
+// setting is a value of the configuration along with the environment
+// variable and the flag setting it. The secrets have no flag, so they do not
+// show in the list of processes.
+type setting struct {
+	env   string
+	flag  string
+	usage string
+	value value
+}
+
+// value parses the text of a setting into the field it points to.
+type value interface {
+	Set(text string) error
+}
+
+// settings returns the settings of the configuration c.
+func settings(c *Config) []setting {
+	return []setting{
+		{"STORAGE", "storage", "storage of the repositories, postgres or memory", stringValue{&c.Storage}},
+
+		{"DAEMON_PORT", "port", "port the server listens on", intValue{&c.Server.Port}},
+		{"SERVER_READ_TIMEOUT", "read-timeout", "maximum duration to read a request", durationValue{&c.Server.ReadTimeout}},
+		{"SERVER_WRITE_TIMEOUT", "write-timeout", "maximum duration to write a response", durationValue{&c.Server.WriteTimeout}},
+		{"SERVER_IDLE_TIMEOUT", "idle-timeout", "maximum duration a keep-alive connection waits for the next request", durationValue{&c.Server.IdleTimeout}},
+		{"SHUTDOWN_DELAY", "shutdown-delay", "duration the server keeps serving once not ready on shutdown", durationValue{&c.Server.ShutdownDelay}},
+		{"SHUTDOWN_TIMEOUT", "shutdown-timeout", "maximum duration to drain the active requests on shutdown", durationValue{&c.Server.ShutdownTimeout}},
+
+		{"DB_DRIVER", "db-driver", "database driver, postgres", stringValue{&c.Database.Driver}},
+		{"DB_HOST", "db-host", "database host", stringValue{&c.Database.Host}},
+		{"DB_PORT", "db-port", "database port", intValue{&c.Database.Port}},
+		{"DB_USER", "db-user", "database user", stringValue{&c.Database.User}},
+		{"DB_PASSWORD", "", "", stringValue{&c.Database.Password}},
+		{"DB_NAME", "db-name", "database name", stringValue{&c.Database.Name}},
+		{"DB_SSLMODE", "db-sslmode", "database SSL mode, such as disable or verify-full", stringValue{&c.Database.SSLMode}},
+		{"DB_MAX_OPEN_CONNS", "db-max-open-conns", "maximum open connections of the pool, 0 for unlimited", intValue{&c.Database.MaxOpenConns}},
+		{"DB_MAX_IDLE_CONNS", "db-max-idle-conns", "maximum idle connections of the pool", intValue{&c.Database.MaxIdleConns}},
+		{"DB_CONN_MAX_LIFETIME", "db-conn-max-lifetime", "maximum duration a connection is reused, 0 for no limit", durationValue{&c.Database.ConnMaxLifetime}},
+		{"DB_CONN_MAX_IDLE_TIME", "db-conn-max-idle-time", "maximum duration a connection stays idle, 0 for no limit", durationValue{&c.Database.ConnMaxIdleTime}},
+
+		{"API_SECRET", "", "", stringValue{&c.Auth.Secret}},
+
+		{"POST_MAX_LENGTH", "post-max-length", "maximum characters of a post, 0 for no limit", intValue{&c.Posts.MaxLength}},
+		{"POST_MAX_LINKS", "post-max-links", "maximum links of a post, 0 for no limit", intValue{&c.Posts.MaxLinks}},
+		{"POST_MAX_MENTIONS", "post-max-mentions", "maximum mentions of a post, 0 for no limit", intValue{&c.Posts.MaxMentions}},
+
+		{"OTEL_TRACES_EXPORTER", "tracing-exporter", "exporter of the spans, otlp, stdout or none", stringValue{&c.Tracing.Exporter}},
+
+		{"FEATURE_SEARCH", "feature-search", "serve the search of posts and users", boolValue{&c.Features.Search}},
+		{"FEATURE_NOTIFICATIONS", "feature-notifications", "record and serve the notifications", boolValue{&c.Features.Notifications}},
+		{"FEATURE_METRICS", "feature-metrics", "serve the Prometheus metrics", boolValue{&c.Features.Metrics}},
+	}
+}
+
+// Load returns the configuration read from the defaults, the YAML file, the
+// environment and the flags in args, each overriding the ones before. The
+// file is named by the -config flag or else by CONFIG_FILE, if any. It also
+// returns the arguments left after the flags. The configuration is not
+// validated, the caller validates what it uses.
+func Load(args []string) (Config, []string, error) {
+	c := Default()
+	all := settings(&c)
+
+	fs := flag.NewFlagSet("microblog", flag.ContinueOnError)
+	file := fs.String("config", "", "YAML configuration file, overridden by the environment and the flags")
+
+	flags := make(map[string]*raw)
+	for _, s := range all {
+		if s.flag == "" {
+			continue
+		}
+
+		_, boolean := s.value.(boolValue)
+		flags[s.flag] = &raw{boolean: boolean}
+		fs.Var(flags[s.flag], s.flag, s.usage+" ($"+s.env+")")
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return Config{}, nil, err
+	}
+
+	path := *file
+	if path == "" {
+		path = os.Getenv(FileEnv)
+	}
+
+	if path != "" {
+		if err := c.loadFile(path); err != nil {
+			return Config{}, nil, err
+		}
+	}
+
+	if err := fromEnv(all, ""); err != nil {
+		return Config{}, nil, err
+	}
+
+	for _, s := range all {
+		r, ok := flags[s.flag]
+		if !ok || !r.set {
+			continue
+		}
+
+		if err := s.value.Set(r.value); err != nil {
+			return Config{}, nil, fmt.Errorf("flag -%s %w, got %q", s.flag, err, r.value)
+		}
+	}
+
+	return c, fs.Args(), nil
+}
+
+// LoadTest returns the configuration of the test database: the one read from
+// the defaults and the environment, each value overridden by the variable of
+// the same name prefixed with TEST_, so only what differs is set twice.
+func LoadTest() (Database, error) {
+	c := Default()
+
+	var database []setting
+	for _, s := range settings(&c) {
+		if strings.HasPrefix(s.env, "DB_") {
+			database = append(database, s)
+		}
+	}
+
+	if err := fromEnv(database, ""); err != nil {
+		return Database{}, err
+	}
+
+	if err := fromEnv(database, TestPrefix); err != nil {
+		return Database{}, err
+	}
+
+	return c.Database, nil
+}
+
+// loadFile reads the YAML file at path over the configuration. Unknown keys
+// are errors, so a misspelled one does not go unnoticed.
+func (c *Config) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// fromEnv sets the settings from the environment variables of their name
+// prefixed with prefix. Empty variables are ignored.
+func fromEnv(all []setting, prefix string) error {
+	for _, s := range all {
+		name := prefix + s.env
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+
+		if err := s.value.Set(value); err != nil {
+			return fmt.Errorf("%s %w, got %q", name, err, value)
+		}
+	}
+
+	return nil
+}
+
+// raw records the value of a flag, set over the configuration once the file
+// and the environment are read.
+type raw struct {
+	value   string
+	set     bool
+	boolean bool
+}
+
+func (r *raw) Set(value string) error {
+	r.value, r.set = value, true
+	return nil
+}
+
+func (r *raw) String() string { return r.value }
+
+// IsBoolFlag lets the boolean flags go without value.
+func (r *raw) IsBoolFlag() bool { return r.boolean }
+
+// stringValue, intValue, durationValue and boolValue parse a setting into
+// the field they point to.
+type (
+	stringValue   struct{ p *string }
+	intValue      struct{ p *int }
+	durationValue struct{ p *time.Duration }
+	boolValue     struct{ p *bool }
+)
+
+func (v stringValue) Set(value string) error {
+	*v.p = value
+	return nil
+}
+
+func (v intValue) Set(value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return errors.New("must be an integer")
+	}
+
+	*v.p = n
+	return nil
+}
+
+func (v durationValue) Set(value string) error {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return errors.New("must be a duration such as 10s")
+	}
+
+	*v.p = d
+	return nil
+}
+
+func (v boolValue) Set(value string) error {
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return errors.New("must be true or false")
+	}
+
+	*v.p = b
+	return nil
+}
@@ -1,11 +1,12 @@
 package data
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	"io/ioutil"
 	"log"
-	"os"
+	"microblog/infrastructure/config"
+	"microblog/infrastructure/database/migration"
 	"sync"
 
 	// registering database driver
@@ -13,64 +14,65 @@ import (
 )
 
 var (
-	data *Data
-	once sync.Once
+	data    *Data
+	initErr error
+	once    sync.Once
 )
 // Data manages the connection to the database.
 type Data struct {
 	DB *sql.DB
 }
 
-// New returns a new instance of Data with the database connection ready.
-func New() *Data {
-	once.Do(initDB)
-	return data
-}
+// New returns a new instance of Data with the database connection ready,
+// the pending migrations applied.
+func New(cfg config.Database) (*Data, error) {
+	once.Do(func() {
+		data, initErr = initDB(cfg)
+	})
 
-func NewTest() *Data {
-	once.Do(initDBTest)
-	return data
+	return data, initErr
 }
 
-// initialize the data variable with the connection to the database.
-func initDB() {
+// NewTest returns a new instance of Data connected to the test database,
+// configured by config.LoadTest. It exits when the database is not usable.
+func NewTest() *Data {
+	once.Do(func() {
+		cfg, err := config.LoadTest()
+		if err == nil {
+			err = config.ValidateDatabase(cfg)
+		}
 
-	db, err := GetConnection()
-	if err != nil {
-		fmt.Println("Cannot connect to database")
-		log.Fatal("This is the error:", err)
-	} else {
-		fmt.Println("We are connected to the database")
-	}
+		if err == nil {
+			data, err = initDB(cfg)
+		}
 
-	err = MakeMigration(db)
-	if err != nil {
-		log.Fatal("This is the error:", err)
-	}
+		if err != nil {
+			log.Fatal("This is the error:", err)
+		}
+	})
 
-	data = &Data{
-		DB: db,
-	}
+	return data
 }
 
-func initDBTest() {
+// initDB returns the connection to the database, migrated.
+func initDB(cfg config.Database) (*Data, error) {
 
-	db, err := GetConnectionTest()
+	db, err := Open(cfg)
 	if err != nil {
-		fmt.Println("Cannot connect to database test")
-		log.Fatal("This is the error:", err)
-	} else {
-		fmt.Println("We are connected to the database test")
+		return nil, err
 	}
 
-	err = MakeMigrationTest(db)
+	err = migrate(db)
 	if err != nil {
-		log.Fatal("This is the error:", err)
+		_ = db.Close()
+		return nil, err
 	}
 
-	data = &Data{
+	fmt.Println("We are connected to the database", cfg.Name)
+
+	return &Data{
 		DB: db,
-	}
+	}, nil
 }
 
 // Close closes the resources used by data.
@@ -82,56 +84,27 @@ func Close() error {
 	return data.DB.Close()
 }
 
-func GetConnection() (*sql.DB, error) {
-	DbHost := os.Getenv("DB_HOST")
-	DbDriver := os.Getenv("DB_DRIVER")
-	DbUser := os.Getenv("DB_USER")
-	DbPassword := os.Getenv("DB_PASSWORD")
-	DbName := os.Getenv("DB_NAME")
-	DbPort := os.Getenv("DB_PORT")
-
-	uri := fmt.Sprintf("host=%s port=%s user=%s dbname=%s sslmode=disable password=%s", DbHost, DbPort, DbUser, DbName, DbPassword)
-	return sql.Open(DbDriver, uri)
-}
-
-func GetConnectionTest() (*sql.DB, error) {
-	DbHost := os.Getenv("TestDbHost")
-	DbDriver := os.Getenv("TestDbDriver")
-	DbUser := os.Getenv("TestDbUser")
-	DbPassword := os.Getenv("TestDbPassword")
-	DbName := os.Getenv("TestDbName")
-	DbPort := os.Getenv("TestDbPort")
-
-	uri := fmt.Sprintf("host=%s port=%s user=%s dbname=%s sslmode=disable password=%s", DbHost, DbPort, DbUser, DbName, DbPassword)
-	return sql.Open(DbDriver, uri)
-}
-
-// MakeMigration creates all the tables in the database
-func MakeMigration(db *sql.DB) error {
-	b, err := ioutil.ReadFile("./infrastructure/database/models.sql")
+// Open returns the connection pool of the database, sized as configured.
+func Open(cfg config.Database) (*sql.DB, error) {
+	db, err := sql.Open(cfg.Driver, cfg.DSN())
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	rows, err := db.Query(string(b))
-	if err != nil {
-		return err
-	}
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 
-	return rows.Close()
+	return db, nil
 }
 
-// MakeMigrationTest creates all the tables in the database
-func MakeMigrationTest(db *sql.DB) error {
-	b, err := ioutil.ReadFile("../../infrastructure/database/models.sql")
-	if err != nil {
-		return err
-	}
-
-	rows, err := db.Query(string(b))
+// migrate applies the pending migrations embedded in the binary.
+func migrate(db *sql.DB) error {
+	m, err := migration.New(db)
 	if err != nil {
 		return err
 	}
 
-	return rows.Close()
+	return m.Up(context.Background())
 }
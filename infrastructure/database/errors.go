@@ -0,0 +1,109 @@
+package data
+
+import (
+	"database/sql"
+	"errors"
+	"microblog/domain/shared/errs"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Codes of the PostgreSQL errors translated into domain errors.
+// See https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	codeUniqueViolation     pq.ErrorCode = "23505"
+	codeForeignKeyViolation pq.ErrorCode = "23503"
+	codeCheckViolation      pq.ErrorCode = "23514"
+	codeNotNullViolation    pq.ErrorCode = "23502"
+	codeStringTooLong       pq.ErrorCode = "22001"
+)
+
+// constraints maps the constraints of the schema to the message shown to
+// clients when a statement violates them.
+var constraints = map[string]string{
+	"users_username_key":            "username already exists",
+	"users_email_key":               "email already exists",
+	"refresh_tokens_token_hash_key": "refresh token already exists",
+	"fk_posts_users":                "user not found",
+	"fk_posts_in_reply_to":          "post not found",
+	"fk_posts_repost_of":            "post not found",
+	"fk_posts_quote_of":             "post not found",
+	"fk_post_mentions_users":        "mentioned user not found",
+	"uq_posts_reposts":              "post already reposted",
+	"fk_likes_users":                "user not found",
+	"fk_likes_posts":                "post not found",
+	"fk_refresh_tokens_users":       "user not found",
+	"fk_follows_follower":           "user not found",
+	"fk_follows_following":          "user not found",
+	"fk_notifications_users":        "user not found",
+	"fk_notifications_actors":       "user not found",
+	"fk_notifications_posts":        "post not found",
+	"ck_follows_self":               "a user can not follow itself",
+}
+
+// Error translates an error returned by the database into a domain error:
+// sql.ErrNoRows becomes a not found error with the notFound message and
+// constraint violations become conflict, not found or validation errors.
+// Any other error is returned as is.
+func Error(err error, notFound string) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return errs.Wrap(errs.ErrNotFound, notFound, err)
+	}
+
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	message, ok := constraints[pqErr.Constraint]
+
+	switch pqErr.Code {
+	case codeUniqueViolation:
+		if !ok {
+			message = "resource already exists"
+		}
+
+		return errs.Wrap(errs.ErrConflict, message, err)
+
+	case codeForeignKeyViolation:
+		// deleting a row other rows still reference.
+		if strings.HasPrefix(pqErr.Message, "update or delete") {
+			return errs.Wrap(errs.ErrConflict, "resource is still referenced by "+pqErr.Table, err)
+		}
+
+		if !ok {
+			message = "referenced resource not found"
+		}
+
+		return errs.Wrap(errs.ErrNotFound, message, err)
+
+	case codeCheckViolation, codeNotNullViolation, codeStringTooLong:
+		if !ok {
+			message = pqErr.Message
+		}
+
+		return errs.Wrap(errs.ErrValidation, message, err)
+	}
+
+	return err
+}
+
+// Affected returns a not found error with the notFound message when the
+// statement that produced result changed no row.
+func Affected(result sql.Result, notFound string) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return errs.NotFound(notFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package data
+
+import (
+	"database/sql"
+	"errors"
+	"microblog/domain/shared/errs"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Err     error
+		Kind    error
+		Message string
+	}{
+		{Name: "No Rows", Err: sql.ErrNoRows, Kind: errs.ErrNotFound, Message: "user not found"},
+		{Name: "Unique Username", Err: &pq.Error{Code: codeUniqueViolation, Constraint: "users_username_key"}, Kind: errs.ErrConflict, Message: "username already exists"},
+		{Name: "Unique Unknown", Err: &pq.Error{Code: codeUniqueViolation}, Kind: errs.ErrConflict, Message: "resource already exists"},
+		{Name: "Missing Reference", Err: &pq.Error{Code: codeForeignKeyViolation, Constraint: "fk_posts_users", Message: "insert or update on table \"posts\" violates foreign key constraint"}, Kind: errs.ErrNotFound, Message: "user not found"},
+		{Name: "Still Referenced", Err: &pq.Error{Code: codeForeignKeyViolation, Table: "posts", Message: "update or delete on table \"users\" violates foreign key constraint"}, Kind: errs.ErrConflict, Message: "resource is still referenced by posts"},
+		{Name: "Check", Err: &pq.Error{Code: codeCheckViolation, Constraint: "ck_follows_self"}, Kind: errs.ErrValidation, Message: "a user can not follow itself"},
+	}
+
+	for _, test := range tests {
+		err := Error(test.Err, "user not found")
+		assert.True(t, errors.Is(err, test.Kind), test.Name)
+		assert.Equal(t, test.Message, err.Error(), test.Name)
+	}
+
+	outage := errors.New("connection refused")
+	assert.Equal(t, outage, Error(outage, "user not found"))
+	assert.Nil(t, Error(nil, "user not found"))
+}
+
+func TestAffected(t *testing.T) {
+	err := Affected(sqlmock.NewResult(0, 0), "post not found")
+	assert.True(t, errors.Is(err, errs.ErrNotFound))
+
+	assert.NoError(t, Affected(sqlmock.NewResult(0, 1), "post not found"))
+}
@@ -0,0 +1,64 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	postDomain "microblog/domain/post/domain"
+
+	"github.com/lib/pq"
+)
+
+const (
+	// selectPostBodies is a query that selects the body of every post.
+	selectPostBodies = "SELECT id, body FROM posts ORDER BY id;"
+
+	// insertPostTags is a query that indexes the hashtags of a post.
+	insertPostTags = "INSERT INTO post_tags (post_id, tag) SELECT $1, unnest($2::text[]) ON CONFLICT DO NOTHING;"
+)
+
+// backfills holds the backfill of the migrations by version.
+var backfills = map[uint]func(ctx context.Context, tx *sql.Tx) error{
+	8: backfillPostTags,
+}
+
+// backfillPostTags indexes the hashtags of the posts created before the
+// post_tags table. The tags are extracted in Go, the same way as for new
+// posts, since the regular expressions of PostgreSQL have no Unicode classes.
+func backfillPostTags(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, selectPostBodies)
+	if err != nil {
+		return err
+	}
+
+	tags := make(map[uint][]string)
+	var ids []uint
+	for rows.Next() {
+		var id uint
+		var body string
+		if err := rows.Scan(&id, &body); err != nil {
+			_ = rows.Close()
+			return err
+		}
+
+		if t := (postDomain.Post{Body: body}).Tags(); len(t) > 0 {
+			tags[id] = t
+			ids = append(ids, id)
+		}
+	}
+
+	if err := rows.Close(); err != nil {
+		return err
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if _, err := tx.ExecContext(ctx, insertPostTags, id, pq.Array(tags[id])); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackfillPostTags(t *testing.T) {
+
+	t.Run("Tags Extracted In Go", func(tt *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(tt, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows([]string{"id", "body"}).
+			AddRow(1, "#Café con #ñandú y #café").
+			AddRow(2, "sin etiquetas, C# ni a.com/#x").
+			AddRow(3, "#नमस्ते")
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(selectPostBodies)).WillReturnRows(rows)
+		mock.ExpectExec(regexp.QuoteMeta(insertPostTags)).WithArgs(1, pq.Array([]string{"café", "ñandú"})).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(regexp.QuoteMeta(insertPostTags)).WithArgs(3, pq.Array([]string{"नमस्ते"})).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		tx, err := db.Begin()
+		assert.NoError(tt, err)
+		assert.NoError(tt, backfillPostTags(context.Background(), tx))
+		assert.NoError(tt, tx.Commit())
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error Select", func(tt *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(tt, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(selectPostBodies)).WillReturnError(errors.New("error sql"))
+
+		tx, err := db.Begin()
+		assert.NoError(tt, err)
+		assert.Error(tt, backfillPostTags(context.Background(), tx))
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,81 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+)
+
+// ErrUsage is returned when the migrate command arguments are not valid.
+var ErrUsage = errors.New("usage: migrate up | down | to <version> | status")
+
+// Run executes the migrate command described by args and writes its output
+// to out.
+func Run(ctx context.Context, m *Migrator, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return ErrUsage
+	}
+
+	switch args[0] {
+	case "up":
+		if len(args) != 1 {
+			return ErrUsage
+		}
+
+		return m.Up(ctx)
+
+	case "down":
+		if len(args) != 1 {
+			return ErrUsage
+		}
+
+		return m.Down(ctx)
+
+	case "to":
+		if len(args) != 2 {
+			return ErrUsage
+		}
+
+		version, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			return ErrUsage
+		}
+
+		return m.To(ctx, uint(version))
+
+	case "status":
+		if len(args) != 1 {
+			return ErrUsage
+		}
+
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		return printStatus(out, statuses)
+
+	default:
+		return ErrUsage
+	}
+}
+
+// printStatus writes one line per migration.
+func printStatus(out io.Writer, statuses []Status) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+
+	for _, s := range statuses {
+		state, appliedAt := "pending", ""
+		if s.Applied {
+			state, appliedAt = "applied", s.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+
+		fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+	}
+
+	return w.Flush()
+}
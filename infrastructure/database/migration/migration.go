@@ -0,0 +1,378 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// files holds the migrations shipped with the binary. Each migration is a
+// pair of files named <version>_<name>.up.sql and <version>_<name>.down.sql.
+//
+//go:embed migrations/*.sql
+var files embed.FS
+
+// lockKey identifies the advisory lock held while migrating, so only one
+// runner changes the schema at a time.
+const lockKey int64 = 4821936
+
+const (
+	// createSchemaMigrations is a query that creates the table tracking the applied migrations.
+	createSchemaMigrations = `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version bigint NOT NULL,
+		name VARCHAR(255) NOT NULL,
+		applied_at timestamp NOT NULL DEFAULT now(),
+		CONSTRAINT pk_schema_migrations PRIMARY KEY(version)
+	);`
+
+	// selectApplied is a query that selects the applied migrations.
+	selectApplied = "SELECT version, applied_at FROM schema_migrations ORDER BY version;"
+
+	// insertApplied is a query that records a migration as applied.
+	insertApplied = "INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3);"
+
+	// deleteApplied is a query that removes the record of an applied migration.
+	deleteApplied = "DELETE FROM schema_migrations WHERE version = $1;"
+
+	// lock and unlock take and release the session advisory lock.
+	lock   = "SELECT pg_advisory_lock($1);"
+	unlock = "SELECT pg_advisory_unlock($1);"
+)
+
+var (
+	// ErrInvalidName is returned when a migration file does not follow the naming scheme.
+	ErrInvalidName = errors.New("invalid migration file name")
+
+	// ErrUnknownVersion is returned when a version does not match any migration.
+	ErrUnknownVersion = errors.New("unknown migration version")
+
+	// ErrIrreversible is returned when rolling back a migration without down script.
+	ErrIrreversible = errors.New("migration has no down script")
+
+	// ErrNoChange is returned by Down when no migration is applied.
+	ErrNoChange = errors.New("no migration to roll back")
+)
+
+// fileName matches <version>_<name>.<up|down>.sql.
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a numbered change of the database schema.
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+
+	// Backfill, if set, fills the data the up script cannot compute in SQL.
+	// It runs after the up script, in the same transaction.
+	Backfill func(ctx context.Context, tx *sql.Tx) error
+}
+
+// Status is a migration along with whether it has been applied.
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Load reads the migrations stored in dir, sorted by version.
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[uint]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidName, entry.Name())
+		}
+
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidName, entry.Name())
+		}
+
+		b, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[uint(version)]
+		if !ok {
+			m = &Migration{Version: uint(version), Name: match[2]}
+			byVersion[uint(version)] = m
+		}
+
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("%w: version %d is used by %s and %s", ErrInvalidName, version, m.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			m.Up = string(b)
+		} else {
+			m.Down = string(b)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("%w: version %d has no up script", ErrInvalidName, m.Version)
+		}
+
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Migrator applies and rolls back migrations on a PostgreSQL database.
+type Migrator struct {
+	DB         *sql.DB
+	Migrations []Migration
+	now        func() time.Time
+}
+
+// New returns a Migrator for the migrations embedded in the binary.
+func New(db *sql.DB) (*Migrator, error) {
+	migrations, err := Load(files, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range migrations {
+		migrations[i].Backfill = backfills[migrations[i].Version]
+	}
+
+	return &Migrator{DB: db, Migrations: migrations, now: time.Now}, nil
+}
+
+// Up applies every pending migration.
+func (m *Migrator) Up(ctx context.Context) error {
+	if len(m.Migrations) == 0 {
+		return nil
+	}
+
+	return m.To(ctx, m.Migrations[len(m.Migrations)-1].Version)
+}
+
+// Down rolls back the last applied migration.
+func (m *Migrator) Down(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.Migrations) - 1; i >= 0; i-- {
+			if _, ok := applied[m.Migrations[i].Version]; ok {
+				return m.rollback(ctx, conn, m.Migrations[i])
+			}
+		}
+
+		return ErrNoChange
+	})
+}
+
+// To applies or rolls back migrations until version is the last applied
+// one. Version 0 rolls back every migration.
+func (m *Migrator) To(ctx context.Context, version uint) error {
+	if version != 0 && !m.known(version) {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.Migrations) - 1; i >= 0; i-- {
+			migration := m.Migrations[i]
+			if _, ok := applied[migration.Version]; !ok || migration.Version <= version {
+				continue
+			}
+
+			if err := m.rollback(ctx, conn, migration); err != nil {
+				return err
+			}
+		}
+
+		for _, migration := range m.Migrations {
+			if _, ok := applied[migration.Version]; ok || migration.Version > version {
+				continue
+			}
+
+			if err := m.apply(ctx, conn, migration); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Status returns every migration along with whether it has been applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.Migrations {
+			appliedAt, ok := applied[migration.Version]
+			statuses = append(statuses, Status{Migration: migration, Applied: ok, AppliedAt: appliedAt})
+		}
+
+		return nil
+	})
+
+	return statuses, err
+}
+
+// Pending returns the migrations not applied yet. It reads the applied ones
+// without taking the lock, so it never waits on a running migration, and
+// fails when the schema_migrations table does not exist.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	applied, err := m.applied(ctx, m.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, migration := range m.Migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+
+	return pending, nil
+}
+
+// known reports whether version matches a migration.
+func (m *Migrator) known(version uint) bool {
+	for _, migration := range m.Migrations {
+		if migration.Version == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+// withLock runs fn on a single connection holding the advisory lock, after
+// making sure the schema_migrations table exists.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.DB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, lock, lockKey); err != nil {
+		return err
+	}
+
+	defer func() {
+		_, _ = conn.ExecContext(context.Background(), unlock, lockKey)
+	}()
+
+	if _, err := conn.ExecContext(ctx, createSchemaMigrations); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+// querier runs queries on a connection or on the pool.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// applied returns the applied versions along with when they were applied.
+func (m *Migrator) applied(ctx context.Context, conn querier) (map[uint]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, selectApplied)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	applied := make(map[uint]time.Time)
+	for rows.Next() {
+		var version uint
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+
+		applied[version] = appliedAt
+	}
+
+	return applied, rows.Err()
+}
+
+// apply runs the up script and the backfill of the migration and records it
+// in the same transaction.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	return m.inTx(ctx, conn, migration, migration.Up, migration.Backfill, insertApplied, migration.Version, migration.Name, m.now())
+}
+
+// rollback runs the down script of the migration and removes its record in
+// the same transaction.
+func (m *Migrator) rollback(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	if migration.Down == "" {
+		return fmt.Errorf("%w: %d_%s", ErrIrreversible, migration.Version, migration.Name)
+	}
+
+	return m.inTx(ctx, conn, migration, migration.Down, nil, deleteApplied, migration.Version)
+}
+
+// inTx runs script, then fill if set, followed by the tracking query in a
+// transaction.
+func (m *Migrator) inTx(ctx context.Context, conn *sql.Conn, migration Migration, script string, fill func(ctx context.Context, tx *sql.Tx) error, track string, args ...interface{}) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	if fill != nil {
+		if err := fill(ctx, tx); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("migration %d_%s: backfill: %w", migration.Version, migration.Name, err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, track, args...); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,284 @@
+package migration
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// newMockMigrator returns a Migrator with two migrations backed by sqlmock.
+func newMockMigrator(t *testing.T) (*Migrator, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	migrations := []Migration{
+		{Version: 1, Name: "create_users", Up: "CREATE TABLE users", Down: "DROP TABLE users"},
+		{Version: 2, Name: "create_posts", Up: "CREATE TABLE posts", Down: "DROP TABLE posts"},
+	}
+
+	now := time.Date(2020, 9, 4, 10, 30, 0, 0, time.UTC)
+
+	return &Migrator{DB: db, Migrations: migrations, now: func() time.Time { return now }}, mock
+}
+
+// expectLock expects the advisory lock and the tracking table creation,
+// then returns the applied versions.
+func expectLock(mock sqlmock.Sqlmock, applied ...uint) {
+	mock.ExpectExec(regexp.QuoteMeta(lock)).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	rows := sqlmock.NewRows([]string{"version", "applied_at"})
+	for _, version := range applied {
+		rows.AddRow(version, time.Now())
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(selectApplied)).WillReturnRows(rows)
+}
+
+// expectUnlock expects the advisory lock release.
+func expectUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta(unlock)).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func TestLoad(t *testing.T) {
+
+	t.Run("Embedded Migrations", func(tt *testing.T) {
+		migrations, err := Load(files, "migrations")
+		assert.NoError(tt, err)
+		assert.NotEmpty(tt, migrations)
+
+		for i, m := range migrations {
+			assert.Equal(tt, uint(i+1), m.Version)
+			assert.NotEmpty(tt, m.Up)
+			assert.NotEmpty(tt, m.Down)
+		}
+	})
+
+	t.Run("Sorted By Version", func(tt *testing.T) {
+		fsys := fstest.MapFS{
+			"m/0010_second.up.sql": {Data: []byte("SELECT 2")},
+			"m/0002_first.up.sql":  {Data: []byte("SELECT 1")},
+		}
+
+		migrations, err := Load(fsys, "m")
+		assert.NoError(tt, err)
+		assert.Len(tt, migrations, 2)
+		assert.Equal(tt, "first", migrations[0].Name)
+		assert.Equal(tt, "second", migrations[1].Name)
+		assert.Empty(tt, migrations[0].Down)
+	})
+
+	t.Run("Error Invalid Names", func(tt *testing.T) {
+		tests := []fstest.MapFS{
+			{"m/create_users.up.sql": {Data: []byte("SELECT 1")}},
+			{"m/0000_zero.up.sql": {Data: []byte("SELECT 1")}},
+			{"m/0001_only.down.sql": {Data: []byte("SELECT 1")}},
+			{"m/0001_a.up.sql": {Data: []byte("SELECT 1")}, "m/0001_b.down.sql": {Data: []byte("SELECT 1")}},
+		}
+
+		for _, fsys := range tests {
+			_, err := Load(fsys, "m")
+			assert.True(tt, errors.Is(err, ErrInvalidName), err)
+		}
+	})
+}
+
+func TestNew(t *testing.T) {
+
+	t.Run("Backfills Attached", func(tt *testing.T) {
+		m, err := New(nil)
+		assert.NoError(tt, err)
+
+		for _, migration := range m.Migrations {
+			_, ok := backfills[migration.Version]
+			assert.Equal(tt, ok, migration.Backfill != nil, "%d_%s", migration.Version, migration.Name)
+		}
+	})
+}
+
+func TestMigrator_Up(t *testing.T) {
+
+	t.Run("Apply Pending Migrations", func(tt *testing.T) {
+		m, mock := newMockMigrator(tt)
+
+		expectLock(mock, 1)
+		mock.ExpectBegin()
+		mock.ExpectExec("CREATE TABLE posts").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(insertApplied)).WithArgs(2, "create_posts", m.now()).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		expectUnlock(mock)
+
+		assert.NoError(tt, m.Up(context.Background()))
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Backfill In Transaction", func(tt *testing.T) {
+		m, mock := newMockMigrator(tt)
+		m.Migrations[1].Backfill = func(ctx context.Context, tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, "UPDATE posts")
+			return err
+		}
+
+		expectLock(mock, 1)
+		mock.ExpectBegin()
+		mock.ExpectExec("CREATE TABLE posts").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE posts").WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectExec(regexp.QuoteMeta(insertApplied)).WithArgs(2, "create_posts", m.now()).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		expectUnlock(mock)
+
+		assert.NoError(tt, m.Up(context.Background()))
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error Backfill Rolls Back", func(tt *testing.T) {
+		m, mock := newMockMigrator(tt)
+		m.Migrations[1].Backfill = func(ctx context.Context, tx *sql.Tx) error {
+			return errors.New("error backfill")
+		}
+
+		expectLock(mock, 1)
+		mock.ExpectBegin()
+		mock.ExpectExec("CREATE TABLE posts").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+		expectUnlock(mock)
+
+		err := m.Up(context.Background())
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "2_create_posts: backfill")
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error Rolls Back Failed Migration", func(tt *testing.T) {
+		m, mock := newMockMigrator(tt)
+
+		expectLock(mock)
+		mock.ExpectBegin()
+		mock.ExpectExec("CREATE TABLE users").WillReturnError(errors.New("error sql"))
+		mock.ExpectRollback()
+		expectUnlock(mock)
+
+		err := m.Up(context.Background())
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "1_create_users")
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMigrator_Down(t *testing.T) {
+
+	t.Run("Roll Back Last Migration", func(tt *testing.T) {
+		m, mock := newMockMigrator(tt)
+
+		expectLock(mock, 1, 2)
+		mock.ExpectBegin()
+		mock.ExpectExec("DROP TABLE posts").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(deleteApplied)).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		expectUnlock(mock)
+
+		assert.NoError(tt, m.Down(context.Background()))
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error Nothing Applied", func(tt *testing.T) {
+		m, mock := newMockMigrator(tt)
+
+		expectLock(mock)
+		expectUnlock(mock)
+
+		assert.Equal(tt, ErrNoChange, m.Down(context.Background()))
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMigrator_To(t *testing.T) {
+
+	t.Run("Error Unknown Version", func(tt *testing.T) {
+		m, mock := newMockMigrator(tt)
+
+		err := m.To(context.Background(), 7)
+		assert.True(tt, errors.Is(err, ErrUnknownVersion))
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Roll Back Everything", func(tt *testing.T) {
+		m, mock := newMockMigrator(tt)
+
+		expectLock(mock, 1, 2)
+		for _, migration := range []Migration{m.Migrations[1], m.Migrations[0]} {
+			mock.ExpectBegin()
+			mock.ExpectExec(migration.Down).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(regexp.QuoteMeta(deleteApplied)).WithArgs(migration.Version).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+		}
+		expectUnlock(mock)
+
+		assert.NoError(tt, m.To(context.Background(), 0))
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMigrator_Pending(t *testing.T) {
+
+	t.Run("Without Lock", func(tt *testing.T) {
+		m, mock := newMockMigrator(tt)
+
+		rows := sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, m.now())
+		mock.ExpectQuery(regexp.QuoteMeta(selectApplied)).WillReturnRows(rows)
+
+		pending, err := m.Pending(context.Background())
+		assert.NoError(tt, err)
+		assert.Equal(tt, m.Migrations[1:], pending)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error Not Tracked", func(tt *testing.T) {
+		m, mock := newMockMigrator(tt)
+
+		mock.ExpectQuery(regexp.QuoteMeta(selectApplied)).WillReturnError(errors.New(`relation "schema_migrations" does not exist`))
+
+		_, err := m.Pending(context.Background())
+		assert.Error(tt, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRun(t *testing.T) {
+
+	t.Run("Error Usage", func(tt *testing.T) {
+		m, _ := newMockMigrator(tt)
+
+		for _, args := range [][]string{nil, {"sideways"}, {"to"}, {"to", "latest"}, {"up", "2"}} {
+			assert.Equal(tt, ErrUsage, Run(context.Background(), m, args, &bytes.Buffer{}), args)
+		}
+	})
+
+	t.Run("Status", func(tt *testing.T) {
+		m, mock := newMockMigrator(tt)
+
+		expectLock(mock, 1)
+		expectUnlock(mock)
+
+		var out bytes.Buffer
+		assert.NoError(tt, Run(context.Background(), m, []string{"status"}, &out))
+		assert.NoError(tt, mock.ExpectationsWereMet())
+
+		assert.Regexp(tt, `0001\s+create_users\s+applied`, out.String())
+		assert.Regexp(tt, `0002\s+create_posts\s+pending`, out.String())
+	})
+}
@@ -6,17 +6,11 @@ CREATE TABLE IF NOT EXISTS users (
     password varchar(256) NOT NULL,
     email VARCHAR(150) NOT NULL UNIQUE,
     picture VARCHAR(256) DEFAULT 'https://placekitten.com/g/300/300',
+    role VARCHAR(20) NOT NULL DEFAULT 'user',
     created_at timestamp DEFAULT now(),
     updated_at timestamp NOT NULL,
     CONSTRAINT pk_users PRIMARY KEY(id)
 );
 
-CREATE TABLE IF NOT EXISTS posts (
-    id serial NOT NULL,
-    user_id int NOT NULL,
-    body text NOT NULL,
-    created_at timestamp DEFAULT now(),
-    updated_at timestamp NOT NULL,
-    CONSTRAINT pk_notes PRIMARY KEY(id),
-    CONSTRAINT fk_posts_users FOREIGN KEY(user_id) REFERENCES users(id)
-);
+-- Databases created before roles existed already have the users table.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'user';
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS posts (
+    id serial NOT NULL,
+    user_id int NOT NULL,
+    body text NOT NULL,
+    created_at timestamp DEFAULT now(),
+    updated_at timestamp NOT NULL,
+    CONSTRAINT pk_notes PRIMARY KEY(id),
+    CONSTRAINT fk_posts_users FOREIGN KEY(user_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_posts_user_id_created_at ON posts(user_id, created_at DESC, id DESC);
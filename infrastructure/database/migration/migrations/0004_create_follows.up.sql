@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS follows (
+    follower_id int NOT NULL,
+    following_id int NOT NULL,
+    created_at timestamp DEFAULT now(),
+    CONSTRAINT pk_follows PRIMARY KEY(follower_id, following_id),
+    CONSTRAINT fk_follows_follower FOREIGN KEY(follower_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_follows_following FOREIGN KEY(following_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT ck_follows_self CHECK (follower_id <> following_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_follows_following_id ON follows(following_id);
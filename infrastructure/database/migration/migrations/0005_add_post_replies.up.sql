@@ -0,0 +1,5 @@
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS in_reply_to int;
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS fk_posts_in_reply_to;
+ALTER TABLE posts ADD CONSTRAINT fk_posts_in_reply_to FOREIGN KEY(in_reply_to) REFERENCES posts(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_posts_in_reply_to ON posts(in_reply_to);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS likes (
+    user_id int NOT NULL,
+    post_id int NOT NULL,
+    created_at timestamp DEFAULT now(),
+    CONSTRAINT pk_likes PRIMARY KEY(user_id, post_id),
+    CONSTRAINT fk_likes_users FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_likes_posts FOREIGN KEY(post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_likes_post_id_created_at ON likes(post_id, created_at DESC, user_id DESC);
+CREATE INDEX IF NOT EXISTS idx_likes_user_id_created_at ON likes(user_id, created_at DESC, post_id DESC);
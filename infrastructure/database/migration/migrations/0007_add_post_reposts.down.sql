@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_posts_quote_of;
+DROP INDEX IF EXISTS uq_posts_reposts;
+ALTER TABLE posts DROP COLUMN IF EXISTS quote_of;
+ALTER TABLE posts DROP COLUMN IF EXISTS repost_of;
@@ -0,0 +1,9 @@
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS repost_of int;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS quote_of int;
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS fk_posts_repost_of;
+ALTER TABLE posts ADD CONSTRAINT fk_posts_repost_of FOREIGN KEY(repost_of) REFERENCES posts(id) ON DELETE CASCADE;
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS fk_posts_quote_of;
+ALTER TABLE posts ADD CONSTRAINT fk_posts_quote_of FOREIGN KEY(quote_of) REFERENCES posts(id) ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_posts_reposts ON posts(user_id, repost_of) WHERE repost_of IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_posts_quote_of ON posts(quote_of);
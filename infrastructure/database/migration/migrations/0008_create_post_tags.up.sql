@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS post_tags (
+    post_id int NOT NULL,
+    tag varchar(100) NOT NULL,
+    CONSTRAINT pk_post_tags PRIMARY KEY(post_id, tag),
+    CONSTRAINT fk_post_tags_posts FOREIGN KEY(post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_tags_tag ON post_tags(tag);
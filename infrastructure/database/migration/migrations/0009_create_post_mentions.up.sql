@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS post_mentions (
+    post_id int NOT NULL,
+    user_id int NOT NULL,
+    start_offset int NOT NULL,
+    end_offset int NOT NULL,
+    CONSTRAINT pk_post_mentions PRIMARY KEY(post_id, start_offset),
+    CONSTRAINT fk_post_mentions_posts FOREIGN KEY(post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    CONSTRAINT fk_post_mentions_users FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_mentions_user_id ON post_mentions(user_id);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS notifications (
+    id serial NOT NULL,
+    user_id int NOT NULL,
+    actor_id int NOT NULL,
+    kind varchar(20) NOT NULL,
+    post_id int,
+    read_at timestamp,
+    created_at timestamp DEFAULT now(),
+    CONSTRAINT pk_notifications PRIMARY KEY(id),
+    CONSTRAINT fk_notifications_users FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_notifications_actors FOREIGN KEY(actor_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_notifications_posts FOREIGN KEY(post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_notifications ON notifications(user_id, actor_id, kind, COALESCE(post_id, 0));
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id_created_at ON notifications(user_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications(user_id) WHERE read_at IS NULL;
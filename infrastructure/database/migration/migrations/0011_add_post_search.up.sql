@@ -0,0 +1,5 @@
+-- the simple configuration lower cases the words without stemming them, whatever the language
+-- of the post. The generated column indexes the posts created before it too.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS search tsvector GENERATED ALWAYS AS (to_tsvector('simple', body)) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_posts_search ON posts USING GIN(search);
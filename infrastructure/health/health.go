@@ -0,0 +1,190 @@
+// Package health serves the liveness and readiness probes of the server.
+package health
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"microblog/domain/shared/response"
+	"microblog/infrastructure/database/migration"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout bounds how long a probe may take before its dependency is
+// reported down.
+const DefaultTimeout = 2 * time.Second
+
+// States of the server and of its dependencies.
+const (
+	StatusOK       = "ok"
+	StatusReady    = "ready"
+	StatusNotReady = "not ready"
+	StatusUp       = "up"
+	StatusDown     = "down"
+)
+
+// ErrShuttingDown is reported by the server probe once it stops serving.
+var ErrShuttingDown = errors.New("server is not serving")
+
+// Probe checks a dependency. It returns details on its state, if any, and an
+// error when the dependency is not usable.
+type Probe func(ctx context.Context) (interface{}, error)
+
+// Check is the state of a dependency.
+type Check struct {
+	Status  string      `json:"status"`
+	Details interface{} `json:"details,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// Report is the state of the server, ready only when every dependency is up.
+type Report struct {
+	Status string           `json:"status"`
+	Checks map[string]Check `json:"checks"`
+}
+
+// Checker runs the probes of the dependencies the server needs to serve.
+type Checker struct {
+	Timeout time.Duration
+
+	names  []string
+	probes map[string]Probe
+}
+
+// NewChecker returns a Checker without probes, bounded by DefaultTimeout.
+func NewChecker() *Checker {
+	return &Checker{Timeout: DefaultTimeout, probes: make(map[string]Probe)}
+}
+
+// Add registers the probe of a dependency, replacing the one of the same
+// name.
+func (c *Checker) Add(name string, p Probe) {
+	if _, ok := c.probes[name]; !ok {
+		c.names = append(c.names, name)
+		sort.Strings(c.names)
+	}
+
+	c.probes[name] = p
+}
+
+// Check runs every probe concurrently, each bounded by the timeout.
+func (c *Checker) Check(ctx context.Context) Report {
+	report := Report{Status: StatusReady, Checks: make(map[string]Check, len(c.names))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, name := range c.names {
+		wg.Add(1)
+		go func(name string, p Probe) {
+			defer wg.Done()
+
+			check := run(ctx, c.Timeout, p)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			report.Checks[name] = check
+			if check.Status != StatusUp {
+				report.Status = StatusNotReady
+			}
+		}(name, c.probes[name])
+	}
+
+	wg.Wait()
+
+	return report
+}
+
+// run runs the probe with the timeout.
+func run(ctx context.Context, timeout time.Duration, p Probe) Check {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	details, err := p(ctx)
+	if err != nil {
+		return Check{Status: StatusDown, Details: details, Error: err.Error()}
+	}
+
+	return Check{Status: StatusUp, Details: details}
+}
+
+// LivenessHandler reports that the process serves requests. It checks no
+// dependency, a failing database does not call for a restart.
+func LivenessHandler(w http.ResponseWriter, r *http.Request) {
+	_ = response.JSON(w, r, http.StatusOK, map[string]string{"status": StatusOK})
+}
+
+// ReadinessHandler reports whether the dependencies are up, with 503 Service
+// Unavailable when one is not.
+func (c *Checker) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	report := c.Check(r.Context())
+
+	status := http.StatusOK
+	if report.Status != StatusReady {
+		status = http.StatusServiceUnavailable
+	}
+
+	_ = response.JSON(w, r, status, report)
+}
+
+// Serving returns the probe of the server itself, down once it starts
+// shutting down.
+func Serving(ready func() bool) Probe {
+	return func(ctx context.Context) (interface{}, error) {
+		if !ready() {
+			return nil, ErrShuttingDown
+		}
+
+		return nil, nil
+	}
+}
+
+// PoolStats are the details of the database probe.
+type PoolStats struct {
+	OpenConnections int `json:"open_connections"`
+	InUse           int `json:"in_use"`
+	Idle            int `json:"idle"`
+}
+
+// Database returns the probe pinging the database.
+func Database(db *sql.DB) Probe {
+	return func(ctx context.Context) (interface{}, error) {
+		err := db.PingContext(ctx)
+
+		stats := db.Stats()
+		return PoolStats{OpenConnections: stats.OpenConnections, InUse: stats.InUse, Idle: stats.Idle}, err
+	}
+}
+
+// MigrationStatus are the details of the migrations probe: the version of
+// the schema the binary expects and how many migrations it lacks.
+type MigrationStatus struct {
+	Version uint `json:"version"`
+	Pending int  `json:"pending"`
+}
+
+// Migrations returns the probe of the database schema, down while migrations
+// are pending.
+func Migrations(m *migration.Migrator) Probe {
+	return func(ctx context.Context) (interface{}, error) {
+		pending, err := m.Pending(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		status := MigrationStatus{Pending: len(pending)}
+		if n := len(m.Migrations); n > 0 {
+			status.Version = m.Migrations[n-1].Version
+		}
+
+		if len(pending) > 0 {
+			return status, fmt.Errorf("%d pending migrations, first %d_%s", len(pending), pending[0].Version, pending[0].Name)
+		}
+
+		return status, nil
+	}
+}
@@ -0,0 +1,137 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"microblog/infrastructure/database/migration"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// up is a probe of a dependency that is always up.
+func up(ctx context.Context) (interface{}, error) {
+	return nil, nil
+}
+
+// ready serves the readiness probe of the checker and decodes its report.
+func ready(t *testing.T, c *Checker) (int, Report) {
+	response := httptest.NewRecorder()
+	c.ReadinessHandler(response, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	var report Report
+	if err := json.NewDecoder(response.Body).Decode(&report); err != nil {
+		t.Fatal(err)
+	}
+
+	return response.Code, report
+}
+
+func TestLivenessHandler(t *testing.T) {
+	response := httptest.NewRecorder()
+	LivenessHandler(response, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, response.Body.String())
+}
+
+func TestChecker_ReadinessHandler(t *testing.T) {
+	t.Run("Ready", func(tt *testing.T) {
+		c := NewChecker()
+		c.Add("server", Serving(func() bool { return true }))
+		c.Add("cache", up)
+
+		code, report := ready(tt, c)
+		assert.Equal(tt, http.StatusOK, code)
+		assert.Equal(tt, Report{Status: StatusReady, Checks: map[string]Check{
+			"server": {Status: StatusUp},
+			"cache":  {Status: StatusUp},
+		}}, report)
+	})
+
+	t.Run("Not Ready Shutting Down", func(tt *testing.T) {
+		c := NewChecker()
+		c.Add("server", Serving(func() bool { return false }))
+		c.Add("cache", up)
+
+		code, report := ready(tt, c)
+		assert.Equal(tt, http.StatusServiceUnavailable, code)
+		assert.Equal(tt, StatusNotReady, report.Status)
+		assert.Equal(tt, Check{Status: StatusDown, Error: ErrShuttingDown.Error()}, report.Checks["server"])
+		assert.Equal(tt, StatusUp, report.Checks["cache"].Status)
+	})
+
+	t.Run("Not Ready Timeout", func(tt *testing.T) {
+		c := NewChecker()
+		c.Timeout = 10 * time.Millisecond
+		c.Add("slow", func(ctx context.Context) (interface{}, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+
+		code, report := ready(tt, c)
+		assert.Equal(tt, http.StatusServiceUnavailable, code)
+		assert.Equal(tt, Check{Status: StatusDown, Error: context.DeadlineExceeded.Error()}, report.Checks["slow"])
+	})
+}
+
+func TestDatabase(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectPing()
+	_, err = Database(db)(context.Background())
+	assert.NoError(t, err)
+
+	mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+	_, err = Database(db)(context.Background())
+	assert.EqualError(t, err, "connection refused")
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrations(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	m := &migration.Migrator{DB: db, Migrations: []migration.Migration{
+		{Version: 1, Name: "create_users", Up: "CREATE TABLE users"},
+		{Version: 2, Name: "create_posts", Up: "CREATE TABLE posts"},
+	}}
+
+	selectApplied := regexp.QuoteMeta("SELECT version, applied_at FROM schema_migrations")
+	applied := func(versions ...uint) *sqlmock.Rows {
+		rows := sqlmock.NewRows([]string{"version", "applied_at"})
+		for _, version := range versions {
+			rows.AddRow(version, time.Now())
+		}
+
+		return rows
+	}
+
+	mock.ExpectQuery(selectApplied).WillReturnRows(applied(1, 2))
+	details, err := Migrations(m)(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, MigrationStatus{Version: 2}, details)
+
+	mock.ExpectQuery(selectApplied).WillReturnRows(applied(1))
+	details, err = Migrations(m)(context.Background())
+	assert.EqualError(t, err, "1 pending migrations, first 2_create_posts")
+	assert.Equal(t, MigrationStatus{Version: 2, Pending: 1}, details)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
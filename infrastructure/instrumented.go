@@ -0,0 +1,265 @@
+package infrastructure
+
+import (
+	"context"
+	followDomain "microblog/domain/follow/domain"
+	likeDomain "microblog/domain/like/domain"
+	notificationDomain "microblog/domain/notification/domain"
+	postDomain "microblog/domain/post/domain"
+	searchDomain "microblog/domain/search/domain"
+	"microblog/domain/shared/page"
+	userDomain "microblog/domain/user/domain"
+	"microblog/infrastructure/metrics"
+	"time"
+)
+
+// Instrument returns the repositories timing each of their calls.
+func Instrument(repos Repositories, m *metrics.Metrics) Repositories {
+	return Repositories{
+		Users:         &instrumentedUsers{next: repos.Users, m: m},
+		RefreshTokens: &instrumentedRefreshTokens{next: repos.RefreshTokens, m: m},
+		Follows:       &instrumentedFollows{next: repos.Follows, m: m},
+		Posts:         &instrumentedPosts{next: repos.Posts, m: m},
+		Likes:         &instrumentedLikes{next: repos.Likes, m: m},
+		Notifications: &instrumentedNotifications{next: repos.Notifications, m: m},
+		Search:        &instrumentedSearch{next: repos.Search, m: m},
+		Data:          repos.Data,
+	}
+}
+
+// instrumentedUsers times the calls of a user repository.
+type instrumentedUsers struct {
+	next userDomain.Repository
+	m    *metrics.Metrics
+}
+
+func (r *instrumentedUsers) GetAllUser(ctx context.Context, p page.Request) (users []userDomain.User, info page.Info, err error) {
+	defer r.m.ObserveCall("users", "GetAllUser", time.Now(), &err)
+	return r.next.GetAllUser(ctx, p)
+}
+
+func (r *instrumentedUsers) GetOne(ctx context.Context, id uint) (user userDomain.User, err error) {
+	defer r.m.ObserveCall("users", "GetOne", time.Now(), &err)
+	return r.next.GetOne(ctx, id)
+}
+
+func (r *instrumentedUsers) GetByUsername(ctx context.Context, username string) (user userDomain.User, err error) {
+	defer r.m.ObserveCall("users", "GetByUsername", time.Now(), &err)
+	return r.next.GetByUsername(ctx, username)
+}
+
+func (r *instrumentedUsers) GetByEmail(ctx context.Context, email string) (user userDomain.User, err error) {
+	defer r.m.ObserveCall("users", "GetByEmail", time.Now(), &err)
+	return r.next.GetByEmail(ctx, email)
+}
+
+func (r *instrumentedUsers) Create(ctx context.Context, user *userDomain.User) (err error) {
+	defer r.m.ObserveCall("users", "Create", time.Now(), &err)
+	return r.next.Create(ctx, user)
+}
+
+func (r *instrumentedUsers) Update(ctx context.Context, id uint, user userDomain.User) (err error) {
+	defer r.m.ObserveCall("users", "Update", time.Now(), &err)
+	return r.next.Update(ctx, id, user)
+}
+
+func (r *instrumentedUsers) Delete(ctx context.Context, id uint) (err error) {
+	defer r.m.ObserveCall("users", "Delete", time.Now(), &err)
+	return r.next.Delete(ctx, id)
+}
+
+// instrumentedRefreshTokens times the calls of a refresh token repository.
+type instrumentedRefreshTokens struct {
+	next userDomain.RefreshTokenRepository
+	m    *metrics.Metrics
+}
+
+func (r *instrumentedRefreshTokens) Create(ctx context.Context, token *userDomain.RefreshToken) (err error) {
+	defer r.m.ObserveCall("refresh_tokens", "Create", time.Now(), &err)
+	return r.next.Create(ctx, token)
+}
+
+func (r *instrumentedRefreshTokens) GetByHash(ctx context.Context, hash string) (token userDomain.RefreshToken, err error) {
+	defer r.m.ObserveCall("refresh_tokens", "GetByHash", time.Now(), &err)
+	return r.next.GetByHash(ctx, hash)
+}
+
+func (r *instrumentedRefreshTokens) Revoke(ctx context.Context, id uint) (revoked bool, err error) {
+	defer r.m.ObserveCall("refresh_tokens", "Revoke", time.Now(), &err)
+	return r.next.Revoke(ctx, id)
+}
+
+func (r *instrumentedRefreshTokens) RevokeFamily(ctx context.Context, familyID string) (err error) {
+	defer r.m.ObserveCall("refresh_tokens", "RevokeFamily", time.Now(), &err)
+	return r.next.RevokeFamily(ctx, familyID)
+}
+
+func (r *instrumentedRefreshTokens) RevokeAllByUser(ctx context.Context, userID uint) (err error) {
+	defer r.m.ObserveCall("refresh_tokens", "RevokeAllByUser", time.Now(), &err)
+	return r.next.RevokeAllByUser(ctx, userID)
+}
+
+// instrumentedFollows times the calls of a follow repository.
+type instrumentedFollows struct {
+	next followDomain.Repository
+	m    *metrics.Metrics
+}
+
+func (r *instrumentedFollows) Follow(ctx context.Context, follow *followDomain.Follow) (created bool, err error) {
+	defer r.m.ObserveCall("follows", "Follow", time.Now(), &err)
+	return r.next.Follow(ctx, follow)
+}
+
+func (r *instrumentedFollows) Unfollow(ctx context.Context, followerID, followingID uint) (err error) {
+	defer r.m.ObserveCall("follows", "Unfollow", time.Now(), &err)
+	return r.next.Unfollow(ctx, followerID, followingID)
+}
+
+func (r *instrumentedFollows) GetFollowers(ctx context.Context, userID uint, p page.Request) (connections []followDomain.Connection, info page.Info, err error) {
+	defer r.m.ObserveCall("follows", "GetFollowers", time.Now(), &err)
+	return r.next.GetFollowers(ctx, userID, p)
+}
+
+func (r *instrumentedFollows) GetFollowing(ctx context.Context, userID uint, p page.Request) (connections []followDomain.Connection, info page.Info, err error) {
+	defer r.m.ObserveCall("follows", "GetFollowing", time.Now(), &err)
+	return r.next.GetFollowing(ctx, userID, p)
+}
+
+func (r *instrumentedFollows) Counts(ctx context.Context, userID uint) (counts followDomain.Counts, err error) {
+	defer r.m.ObserveCall("follows", "Counts", time.Now(), &err)
+	return r.next.Counts(ctx, userID)
+}
+
+// instrumentedPosts times the calls of a post repository.
+type instrumentedPosts struct {
+	next postDomain.Repository
+	m    *metrics.Metrics
+}
+
+func (r *instrumentedPosts) GetAll(ctx context.Context, p page.Request) (posts []postDomain.Post, info page.Info, err error) {
+	defer r.m.ObserveCall("posts", "GetAll", time.Now(), &err)
+	return r.next.GetAll(ctx, p)
+}
+
+func (r *instrumentedPosts) GetOne(ctx context.Context, id uint) (post postDomain.Post, err error) {
+	defer r.m.ObserveCall("posts", "GetOne", time.Now(), &err)
+	return r.next.GetOne(ctx, id)
+}
+
+func (r *instrumentedPosts) GetByUser(ctx context.Context, userID uint, p page.Request) (posts []postDomain.Post, info page.Info, err error) {
+	defer r.m.ObserveCall("posts", "GetByUser", time.Now(), &err)
+	return r.next.GetByUser(ctx, userID, p)
+}
+
+func (r *instrumentedPosts) GetByTag(ctx context.Context, tag string, p page.Request) (posts []postDomain.Post, info page.Info, err error) {
+	defer r.m.ObserveCall("posts", "GetByTag", time.Now(), &err)
+	return r.next.GetByTag(ctx, tag, p)
+}
+
+func (r *instrumentedPosts) GetTimeline(ctx context.Context, userID uint, p page.Request) (posts []postDomain.Post, info page.Info, err error) {
+	defer r.m.ObserveCall("posts", "GetTimeline", time.Now(), &err)
+	return r.next.GetTimeline(ctx, userID, p)
+}
+
+func (r *instrumentedPosts) GetThread(ctx context.Context, id uint) (thread postDomain.Thread, err error) {
+	defer r.m.ObserveCall("posts", "GetThread", time.Now(), &err)
+	return r.next.GetThread(ctx, id)
+}
+
+func (r *instrumentedPosts) Create(ctx context.Context, post *postDomain.Post) (err error) {
+	defer r.m.ObserveCall("posts", "Create", time.Now(), &err)
+	return r.next.Create(ctx, post)
+}
+
+func (r *instrumentedPosts) Update(ctx context.Context, id uint, post postDomain.Post) (err error) {
+	defer r.m.ObserveCall("posts", "Update", time.Now(), &err)
+	return r.next.Update(ctx, id, post)
+}
+
+func (r *instrumentedPosts) Delete(ctx context.Context, id uint) (err error) {
+	defer r.m.ObserveCall("posts", "Delete", time.Now(), &err)
+	return r.next.Delete(ctx, id)
+}
+
+// instrumentedLikes times the calls of a like repository.
+type instrumentedLikes struct {
+	next likeDomain.Repository
+	m    *metrics.Metrics
+}
+
+func (r *instrumentedLikes) Like(ctx context.Context, like *likeDomain.Like) (created bool, err error) {
+	defer r.m.ObserveCall("likes", "Like", time.Now(), &err)
+	return r.next.Like(ctx, like)
+}
+
+func (r *instrumentedLikes) Unlike(ctx context.Context, userID, postID uint) (err error) {
+	defer r.m.ObserveCall("likes", "Unlike", time.Now(), &err)
+	return r.next.Unlike(ctx, userID, postID)
+}
+
+func (r *instrumentedLikes) GetLikers(ctx context.Context, postID uint, p page.Request) (likers []likeDomain.Liker, info page.Info, err error) {
+	defer r.m.ObserveCall("likes", "GetLikers", time.Now(), &err)
+	return r.next.GetLikers(ctx, postID, p)
+}
+
+func (r *instrumentedLikes) GetLikedPosts(ctx context.Context, userID uint, p page.Request) (posts []likeDomain.LikedPost, info page.Info, err error) {
+	defer r.m.ObserveCall("likes", "GetLikedPosts", time.Now(), &err)
+	return r.next.GetLikedPosts(ctx, userID, p)
+}
+
+func (r *instrumentedLikes) Stats(ctx context.Context, readerID uint, postIDs []uint) (stats map[uint]likeDomain.Stats, err error) {
+	defer r.m.ObserveCall("likes", "Stats", time.Now(), &err)
+	return r.next.Stats(ctx, readerID, postIDs)
+}
+
+// instrumentedNotifications times the calls of a notification repository.
+type instrumentedNotifications struct {
+	next notificationDomain.Repository
+	m    *metrics.Metrics
+}
+
+func (r *instrumentedNotifications) Create(ctx context.Context, notification *notificationDomain.Notification) (err error) {
+	defer r.m.ObserveCall("notifications", "Create", time.Now(), &err)
+	return r.next.Create(ctx, notification)
+}
+
+func (r *instrumentedNotifications) GetAll(ctx context.Context, filter notificationDomain.Filter, p page.Request) (notifications []notificationDomain.Notification, info page.Info, err error) {
+	defer r.m.ObserveCall("notifications", "GetAll", time.Now(), &err)
+	return r.next.GetAll(ctx, filter, p)
+}
+
+func (r *instrumentedNotifications) MarkRead(ctx context.Context, userID, id uint) (err error) {
+	defer r.m.ObserveCall("notifications", "MarkRead", time.Now(), &err)
+	return r.next.MarkRead(ctx, userID, id)
+}
+
+func (r *instrumentedNotifications) MarkAllRead(ctx context.Context, userID uint) (err error) {
+	defer r.m.ObserveCall("notifications", "MarkAllRead", time.Now(), &err)
+	return r.next.MarkAllRead(ctx, userID)
+}
+
+func (r *instrumentedNotifications) CountUnread(ctx context.Context, userID uint) (n int, err error) {
+	defer r.m.ObserveCall("notifications", "CountUnread", time.Now(), &err)
+	return r.next.CountUnread(ctx, userID)
+}
+
+// instrumentedSearch times the calls of a search repository.
+type instrumentedSearch struct {
+	next searchDomain.Repository
+	m    *metrics.Metrics
+}
+
+func (r *instrumentedSearch) SearchPosts(ctx context.Context, q searchDomain.Query, p searchDomain.Request) (hits []searchDomain.Hit, info page.Info, err error) {
+	defer r.m.ObserveCall("search", "SearchPosts", time.Now(), &err)
+	return r.next.SearchPosts(ctx, q, p)
+}
+
+func (r *instrumentedSearch) SearchUsers(ctx context.Context, q string, p searchDomain.Request) (hits []searchDomain.UserHit, info page.Info, err error) {
+	defer r.m.ObserveCall("search", "SearchUsers", time.Now(), &err)
+	return r.next.SearchUsers(ctx, q, p)
+}
+
+func (r *instrumentedSearch) CompleteUsername(ctx context.Context, prefix string, limit int) (profiles []userDomain.Profile, err error) {
+	defer r.m.ObserveCall("search", "CompleteUsername", time.Now(), &err)
+	return r.next.CompleteUsername(ctx, prefix, limit)
+}
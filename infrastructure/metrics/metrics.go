@@ -0,0 +1,132 @@
+// Package metrics exposes the Prometheus metrics of the server: the HTTP
+// requests, the database pool and the repository calls.
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"microblog/domain/shared/errs"
+	"microblog/infrastructure/route"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes the metrics of the microblog.
+const namespace = "microblog"
+
+// Metrics collects the metrics of a server in its own registry.
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	calls    *prometheus.HistogramVec
+}
+
+// New returns the metrics of a server, along with the ones of the Go runtime
+// and of the process.
+func New() *Metrics {
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Number of HTTP requests served, by route pattern and status.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Time taken to serve the HTTP requests, by route pattern and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		calls: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "repository",
+			Name:      "call_duration_seconds",
+			Help:      "Time taken by the repository methods, by repository, method and outcome: ok, rejected or error.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"repository", "method", "outcome"}),
+	}
+
+	m.Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.duration,
+		m.calls,
+	)
+
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{})
+}
+
+// RegisterDB adds the gauges and counters of the connection pool of the
+// database, labelled with its name: open, in use and idle connections, waits
+// and their duration.
+func (m *Metrics) RegisterDB(db *sql.DB, name string) {
+	m.Registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// Middleware counts and times the requests, labelled by the pattern of the
+// route they matched instead of their path so ids do not make new series.
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		labels := prometheus.Labels{
+			"method": r.Method,
+			"route":  route.Pattern(r),
+			"status": strconv.Itoa(status),
+		}
+
+		m.requests.With(labels).Inc()
+		m.duration.With(labels).Observe(time.Since(start).Seconds())
+	})
+}
+
+// ObserveCall records the time taken by a call of a repository method since
+// start, along with its outcome. It is meant to be deferred:
+//
+//	defer m.ObserveCall("posts", "GetOne", time.Now(), &err)
+func (m *Metrics) ObserveCall(repository, method string, start time.Time, err *error) {
+	var e error
+	if err != nil {
+		e = *err
+	}
+
+	m.calls.WithLabelValues(repository, method, outcome(e)).Observe(time.Since(start).Seconds())
+}
+
+// outcome returns ok for a call that succeeded, rejected for one that failed
+// with a domain error, such as a post not found, and error otherwise.
+func outcome(err error) string {
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, errs.ErrNotFound), errors.Is(err, errs.ErrConflict),
+		errors.Is(err, errs.ErrValidation), errors.Is(err, errs.ErrForbidden):
+		return "rejected"
+	default:
+		return "error"
+	}
+}
@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"errors"
+	"microblog/domain/shared/errs"
+	"microblog/infrastructure/route"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics_Middleware(t *testing.T) {
+	m := New()
+
+	posts := chi.NewRouter()
+	posts.Get("/", func(w http.ResponseWriter, r *http.Request) {})
+	posts.Get("/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	router := chi.NewRouter()
+	router.Use(m.Middleware)
+	router.Mount("/posts", posts)
+
+	for _, target := range []string{"/posts/", "/posts/1", "/posts/2", "/unknown/path"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.requests.WithLabelValues("GET", "/posts/", "200")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.requests.WithLabelValues("GET", "/posts/{id}", "404")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.requests.WithLabelValues("GET", route.Unmatched, "404")))
+	assert.Equal(t, 3, testutil.CollectAndCount(m.duration))
+}
+
+func TestMetrics_ObserveCall(t *testing.T) {
+	m := New()
+
+	call := func(err error) {
+		defer m.ObserveCall("posts", "GetOne", time.Now(), &err)
+	}
+
+	call(nil)
+	call(errs.NotFound("post not found"))
+	call(errors.New("connection refused"))
+	call(errors.New("connection refused"))
+
+	// count returns the number of calls observed with the outcome.
+	count := func(outcome string) uint64 {
+		families, err := m.Registry.Gather()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, family := range families {
+			if family.GetName() != "microblog_repository_call_duration_seconds" {
+				continue
+			}
+
+			for _, metric := range family.GetMetric() {
+				for _, label := range metric.GetLabel() {
+					if label.GetName() == "outcome" && label.GetValue() == outcome {
+						return metric.GetHistogram().GetSampleCount()
+					}
+				}
+			}
+		}
+
+		return 0
+	}
+
+	assert.Equal(t, 3, testutil.CollectAndCount(m.calls))
+	assert.Equal(t, uint64(1), count("ok"))
+	assert.Equal(t, uint64(1), count("rejected"))
+	assert.Equal(t, uint64(2), count("error"))
+}
+
+func TestMetrics_RegisterDB(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	m := New()
+	m.RegisterDB(db, "microblog")
+
+	response := httptest.NewRecorder()
+	m.Handler().ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	for _, name := range []string{"open_connections", "in_use_connections", "idle_connections", "wait_count_total", "wait_duration_seconds_total"} {
+		assert.Contains(t, response.Body.String(), `go_sql_`+name+`{db_name="microblog"}`)
+	}
+}
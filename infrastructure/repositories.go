@@ -0,0 +1,70 @@
+package infrastructure
+
+import (
+	followDomain "microblog/domain/follow/domain"
+	memoryFollow "microblog/domain/follow/infraestructure/memory"
+	persistenceFollow "microblog/domain/follow/infraestructure/persistence"
+	likeDomain "microblog/domain/like/domain"
+	memoryLike "microblog/domain/like/infraestructure/memory"
+	persistenceLike "microblog/domain/like/infraestructure/persistence"
+	notificationDomain "microblog/domain/notification/domain"
+	memoryNotification "microblog/domain/notification/infraestructure/memory"
+	persistenceNotification "microblog/domain/notification/infraestructure/persistence"
+	postDomain "microblog/domain/post/domain"
+	memoryPost "microblog/domain/post/infraestructure/memory"
+	persistencePost "microblog/domain/post/infraestructure/persistence"
+	searchDomain "microblog/domain/search/domain"
+	memorySearch "microblog/domain/search/infraestructure/memory"
+	persistenceSearch "microblog/domain/search/infraestructure/persistence"
+	userDomain "microblog/domain/user/domain"
+	memoryUser "microblog/domain/user/infraestructure/memory"
+	persistenceUser "microblog/domain/user/infraestructure/persistence"
+
+	data "microblog/infrastructure/database"
+)
+
+// Repositories are the adapters the API reads and writes through.
+type Repositories struct {
+	Users         userDomain.Repository
+	RefreshTokens userDomain.RefreshTokenRepository
+	Follows       followDomain.Repository
+	Posts         postDomain.Repository
+	Likes         likeDomain.Repository
+	Notifications notificationDomain.Repository
+	Search        searchDomain.Repository
+
+	// Data is the database connection, nil when kept in memory.
+	Data *data.Data
+}
+
+// NewPostgresRepositories returns the repositories backed by the database.
+func NewPostgresRepositories(conn *data.Data) Repositories {
+	return Repositories{
+		Users:         &persistenceUser.UserRepository{Data: conn},
+		RefreshTokens: &persistenceUser.RefreshTokenRepository{Data: conn},
+		Follows:       &persistenceFollow.FollowRepository{Data: conn},
+		Posts:         &persistencePost.PostRepository{Data: conn},
+		Likes:         &persistenceLike.LikeRepository{Data: conn},
+		Notifications: &persistenceNotification.NotificationRepository{Data: conn},
+		Search:        &persistenceSearch.SearchRepository{Data: conn},
+		Data:          conn,
+	}
+}
+
+// NewMemoryRepositories returns empty repositories kept in memory, which
+// are lost when the process exits.
+func NewMemoryRepositories() Repositories {
+	users := memoryUser.NewUserRepository()
+	follows := memoryFollow.NewFollowRepository(users)
+	posts := memoryPost.NewPostRepository(follows)
+
+	return Repositories{
+		Users:         users,
+		RefreshTokens: memoryUser.NewRefreshTokenRepository(),
+		Follows:       follows,
+		Posts:         posts,
+		Likes:         memoryLike.NewLikeRepository(users, posts),
+		Notifications: memoryNotification.NewNotificationRepository(),
+		Search:        memorySearch.NewSearchRepository(users, posts),
+	}
+}
@@ -0,0 +1,36 @@
+// Package route names the route a request matched, so the requests of a
+// route are told apart by its pattern instead of their path.
+package route
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi"
+)
+
+// Unmatched names the route of the requests that match none, so probing
+// random paths does not make new names.
+const Unmatched = "unmatched"
+
+// Pattern returns the pattern of the route the request matched, such as
+// /api/v1/posts/{id}, or Unmatched. It is known once the request is routed.
+func Pattern(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return Unmatched
+	}
+
+	// the patterns of the routes of mounted routers are joined with their
+	// own leading slash, as in /api/v1/posts//.
+	pattern := rctx.RoutePattern()
+	for strings.Contains(pattern, "//") {
+		pattern = strings.Replace(pattern, "//", "/", -1)
+	}
+
+	if pattern == "" || pattern == "/*" {
+		return Unmatched
+	}
+
+	return pattern
+}
@@ -0,0 +1,46 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPattern(t *testing.T) {
+	var pattern string
+	record := func(w http.ResponseWriter, r *http.Request) {}
+
+	posts := chi.NewRouter()
+	posts.Get("/", record)
+	posts.Get("/{id}", record)
+
+	router := chi.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r)
+			pattern = Pattern(r)
+		})
+	})
+	router.Get("/healthz", record)
+	router.Mount("/posts", posts)
+
+	tests := []struct {
+		target   string
+		expected string
+	}{
+		{"/healthz", "/healthz"},
+		{"/posts/", "/posts/"},
+		{"/posts/7", "/posts/{id}"},
+		{"/unknown/path", Unmatched},
+	}
+
+	for _, test := range tests {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, test.target, nil))
+		assert.Equal(t, test.expected, pattern, test.target)
+	}
+
+	assert.Equal(t, Unmatched, Pattern(httptest.NewRequest(http.MethodGet, "/", nil)))
+}
@@ -2,35 +2,134 @@ package infrastructure
 
 import (
 	"github.com/go-chi/chi"
+	v1follow "microblog/domain/follow/application/v1"
+	v1like "microblog/domain/like/application/v1"
+	v1notification "microblog/domain/notification/application/v1"
 	v1post "microblog/domain/post/application/v1"
+	v1search "microblog/domain/search/application/v1"
 	v1user "microblog/domain/user/application/v1"
+	"microblog/infrastructure/auth"
 	"net/http"
 )
 
 // Routes returns post router with each endpoint.
-func RoutesPost(pr *v1post.PostRouter) http.Handler {
+func RoutesPost(pr *v1post.PostRouter, lr *v1like.LikeRouter, tm *auth.TokenManager) http.Handler {
 	newRouter := chi.NewRouter()
 
-	newRouter.Get("/user/{userId}",pr.GetByUserHandler)
-	newRouter.Get("/", pr.GetAllPost)
-	newRouter.Post("/", pr.CreateHandler)
-	newRouter.Get("/{id}", pr.GetOneHandler)
-	newRouter.Put("/{id}", pr.UpdateHandler)
-	newRouter.Delete("/{id}", pr.DeleteHandler)
+	newRouter.Group(func(r chi.Router) {
+		r.Use(auth.OptionalAuthenticator(tm))
+
+		r.Get("/user/{userId}", pr.GetByUserHandler)
+		r.Get("/", pr.GetAllPost)
+		r.Get("/{id}", pr.GetOneHandler)
+		r.Get("/{id}/thread", pr.ThreadHandler)
+	})
+
+	newRouter.Get("/{id}/likes", lr.GetLikersHandler)
+
+	newRouter.Group(func(r chi.Router) {
+		r.Use(auth.Authenticator(tm))
+
+		r.Post("/", pr.CreateHandler)
+		r.Put("/{id}", pr.UpdateHandler)
+		r.Delete("/{id}", pr.DeleteHandler)
+
+		r.Post("/{id}/repost", pr.RepostHandler)
+
+		r.Put("/{id}/like", lr.LikeHandler)
+		r.Delete("/{id}/like", lr.UnlikeHandler)
+	})
+
+	return newRouter
+}
+
+
+// RoutesTimeline returns timeline router with each endpoint.
+func RoutesTimeline(pr *v1post.PostRouter, tm *auth.TokenManager) http.Handler {
+	newRouter := chi.NewRouter()
+
+	newRouter.Use(auth.Authenticator(tm))
+	newRouter.Get("/", pr.TimelineHandler)
 
 	return newRouter
 }
 
+// RoutesTag returns hashtag router with each endpoint.
+func RoutesTag(pr *v1post.PostRouter, tm *auth.TokenManager) http.Handler {
+	newRouter := chi.NewRouter()
+
+	newRouter.Use(auth.OptionalAuthenticator(tm))
+	newRouter.Get("/{tag}/posts", pr.GetByTagHandler)
 
-// Routes returns user router with each endpoint.
-func RoutesUser(ur *v1user.UserRouter) http.Handler {
+	return newRouter
+}
+
+// RoutesSearch returns search router with each endpoint.
+func RoutesSearch(sr *v1search.SearchRouter, tm *auth.TokenManager) http.Handler {
 	newRouter := chi.NewRouter()
 
+	newRouter.Use(auth.OptionalAuthenticator(tm))
+	newRouter.Get("/posts", sr.SearchPostsHandler)
+
+	return newRouter
+}
+
+// RoutesNotification returns notification router with each endpoint.
+func RoutesNotification(nr *v1notification.NotificationRouter, tm *auth.TokenManager) http.Handler {
+	newRouter := chi.NewRouter()
+
+	newRouter.Use(auth.Authenticator(tm))
+	newRouter.Get("/", nr.GetAllHandler)
+	newRouter.Get("/unread-count", nr.UnreadCountHandler)
+	newRouter.Post("/read", nr.MarkAllReadHandler)
+	newRouter.Post("/{id}/read", nr.MarkReadHandler)
+
+	return newRouter
+}
+
+// Routes returns user router with each endpoint, the user search only with sr.
+func RoutesUser(ur *v1user.UserRouter, fr *v1follow.FollowRouter, lr *v1like.LikeRouter, sr *v1search.SearchRouter, tm *auth.TokenManager) http.Handler {
+	newRouter := chi.NewRouter()
+
+	if sr != nil {
+		newRouter.Get("/search", sr.SearchUsersHandler)
+		newRouter.Get("/autocomplete", sr.AutocompleteHandler)
+	}
+
 	newRouter.Get("/", ur.GetAllUser)
 	newRouter.Post("/", ur.CreateHandler)
 	newRouter.Get("/{id}", ur.GetOneHandler)
 	newRouter.Put("/{id}", ur.UpdateHandler)
 	newRouter.Delete("/{id}", ur.DeleteHandler)
 
+	newRouter.Get("/{id}/followers", fr.GetFollowersHandler)
+	newRouter.Get("/{id}/following", fr.GetFollowingHandler)
+	newRouter.With(auth.OptionalAuthenticator(tm)).Get("/{id}/likes", lr.GetLikedPostsHandler)
+
+	newRouter.Group(func(r chi.Router) {
+		r.Use(auth.Authenticator(tm))
+
+		r.Put("/{id}/follow", fr.FollowHandler)
+		r.Delete("/{id}/follow", fr.UnfollowHandler)
+	})
+
+	return newRouter
+}
+
+// RoutesAuth returns authentication router with each endpoint.
+func RoutesAuth(ar *v1user.AuthRouter) http.Handler {
+	newRouter := chi.NewRouter()
+
+	newRouter.Post("/login", ar.LoginHandler)
+	newRouter.Post("/refresh", ar.RefreshHandler)
+	newRouter.Post("/logout", ar.LogoutHandler)
+
+	newRouter.Group(func(r chi.Router) {
+		r.Use(auth.Authenticator(ar.Tokens))
+
+		r.Get("/me", ar.MeHandler)
+		r.Post("/logout-all", ar.LogoutAllHandler)
+	})
+
 	return newRouter
-}
\ No newline at end of file
+}
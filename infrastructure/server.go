@@ -1,11 +1,19 @@
 package infrastructure
 
 import (
+	"context"
+	"errors"
 	"github.com/go-chi/chi/middleware"
 	"log"
-
-	"microblog/infrastructure/database"
+	"microblog/infrastructure/config"
+	"microblog/infrastructure/database/migration"
+	"microblog/infrastructure/health"
+	"microblog/infrastructure/metrics"
+	"microblog/infrastructure/tracing"
+	"net"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-chi/chi"
@@ -14,6 +22,15 @@ import (
 // Server is a base server configuration.
 type Server struct {
 	handler http.Server
+
+	// ready is 1 while the server accepts traffic, it is read atomically.
+	ready int32
+
+	// delay is how long the server keeps serving once not ready on shutdown.
+	delay time.Duration
+
+	mu      sync.Mutex
+	closers []func() error
 }
 
 // ServeHTTP implements the http.Handler interface for the Server type.
@@ -22,35 +39,163 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 // NewApplication initialized a new server with configuration.
-func NewApplication(port string, conn *data.Data) *Server {
+func NewApplication(cfg config.Config, repos Repositories) *Server {
+
+	var m *metrics.Metrics
+	if cfg.Features.Metrics {
+		m = metrics.New()
+		if repos.Data != nil {
+			m.RegisterDB(repos.Data.DB, cfg.Database.Name)
+		}
+
+		repos = Instrument(repos, m)
+	}
 
 	router := chi.NewRouter()
 
 	router.Use(middleware.RequestID)
 	router.Use(middleware.RealIP)
+	router.Use(tracing.Middleware)
+	if m != nil {
+		router.Use(m.Middleware)
+	}
 	router.Use(middleware.Logger)
 	router.Use(middleware.Recoverer)
 
-	router.Mount("/api/v1", New(conn))
+	server := Server{
+		handler: http.Server{
+			Addr:         cfg.Server.Addr(),
+			Handler:      router,
+			ReadTimeout:  cfg.Server.ReadTimeout,
+			WriteTimeout: cfg.Server.WriteTimeout,
+			IdleTimeout:  cfg.Server.IdleTimeout,
+		},
+		delay: cfg.Server.ShutdownDelay,
+	}
+
+	checker := readiness(&server, repos)
+	router.Get("/healthz", health.LivenessHandler)
+	router.Get("/readyz", checker.ReadinessHandler)
+	if m != nil {
+		router.Method(http.MethodGet, "/metrics", m.Handler())
+	}
 
-	server := Server{handler: http.Server{
-		Addr:         ":" + port,
-		Handler:      router,
-		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 10 * time.Second,
-	}}
+	router.Mount("/api/v1", New(repos, cfg))
 
 	return &server
 }
 
-// Close server resources.
-func (s *Server) Close() error {
-	// TODO: add resource closure.
-	return s.handler.Close()
+// readiness returns the checker of what the server needs to serve: itself
+// and, unless kept in memory, the database and its schema.
+func readiness(s *Server, repos Repositories) *health.Checker {
+	checker := health.NewChecker()
+	checker.Add("server", health.Serving(s.Ready))
+
+	if repos.Data == nil {
+		return checker
+	}
+
+	checker.Add("database", health.Database(repos.Data.DB))
+
+	m, err := migration.New(repos.Data.DB)
+	if err != nil {
+		checker.Add("migrations", func(ctx context.Context) (interface{}, error) { return nil, err })
+		return checker
+	}
+
+	checker.Add("migrations", health.Migrations(m))
+
+	return checker
+}
+
+// Ready reports whether the server accepts traffic: it is once serving and
+// until it starts shutting down.
+func (s *Server) Ready() bool {
+	return atomic.LoadInt32(&s.ready) == 1
 }
 
-// Start the server.
-func (s *Server) Start() {
+// OnShutdown registers a function closing a resource once the server is
+// drained, the functions run in the order they were registered.
+func (s *Server) OnShutdown(f func() error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closers = append(s.closers, f)
+}
+
+// Start listens on the address of the server and serves it until it is shut
+// down, which is not an error.
+func (s *Server) Start() error {
+	ln, err := net.Listen("tcp", s.handler.Addr)
+	if err != nil {
+		return err
+	}
+
 	log.Printf("handler running on http://localhost%s", s.handler.Addr)
-	log.Fatal(s.handler.ListenAndServe())
+	return s.Serve(ln)
+}
+
+// Serve serves the connections accepted by the listener until the server is
+// shut down, which is not an error.
+func (s *Server) Serve(ln net.Listener) error {
+	atomic.StoreInt32(&s.ready, 1)
+
+	err := s.handler.Serve(ln)
+	atomic.StoreInt32(&s.ready, 0)
+
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+
+	return err
+}
+
+// Shutdown stops the server gracefully. It is marked not ready first and
+// keeps serving for the shutdown delay, so the readiness probes see it go,
+// then it stops accepting connections and waits for the active ones to go
+// idle. The connections left when the context is done are closed. Last, the
+// registered resources are closed in order, whether draining failed or not.
+func (s *Server) Shutdown(ctx context.Context) error {
+	atomic.StoreInt32(&s.ready, 0)
+
+	if s.delay > 0 {
+		t := time.NewTimer(s.delay)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+		}
+	}
+
+	err := s.handler.Shutdown(ctx)
+	if err != nil {
+		_ = s.handler.Close()
+	}
+
+	return s.release(err)
+}
+
+// Close closes the server and its resources at once, dropping the active
+// connections. Shutdown is the graceful alternative.
+func (s *Server) Close() error {
+	atomic.StoreInt32(&s.ready, 0)
+
+	return s.release(s.handler.Close())
+}
+
+// release closes the registered resources in order and returns err, or else
+// the first error closing them.
+func (s *Server) release(err error) error {
+	s.mu.Lock()
+	closers := s.closers
+	s.closers = nil
+	s.mu.Unlock()
+
+	for _, f := range closers {
+		if cerr := f(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+
+	return err
 }
@@ -0,0 +1,195 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"microblog/infrastructure/health"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// serve serves the handler on a local port and returns the server, its url
+// and the channel receiving what Serve returns.
+func serve(t *testing.T, handler http.Handler) (*Server, string, <-chan error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Server{handler: http.Server{Handler: handler}}
+
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(ln) }()
+
+	url := "http://" + ln.Addr().String()
+	for !s.Ready() {
+		time.Sleep(time.Millisecond)
+	}
+
+	return s, url, done
+}
+
+func TestServer_Shutdown(t *testing.T) {
+	t.Run("Drains Active Requests", func(tt *testing.T) {
+		started, release := make(chan struct{}), make(chan struct{})
+		s, url, done := serve(tt, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+			w.WriteHeader(http.StatusNoContent)
+		}))
+
+		var order []string
+		s.OnShutdown(func() error { order = append(order, "first"); return nil })
+		s.OnShutdown(func() error { order = append(order, "second"); return nil })
+
+		responses := make(chan int, 1)
+		go func() {
+			response, err := http.Get(url)
+			if err != nil {
+				responses <- 0
+				return
+			}
+			response.Body.Close()
+			responses <- response.StatusCode
+		}()
+		<-started
+
+		shutdown := make(chan error, 1)
+		go func() { shutdown <- s.Shutdown(context.Background()) }()
+
+		// not ready anymore while the request is still active.
+		for s.Ready() {
+			time.Sleep(time.Millisecond)
+		}
+		assert.Empty(tt, order)
+		close(release)
+
+		assert.NoError(tt, <-shutdown)
+		assert.Equal(tt, http.StatusNoContent, <-responses)
+		assert.NoError(tt, <-done)
+		assert.Equal(tt, []string{"first", "second"}, order)
+	})
+
+	t.Run("Not Ready During Delay", func(tt *testing.T) {
+		started, release := make(chan struct{}), make(chan struct{})
+
+		var checker *health.Checker
+		mux := http.NewServeMux()
+		mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) { checker.ReadinessHandler(w, r) })
+		mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		s, url, done := serve(tt, mux)
+		s.delay = time.Minute
+		checker = readiness(s, Repositories{})
+
+		responses := make(chan int, 1)
+		go func() {
+			response, err := http.Get(url + "/slow")
+			if err != nil {
+				responses <- 0
+				return
+			}
+			response.Body.Close()
+			responses <- response.StatusCode
+		}()
+		<-started
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		shutdown := make(chan error, 1)
+		go func() { shutdown <- s.Shutdown(ctx) }()
+
+		for s.Ready() {
+			time.Sleep(time.Millisecond)
+		}
+
+		// still serving during the delay, the probe sees the server go.
+		response, err := http.Get(url + "/readyz")
+		if assert.NoError(tt, err) {
+			response.Body.Close()
+			assert.Equal(tt, http.StatusServiceUnavailable, response.StatusCode)
+		}
+
+		select {
+		case err := <-shutdown:
+			tt.Fatalf("shut down before the delay: %v", err)
+		default:
+		}
+
+		// the requests in flight complete during the delay too.
+		close(release)
+		assert.Equal(tt, http.StatusNoContent, <-responses)
+
+		// cutting the delay short drains the server at once.
+		cancel()
+		<-shutdown
+		assert.NoError(tt, <-done)
+	})
+
+	t.Run("Error Drain Timeout", func(tt *testing.T) {
+		started, release := make(chan struct{}), make(chan struct{})
+		defer close(release)
+
+		s, url, done := serve(tt, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+		}))
+
+		closed := false
+		s.OnShutdown(func() error { closed = true; return nil })
+
+		go func() {
+			if response, err := http.Get(url); err == nil {
+				response.Body.Close()
+			}
+		}()
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		err := s.Shutdown(ctx)
+		assert.True(tt, errors.Is(err, context.DeadlineExceeded), "%v", err)
+		assert.True(tt, closed)
+		assert.NoError(tt, <-done)
+	})
+
+	t.Run("Error Closing Resource", func(tt *testing.T) {
+		s, _, done := serve(tt, http.NotFoundHandler())
+
+		failure := errors.New("close failed")
+		closed := false
+		s.OnShutdown(func() error { return failure })
+		s.OnShutdown(func() error { closed = true; return nil })
+
+		err := s.Shutdown(context.Background())
+		assert.True(tt, errors.Is(err, failure), "%v", err)
+		assert.True(tt, closed)
+		assert.NoError(tt, <-done)
+		assert.False(tt, s.Ready())
+	})
+}
+
+func TestServer_Start(t *testing.T) {
+	t.Run("Error Address In Use", func(tt *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			tt.Fatal(err)
+		}
+		defer ln.Close()
+
+		s := &Server{handler: http.Server{Addr: ln.Addr().String(), Handler: http.NotFoundHandler()}}
+
+		assert.Error(tt, s.Start())
+		assert.False(tt, s.Ready())
+	})
+}
@@ -0,0 +1,46 @@
+package tracing
+
+import (
+	"microblog/infrastructure/route"
+	"net/http"
+
+	"github.com/go-chi/chi/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentation names the tracer of this package.
+const instrumentation = "microblog/infrastructure/tracing"
+
+// Middleware traces the requests in a server span continuing the trace of
+// the caller, if any. The span is named after the method and the pattern of
+// the route the request matched, as in GET /api/v1/posts/{id}.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+		ctx, span := otel.Tracer(instrumentation).Start(ctx, "HTTP "+r.Method, trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		pattern := route.Pattern(r)
+		span.SetName(r.Method + " " + pattern)
+		span.SetAttributes(semconv.HTTPServerAttributesFromHTTPRequest("", pattern, r)...)
+		span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(status)...)
+
+		code, description := semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(status, trace.SpanKindServer)
+		if code == codes.Error {
+			span.SetStatus(code, description)
+		}
+	})
+}
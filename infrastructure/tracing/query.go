@@ -0,0 +1,74 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"microblog/domain/shared/errs"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attributes of the query spans beyond the semantic conventions.
+const (
+	// StatementKey is the name of the statement a query runs, such as
+	// selectUserById.
+	StatementKey = attribute.Key("db.statement.name")
+
+	// RowsKey is the number of rows a query read or changed.
+	RowsKey = attribute.Key("db.rows")
+)
+
+// Span is the span of a repository method running queries.
+type Span struct {
+	trace.Span
+}
+
+// Query starts the client span of a repository method, such as
+// PostRepository.GetAll, running the named statement on the database. The
+// queries run with the returned context are traced within it.
+func Query(ctx context.Context, name, statement string) (context.Context, *Span) {
+	ctx, span := otel.Tracer(instrumentation).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL, StatementKey.String(statement)),
+	)
+
+	return ctx, &Span{Span: span}
+}
+
+// Rows records the number of rows the query read or changed.
+func (s *Span) Rows(n int) {
+	s.SetAttributes(RowsKey.Int(n))
+}
+
+// End ends the span, failed when the error the method returns is not a
+// domain error, such as a post not found. It is meant to be deferred with
+// the named error result of the method.
+func (s *Span) End(err *error) {
+	if err != nil && *err != nil {
+		s.RecordError(*err)
+		if !domainError(*err) {
+			s.SetStatus(codes.Error, (*err).Error())
+		}
+	}
+
+	s.Span.End()
+}
+
+// domainError reports whether the error is of a kind the domain reports.
+func domainError(err error) bool {
+	return errors.Is(err, errs.ErrNotFound) || errors.Is(err, errs.ErrConflict) ||
+		errors.Is(err, errs.ErrValidation) || errors.Is(err, errs.ErrForbidden)
+}
+
+// Affected records the number of rows the statement changed, if the driver
+// reports it.
+func (s *Span) Affected(result sql.Result) {
+	if n, err := result.RowsAffected(); err == nil {
+		s.Rows(int(n))
+	}
+}
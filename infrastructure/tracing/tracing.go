@@ -0,0 +1,76 @@
+// Package tracing traces the requests served by the API down to the queries
+// they run, with OpenTelemetry. The trace context of the incoming requests is
+// read from their W3C traceparent and tracestate headers.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+)
+
+// ServiceName names the service in the traces, unless OTEL_SERVICE_NAME
+// says otherwise.
+const ServiceName = "microblog"
+
+// Exporters the spans can be sent with.
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// Shutdown flushes the spans not exported yet and stops the exporter.
+type Shutdown func(ctx context.Context) error
+
+// Setup installs the tracer provider exporting the spans with the named
+// exporter, along with the W3C trace context and baggage propagators. With
+// none, the spans are not recorded. The OTLP exporter sends them over HTTP,
+// to the endpoint given by OTEL_EXPORTER_OTLP_ENDPOINT or else
+// localhost:4318, and stdout writes them to out.
+func Setup(ctx context.Context, name string, out io.Writer) (Shutdown, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+
+	switch name {
+	case "", ExporterNone:
+		return func(ctx context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		exporter, err = otlptracehttp.New(ctx)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(out))
+	default:
+		return nil, fmt.Errorf("unknown exporter %q", name)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceNameKey.String(ServiceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
@@ -0,0 +1,150 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"microblog/domain/shared/errs"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// record installs a tracer provider recording the spans it ends.
+func record(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	return recorder
+}
+
+// attributes returns the attributes of the span by key.
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	byKey := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		byKey[kv.Key] = kv.Value
+	}
+
+	return byKey
+}
+
+func TestSetup(t *testing.T) {
+	t.Run("Disabled", func(tt *testing.T) {
+		shutdown, err := Setup(context.Background(), ExporterNone, nil)
+		assert.NoError(tt, err)
+		assert.NoError(tt, shutdown(context.Background()))
+	})
+
+	t.Run("Stdout", func(tt *testing.T) {
+		previous := otel.GetTracerProvider()
+		defer otel.SetTracerProvider(previous)
+
+		var out bytes.Buffer
+		shutdown, err := Setup(context.Background(), ExporterStdout, &out)
+		assert.NoError(tt, err)
+
+		_, span := otel.Tracer("test").Start(context.Background(), "work")
+		span.End()
+
+		assert.NoError(tt, shutdown(context.Background()))
+		assert.Contains(tt, out.String(), `"Name":"work"`)
+		assert.Contains(tt, out.String(), `"Value":"microblog"`)
+	})
+
+	t.Run("Error Unknown Exporter", func(tt *testing.T) {
+		_, err := Setup(context.Background(), "zipkin", nil)
+		assert.Error(tt, err)
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	recorder := record(t)
+
+	router := chi.NewRouter()
+	router.Use(Middleware)
+	router.Get("/posts/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, span := otel.Tracer("test").Start(r.Context(), "query")
+		span.End()
+
+		if chi.URLParam(r, "id") == "0" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/posts/7", nil)
+	request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), request)
+
+	spans := recorder.Ended()
+	if !assert.Len(t, spans, 2) {
+		return
+	}
+
+	query, server := spans[0], spans[1]
+	assert.Equal(t, "GET /posts/{id}", server.Name())
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent().SpanID().String())
+	assert.Equal(t, server.SpanContext().SpanID(), query.Parent().SpanID())
+	assert.Equal(t, "/posts/{id}", attributes(server)[semconv.HTTPRouteKey].AsString())
+	assert.Equal(t, int64(http.StatusOK), attributes(server)[semconv.HTTPStatusCodeKey].AsInt64())
+	assert.Equal(t, codes.Unset, server.Status().Code)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/posts/0", nil))
+
+	spans = recorder.Ended()
+	server = spans[len(spans)-1]
+	assert.False(t, server.Parent().IsValid())
+	assert.Equal(t, codes.Error, server.Status().Code)
+}
+
+func TestQuery(t *testing.T) {
+	recorder := record(t)
+
+	run := func(rows int, err error) error {
+		_, span := Query(context.Background(), "PostRepository.GetOne", "selectPostById")
+		defer span.End(&err)
+
+		span.Rows(rows)
+
+		return err
+	}
+
+	_ = run(1, nil)
+	_ = run(0, errs.NotFound("post not found"))
+	_ = run(0, errors.New("connection refused"))
+
+	spans := recorder.Ended()
+	if !assert.Len(t, spans, 3) {
+		return
+	}
+
+	for _, span := range spans {
+		assert.Equal(t, "PostRepository.GetOne", span.Name())
+		assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+		assert.Equal(t, "selectPostById", attributes(span)[StatementKey].AsString())
+		assert.Equal(t, "postgresql", attributes(span)[semconv.DBSystemKey].AsString())
+	}
+
+	assert.Equal(t, int64(1), attributes(spans[0])[RowsKey].AsInt64())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Len(t, spans[1].Events(), 1)
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+	assert.Equal(t, codes.Error, spans[2].Status().Code)
+}
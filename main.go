@@ -1,12 +1,20 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
 	_ "github.com/joho/godotenv/autoload"
 	log "github.com/sirupsen/logrus"
 	"microblog/infrastructure"
+	"microblog/infrastructure/config"
 	data "microblog/infrastructure/database"
+	"microblog/infrastructure/database/migration"
+	"microblog/infrastructure/tracing"
 	"os"
 	"os/signal"
+	"syscall"
 )
 
 func main() {
@@ -21,29 +29,130 @@ func main() {
 		}
 	}()
 
-	// connection to the database.
-	db := data.New()
-	if err := db.DB.Ping(); err != nil {
-		log.Fatal(err)
+	cfg, args, err := config.Load(os.Args[1:])
+	if err != nil {
+		// the usage is printed on -help, which is no failure.
+		if errors.Is(err, flag.ErrHelp) {
+			err = nil
+		}
+
+		return
+	}
+
+	// manage the database schema instead of starting the server.
+	if len(args) > 0 && args[0] == "migrate" {
+		if err = config.ValidateDatabase(cfg.Database); err != nil {
+			return
+		}
+
+		err = migrate(cfg.Database, args[1:])
+		return
+	}
+
+	if len(args) > 0 {
+		err = fmt.Errorf("unknown command %q", args[0])
+		return
+	}
+
+	if err = cfg.Validate(); err != nil {
+		return
+	}
+
+	repos, err := repositories(cfg)
+	if err != nil {
+		return
 	}
 
-	conn := &data.Data{
-		DB: db.DB,
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing.Exporter, os.Stdout)
+	if err != nil {
+		return
 	}
 
-	DaemonPort := os.Getenv("DAEMON_PORT")
-	serv := infrastructure.NewApplication(DaemonPort, conn)
+	delay, timeout := cfg.Server.ShutdownDelay, cfg.Server.ShutdownTimeout
+	serv := infrastructure.NewApplication(cfg, repos)
+
+	// the database pool is closed once the requests using it are drained,
+	// then the spans left are exported.
+	serv.OnShutdown(data.Close)
+	serv.OnShutdown(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		return shutdownTracing(ctx)
+	})
 
 	// start the server.
-	go serv.Start()
+	done := make(chan error, 1)
+	go func() { done <- serv.Start() }()
 
-	// Wait for an in interrupt.
-	// If you ask about <- look here https://tour.golang.org/concurrency/2
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt)
-	<-c
+	// Wait for an interrupt or a termination, or for the server to fail.
+	c := make(chan os.Signal, 2)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+
+	select {
+	case err = <-done:
+		_ = serv.Close()
+		return
+	case s := <-c:
+		log.WithFields(log.Fields{
+			"signal":  s.String(),
+			"delay":   delay.String(),
+			"timeout": timeout.String(),
+		}).Info("shutting down")
+	}
+
+	// Attempt a graceful shutdown, a second signal cuts the drain short.
+	ctx, cancel := context.WithTimeout(context.Background(), delay+timeout)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-c:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if err = serv.Shutdown(ctx); err != nil {
+		return
+	}
+
+	err = <-done
+}
+
+// repositories returns the repositories of the configured storage backend.
+func repositories(cfg config.Config) (infrastructure.Repositories, error) {
+	if cfg.Storage == config.StorageMemory {
+		log.Warn("using in-memory storage, data is lost on exit")
+		return infrastructure.NewMemoryRepositories(), nil
+	}
+
+	// connection to the database, the pending migrations are applied.
+	db, err := data.New(cfg.Database)
+	if err != nil {
+		return infrastructure.Repositories{}, err
+	}
+
+	if err := db.DB.Ping(); err != nil {
+		return infrastructure.Repositories{}, err
+	}
+
+	return infrastructure.NewPostgresRepositories(db), nil
+}
+
+// migrate runs the migrate command: up, down, to <version> or status.
+func migrate(cfg config.Database, args []string) error {
+	db, err := data.Open(cfg)
+	if err != nil {
+		return err
+	}
+
+	defer db.Close()
+
+	m, err := migration.New(db)
+	if err != nil {
+		return err
+	}
 
-	// Attempt a graceful shutdown.
-	_ = serv.Close()
-	_ = data.Close()
+	return migration.Run(context.Background(), m, args, os.Stdout)
 }
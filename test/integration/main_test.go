@@ -4,6 +4,7 @@ import (
 	"github.com/joho/godotenv"
 	"log"
 	"microblog/infrastructure"
+	"microblog/infrastructure/config"
 	data "microblog/infrastructure/database"
 	"os"
 	"testing"
@@ -33,11 +34,15 @@ func testMain(m *testing.M) int {
 		log.Fatalf("Error getting env %v\n", err)
 	}
 
+	cfg, _, err := config.Load(nil)
+	if err != nil {
+		log.Fatalf("Error getting config %v\n", err)
+	}
+
 	dbc := testdb.Open()
 	defer data.Close()
 
-	port := os.Getenv("DAEMON_PORT")
-	s = infrastructure.NewApplication(port, dbc)
+	s = infrastructure.NewApplication(cfg, infrastructure.NewPostgresRepositories(dbc))
 	d = dbc
 
 	return m.Run()
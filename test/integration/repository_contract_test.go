@@ -0,0 +1,181 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	followDomain "microblog/domain/follow/domain"
+	followContract "microblog/domain/follow/domain/contract"
+	persistenceFollow "microblog/domain/follow/infraestructure/persistence"
+	likeContract "microblog/domain/like/domain/contract"
+	persistenceLike "microblog/domain/like/infraestructure/persistence"
+	notificationContract "microblog/domain/notification/domain/contract"
+	persistenceNotification "microblog/domain/notification/infraestructure/persistence"
+	postDomain "microblog/domain/post/domain"
+	postContract "microblog/domain/post/domain/contract"
+	persistencePost "microblog/domain/post/infraestructure/persistence"
+	searchContract "microblog/domain/search/domain/contract"
+	persistenceSearch "microblog/domain/search/infraestructure/persistence"
+	"microblog/domain/user/domain"
+	userContract "microblog/domain/user/domain/contract"
+	persistenceUser "microblog/domain/user/infraestructure/persistence"
+	testDB "microblog/infrastructure/database/test"
+	"testing"
+)
+
+// emptyDatabase truncates the test database tables before a contract case.
+func emptyDatabase(t *testing.T) {
+	if err := testDB.Truncate(d.DB); err != nil {
+		t.Fatalf("error truncating test database tables: %v", err)
+	}
+}
+
+// newUser returns a function that stores a new user and returns its id.
+func newUser() func(t *testing.T) uint {
+	users := &persistenceUser.UserRepository{Data: d}
+	n := 0
+
+	return func(t *testing.T) uint {
+		n++
+		u := userContract.NewUser(fmt.Sprintf("user.%d", n))
+		if err := users.Create(context.Background(), &u); err != nil {
+			t.Fatalf("error creating user: %v", err)
+		}
+
+		return u.ID
+	}
+}
+
+func TestIntegration_UserRepositoryContract(t *testing.T) {
+	defer emptyDatabase(t)
+
+	userContract.TestRepository(t, func(t *testing.T) domain.Repository {
+		emptyDatabase(t)
+		return &persistenceUser.UserRepository{Data: d}
+	})
+}
+
+func TestIntegration_RefreshTokenRepositoryContract(t *testing.T) {
+	defer emptyDatabase(t)
+
+	userContract.TestRefreshTokenRepository(t, func(t *testing.T) userContract.RefreshTokenHarness {
+		emptyDatabase(t)
+		return userContract.RefreshTokenHarness{
+			Repository: &persistenceUser.RefreshTokenRepository{Data: d},
+			NewUser:    newUser(),
+		}
+	})
+}
+
+func TestIntegration_FollowRepositoryContract(t *testing.T) {
+	defer emptyDatabase(t)
+
+	followContract.TestRepository(t, func(t *testing.T) followContract.Harness {
+		emptyDatabase(t)
+		return followContract.Harness{
+			Repository: &persistenceFollow.FollowRepository{Data: d},
+			NewUser:    newUser(),
+		}
+	})
+}
+
+func TestIntegration_PostRepositoryContract(t *testing.T) {
+	defer emptyDatabase(t)
+
+	follows := &persistenceFollow.FollowRepository{Data: d}
+	postContract.TestRepository(t, func(t *testing.T) postContract.Harness {
+		emptyDatabase(t)
+		return postContract.Harness{
+			Repository: &persistencePost.PostRepository{Data: d},
+			NewUser:    newUser(),
+			Follow: func(t *testing.T, followerID, followingID uint) {
+				f := followDomain.Follow{FollowerID: followerID, FollowingID: followingID}
+				if _, err := follows.Follow(context.Background(), &f); err != nil {
+					t.Fatalf("error following: %v", err)
+				}
+			},
+		}
+	})
+}
+
+func TestIntegration_LikeRepositoryContract(t *testing.T) {
+	defer emptyDatabase(t)
+
+	posts := &persistencePost.PostRepository{Data: d}
+	likeContract.TestRepository(t, func(t *testing.T) likeContract.Harness {
+		emptyDatabase(t)
+		return likeContract.Harness{
+			Repository: &persistenceLike.LikeRepository{Data: d},
+			NewUser:    newUser(),
+			NewPost: func(t *testing.T, userID uint) uint {
+				p := postDomain.Post{Body: "Lorem ipsum dolor sit amet.", UserID: userID}
+				if err := posts.Create(context.Background(), &p); err != nil {
+					t.Fatalf("error creating post: %v", err)
+				}
+
+				return p.ID
+			},
+			CreatePost: func(t *testing.T, post *postDomain.Post) {
+				if err := posts.Create(context.Background(), post); err != nil {
+					t.Fatalf("error creating post: %v", err)
+				}
+			},
+			DeletePost: func(t *testing.T, postID uint) {
+				if err := posts.Delete(context.Background(), postID); err != nil {
+					t.Fatalf("error deleting post: %v", err)
+				}
+			},
+		}
+	})
+}
+
+func TestIntegration_NotificationRepositoryContract(t *testing.T) {
+	defer emptyDatabase(t)
+
+	posts := &persistencePost.PostRepository{Data: d}
+	notificationContract.TestRepository(t, func(t *testing.T) notificationContract.Harness {
+		emptyDatabase(t)
+		return notificationContract.Harness{
+			Repository: &persistenceNotification.NotificationRepository{Data: d},
+			NewUser:    newUser(),
+			NewPost: func(t *testing.T, userID uint) uint {
+				p := postDomain.Post{Body: "Lorem ipsum dolor sit amet.", UserID: userID}
+				if err := posts.Create(context.Background(), &p); err != nil {
+					t.Fatalf("error creating post: %v", err)
+				}
+
+				return p.ID
+			},
+		}
+	})
+}
+
+func TestIntegration_SearchRepositoryContract(t *testing.T) {
+	defer emptyDatabase(t)
+
+	users := &persistenceUser.UserRepository{Data: d}
+	posts := &persistencePost.PostRepository{Data: d}
+	searchContract.TestRepository(t, func(t *testing.T) searchContract.Harness {
+		emptyDatabase(t)
+		return searchContract.Harness{
+			Repository: &persistenceSearch.SearchRepository{Data: d},
+			NewUser:    newUser(),
+			NewProfile: func(t *testing.T, username, firstName, lastName string) uint {
+				u := userContract.NewUser(username)
+				u.FirstName, u.LastName = firstName, lastName
+				if err := users.Create(context.Background(), &u); err != nil {
+					t.Fatalf("error creating user: %v", err)
+				}
+
+				return u.ID
+			},
+			NewPost: func(t *testing.T, userID uint, body string) uint {
+				p := postDomain.Post{Body: body, UserID: userID}
+				if err := posts.Create(context.Background(), &p); err != nil {
+					t.Fatalf("error creating post: %v", err)
+				}
+
+				return p.ID
+			},
+		}
+	})
+}
@@ -15,6 +15,12 @@ import (
 
 type Map map[string]interface{}
 
+// userPage is the body of a page of users.
+type userPage struct {
+	Items      []domain.User `json:"items"`
+	NextCursor string        `json:"next_cursor"`
+}
+
 func dataUSer() []domain.User {
 	now := time.Now().Truncate(time.Second).Truncate(time.Millisecond).Truncate(time.Microsecond)
 
@@ -57,14 +63,13 @@ func TestIntegration_GetAllUser(t *testing.T) {
 			tt.Errorf("expected status code: %v, got status code: %v", e, a)
 		}
 
-		var users []domain.User
-		var valueResponse = Map{"user": users}
-		if err := json.Unmarshal([]byte(w.Body.String()), &valueResponse); err != nil {
+		var users userPage
+		if err := json.Unmarshal([]byte(w.Body.String()), &users); err != nil {
 			tt.Errorf("error decoding response body: %v", err)
 		}
 
-		if len(users) > 0 {
-			tt.Errorf("expected no lists to be returned, got %v lists", len(users))
+		if len(users.Items) > 0 {
+			tt.Errorf("expected no lists to be returned, got %v lists", len(users.Items))
 		}
 	})
 
@@ -80,7 +85,7 @@ func TestIntegration_GetAllUser(t *testing.T) {
 			tt.Fatalf("error seeding users: %v", err)
 		}
 
-		req, err := http.NewRequest(http.MethodGet, "/api/v1/users/", nil)
+		req, err := http.NewRequest(http.MethodGet, "/api/v1/users/?order=asc", nil)
 		if err != nil {
 			tt.Errorf("error creating request: %v", err)
 		}
@@ -92,12 +97,12 @@ func TestIntegration_GetAllUser(t *testing.T) {
 			tt.Errorf("expected status code: %v, got status code: %v", e, a)
 		}
 
-		var users []domain.User
+		var users userPage
 		if err := json.NewDecoder(w.Body).Decode(&users); err != nil {
 			tt.Errorf("error decoding response body: %v", err)
 		}
 
-		if d := cmp.Diff(expectedUsers[0].ID, users[0].ID); d != "" {
+		if d := cmp.Diff(expectedUsers[0].ID, users.Items[0].ID); d != "" {
 			tt.Errorf("unexpected difference in response body:\n%v", d)
 		}
 	})